@@ -0,0 +1,62 @@
+package key
+
+import (
+	"github.com/dynamicgo/injector"
+	"github.com/dynamicgo/xerrors"
+)
+
+// AggregateProvider the provider aggregate the signatures of many keys into one signature, e.g. bls
+type AggregateProvider interface {
+	Provider
+	Aggregate(sigs [][]byte) ([]byte, error)                            // aggregated signature
+	FastAggregateVerify(pubkeys [][]byte, sig []byte, hash []byte) bool // all keys signed the same hash
+	AggregateVerify(pubkeys [][]byte, sig []byte, hashes [][]byte) bool // each key signed its own hash
+}
+
+func getAggregateProvider(driver string) (AggregateProvider, error) {
+	var provider Provider
+	if !injector.Get(driver, &provider) {
+		return nil, xerrors.Wrapf(ErrDriver, "unknown driver %s", driver)
+	}
+
+	aggregateProvider, ok := provider.(AggregateProvider)
+
+	if !ok {
+		return nil, xerrors.Wrapf(ErrAggregate, "driver %s", driver)
+	}
+
+	return aggregateProvider, nil
+}
+
+// Aggregate aggregate the signatures into one signature
+func Aggregate(driver string, sigs [][]byte) ([]byte, error) {
+	provider, err := getAggregateProvider(driver)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Aggregate(sigs)
+}
+
+// FastAggregateVerify verify the aggregated signature of the same hash signed by all the public keys
+func FastAggregateVerify(driver string, pubkeys [][]byte, sig []byte, hash []byte) (bool, error) {
+	provider, err := getAggregateProvider(driver)
+
+	if err != nil {
+		return false, err
+	}
+
+	return provider.FastAggregateVerify(pubkeys, sig, hash), nil
+}
+
+// AggregateVerify verify the aggregated signature of the hashes each signed by the public key of the same index
+func AggregateVerify(driver string, pubkeys [][]byte, sig []byte, hashes [][]byte) (bool, error) {
+	provider, err := getAggregateProvider(driver)
+
+	if err != nil {
+		return false, err
+	}
+
+	return provider.AggregateVerify(pubkeys, sig, hashes), nil
+}
@@ -0,0 +1,121 @@
+package key
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/dynamicgo/injector"
+	"github.com/dynamicgo/xerrors"
+)
+
+// batchSize the number of items verified by one worker at a time
+const batchSize = 64
+
+// VerifyItem one signature to be verified by BatchVerify
+type VerifyItem struct {
+	PubKey []byte // public key bytes, required, the signer is never recovered
+	Sig    []byte // signature bytes
+	Hash   []byte // hashed message
+}
+
+// BatchProvider the provider verify many signatures at once faster than one by one,
+// e.g. schnorr batch verification
+type BatchProvider interface {
+	Provider
+	BatchVerify(items []*VerifyItem) []bool // per item results
+}
+
+// BatchVerify verify signatures concurrently, return per item results and true if all signatures are valid
+func BatchVerify(driver string, items []*VerifyItem) ([]bool, bool, error) {
+	var provider Provider
+	if !injector.Get(driver, &provider) {
+		return nil, false, xerrors.Wrapf(ErrDriver, "unknown driver %s", driver)
+	}
+
+	for i, item := range items {
+		if item == nil {
+			return nil, false, xerrors.Wrapf(ErrVerifyItem, "item %d is nil", i)
+		}
+
+		if len(item.PubKey) == 0 {
+			return nil, false, xerrors.Wrapf(ErrVerifyItem, "item %d public key is empty", i)
+		}
+	}
+
+	results := make([]bool, len(items))
+
+	chunks := make(chan int)
+
+	var wg sync.WaitGroup
+
+	workers := runtime.GOMAXPROCS(0)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for start := range chunks {
+				end := start + batchSize
+
+				if end > len(items) {
+					end = len(items)
+				}
+
+				verifyChunk(provider, items[start:end], results[start:end])
+			}
+		}()
+	}
+
+	for start := 0; start < len(items); start += batchSize {
+		chunks <- start
+	}
+
+	close(chunks)
+
+	wg.Wait()
+
+	valid := true
+
+	for _, result := range results {
+		valid = valid && result
+	}
+
+	return results, valid, nil
+}
+
+// verifyChunk verify the chunk with the batch provider if possible, a panic of the provider only fails
+// the item which caused it, the chunk is verified one by one if the batch verification panics
+func verifyChunk(provider Provider, items []*VerifyItem, results []bool) {
+	if batchProvider, ok := provider.(BatchProvider); ok {
+		if batchResults, ok := batchVerify(batchProvider, items); ok && len(batchResults) == len(items) {
+			copy(results, batchResults)
+			return
+		}
+	}
+
+	for i, item := range items {
+		results[i] = verify(provider, item)
+	}
+}
+
+func batchVerify(provider BatchProvider, items []*VerifyItem) (results []bool, ok bool) {
+	defer func() {
+		if recover() != nil {
+			results, ok = nil, false
+		}
+	}()
+
+	return provider.BatchVerify(items), true
+}
+
+func verify(provider Provider, item *VerifyItem) (valid bool) {
+	defer func() {
+		if recover() != nil {
+			valid = false
+		}
+	}()
+
+	return provider.Verify(item.PubKey, item.Sig, item.Hash)
+}
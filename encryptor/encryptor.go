@@ -2,4 +2,6 @@ package encryptor
 
 import (
 	_ "github.com/laplacenetwork/key/encryptor/web3" //
+	_ "github.com/laplacenetwork/key/shamir"         //
+	_ "github.com/laplacenetwork/key/shamir/slip39"  //
 )
@@ -0,0 +1,378 @@
+// Package hd bip32 hierarchical deterministic key implement
+package hd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/ecdsax"
+	"github.com/laplacenetwork/key/internal/hash160"
+	"github.com/laplacenetwork/key/internal/secp256k1"
+)
+
+// Errors
+var (
+	ErrSeed           = errors.New("invalid seed length")
+	ErrHardened       = errors.New("can't derive hardened child from public extended key")
+	ErrChild          = errors.New("invalid child key, use next index")
+	ErrPrivate        = errors.New("extended key is not private")
+	ErrDepth          = errors.New("derivation depth overflow")
+	ErrExtendedKey    = errors.New("invalid extended key")
+	ErrUnknownVersion = errors.New("unknown extended key version")
+)
+
+// HardenedKeyStart the first hardened child index
+const HardenedKeyStart = uint32(0x80000000)
+
+const (
+	minSeedLen       = 16
+	maxSeedLen       = 64
+	serializedKeyLen = 78
+)
+
+var masterHMACKey = []byte("Bitcoin seed")
+
+// Versions extended key serialization version bytes
+type Versions struct {
+	Private [4]byte // xprv version
+	Public  [4]byte // xpub version
+}
+
+// Known versions
+var (
+	MainNet = Versions{
+		Private: [4]byte{0x04, 0x88, 0xad, 0xe4},
+		Public:  [4]byte{0x04, 0x88, 0xb2, 0x1e},
+	}
+
+	TestNet = Versions{
+		Private: [4]byte{0x04, 0x35, 0x83, 0x94},
+		Public:  [4]byte{0x04, 0x35, 0x87, 0xcf},
+	}
+
+	knownVersions = []Versions{MainNet, TestNet}
+)
+
+// ExtendedKey bip32 extended private or public key
+type ExtendedKey struct {
+	versions  Versions
+	depth     byte
+	parentFP  [4]byte
+	index     uint32
+	chainCode []byte
+	key       []byte // 32 bytes private key or 33 bytes compressed public key
+	private   bool
+}
+
+// NewMaster create master extended private key from seed with mainnet versions
+func NewMaster(seed []byte) (*ExtendedKey, error) {
+	return NewMasterWithVersions(seed, MainNet)
+}
+
+// NewMasterWithVersions create master extended private key from seed
+func NewMasterWithVersions(seed []byte, versions Versions) (*ExtendedKey, error) {
+	if len(seed) < minSeedLen || len(seed) > maxSeedLen {
+		return nil, xerrors.Wrapf(ErrSeed, "seed length %d out of range [%d,%d]", len(seed), minSeedLen, maxSeedLen)
+	}
+
+	hasher := hmac.New(sha512.New, masterHMACKey)
+
+	hasher.Write(seed)
+
+	sum := hasher.Sum(nil)
+
+	k := new(big.Int).SetBytes(sum[:32])
+
+	if k.Sign() == 0 || k.Cmp(secp256k1.SECP256K1().Params().N) >= 0 {
+		return nil, xerrors.Wrapf(ErrChild, "master key out of range")
+	}
+
+	return &ExtendedKey{
+		versions:  versions,
+		chainCode: sum[32:],
+		key:       sum[:32],
+		private:   true,
+	}, nil
+}
+
+// IsPrivate check if extended key contains private key
+func (key *ExtendedKey) IsPrivate() bool {
+	return key.private
+}
+
+// Depth derivation depth, 0 for master key
+func (key *ExtendedKey) Depth() byte {
+	return key.depth
+}
+
+// Index child index of this key
+func (key *ExtendedKey) Index() uint32 {
+	return key.index
+}
+
+// ParentFingerprint parent key fingerprint
+func (key *ExtendedKey) ParentFingerprint() uint32 {
+	return binary.BigEndian.Uint32(key.parentFP[:])
+}
+
+// Fingerprint key fingerprint, the first 4 bytes of hash160(compressed pubkey)
+func (key *ExtendedKey) Fingerprint() uint32 {
+	return binary.BigEndian.Uint32(hash160.Hash160(key.compressedPubKey())[:4])
+}
+
+// ChainCode chain code byte array
+func (key *ExtendedKey) ChainCode() []byte {
+	return append([]byte(nil), key.chainCode...)
+}
+
+// PriKey private key byte array
+func (key *ExtendedKey) PriKey() ([]byte, error) {
+	if !key.private {
+		return nil, ErrPrivate
+	}
+
+	return append([]byte(nil), key.key...), nil
+}
+
+// PubKey uncompressed public key byte array, same format as key.Key PubKey
+func (key *ExtendedKey) PubKey() []byte {
+	x, y := key.pubKeyPoint()
+
+	return ecdsax.PublicKeyBytes(&ecdsa.PublicKey{Curve: secp256k1.SECP256K1(), X: x, Y: y})
+}
+
+func (key *ExtendedKey) pubKeyPoint() (x, y *big.Int) {
+	if key.private {
+		return secp256k1.SECP256K1().ScalarBaseMult(key.key)
+	}
+
+	x, y, _ = secp256k1.DecompressPubkey(key.key)
+
+	return
+}
+
+func (key *ExtendedKey) compressedPubKey() []byte {
+	if !key.private {
+		return key.key
+	}
+
+	return secp256k1.CompressPubkey(key.pubKeyPoint())
+}
+
+// Neuter get extended public key
+func (key *ExtendedKey) Neuter() *ExtendedKey {
+	if !key.private {
+		return key
+	}
+
+	return &ExtendedKey{
+		versions:  key.versions,
+		depth:     key.depth,
+		parentFP:  key.parentFP,
+		index:     key.index,
+		chainCode: key.chainCode,
+		key:       key.compressedPubKey(),
+		private:   false,
+	}
+}
+
+// Child derive child extended key, index >= HardenedKeyStart derive hardened child
+func (key *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if key.depth == 0xff {
+		return nil, ErrDepth
+	}
+
+	hardened := index >= HardenedKeyStart
+
+	if hardened && !key.private {
+		return nil, ErrHardened
+	}
+
+	data := make([]byte, 37)
+
+	if hardened {
+		copy(data[1:], key.key)
+	} else {
+		copy(data, key.compressedPubKey())
+	}
+
+	binary.BigEndian.PutUint32(data[33:], index)
+
+	hasher := hmac.New(sha512.New, key.chainCode)
+
+	hasher.Write(data)
+
+	sum := hasher.Sum(nil)
+
+	curve := secp256k1.SECP256K1()
+
+	il := new(big.Int).SetBytes(sum[:32])
+
+	if il.Cmp(curve.Params().N) >= 0 {
+		return nil, xerrors.Wrapf(ErrChild, "child %d IL out of range", index)
+	}
+
+	var childKey []byte
+
+	if key.private {
+		k := new(big.Int).SetBytes(key.key)
+
+		k.Add(k, il)
+		k.Mod(k, curve.Params().N)
+
+		if k.Sign() == 0 {
+			return nil, xerrors.Wrapf(ErrChild, "child %d private key is zero", index)
+		}
+
+		childKey = make([]byte, 32)
+
+		kBytes := k.Bytes()
+
+		copy(childKey[32-len(kBytes):], kBytes)
+
+	} else {
+		ilx, ily := curve.ScalarBaseMult(sum[:32])
+
+		px, py := key.pubKeyPoint()
+
+		x, y := curve.Add(ilx, ily, px, py)
+
+		if x.Sign() == 0 && y.Sign() == 0 {
+			return nil, xerrors.Wrapf(ErrChild, "child %d public key is infinity", index)
+		}
+
+		childKey = secp256k1.CompressPubkey(x, y)
+	}
+
+	child := &ExtendedKey{
+		versions:  key.versions,
+		depth:     key.depth + 1,
+		index:     index,
+		chainCode: sum[32:],
+		key:       childKey,
+		private:   key.private,
+	}
+
+	copy(child.parentFP[:], hash160.Hash160(key.compressedPubKey())[:4])
+
+	return child, nil
+}
+
+// String base58 serialized extended key, xprv... or xpub...
+func (key *ExtendedKey) String() string {
+	buff := make([]byte, 0, serializedKeyLen+4)
+
+	if key.private {
+		buff = append(buff, key.versions.Private[:]...)
+	} else {
+		buff = append(buff, key.versions.Public[:]...)
+	}
+
+	buff = append(buff, key.depth)
+	buff = append(buff, key.parentFP[:]...)
+
+	var index [4]byte
+
+	binary.BigEndian.PutUint32(index[:], key.index)
+
+	buff = append(buff, index[:]...)
+	buff = append(buff, key.chainCode...)
+
+	if key.private {
+		buff = append(buff, 0x00)
+	}
+
+	buff = append(buff, key.key...)
+
+	buff = append(buff, checksum(buff)...)
+
+	return base58.Encode(buff)
+}
+
+// Parse parse base58 serialized extended key
+func Parse(serialized string) (*ExtendedKey, error) {
+	buff := base58.Decode(serialized)
+
+	if len(buff) != serializedKeyLen+4 {
+		return nil, xerrors.Wrapf(ErrExtendedKey, "serialized length %d error", len(buff))
+	}
+
+	payload := buff[:serializedKeyLen]
+
+	if !bytes.Equal(checksum(payload), buff[serializedKeyLen:]) {
+		return nil, xerrors.Wrapf(ErrExtendedKey, "checksum mismatch")
+	}
+
+	key := &ExtendedKey{
+		depth:     payload[4],
+		index:     binary.BigEndian.Uint32(payload[9:13]),
+		chainCode: append([]byte(nil), payload[13:45]...),
+	}
+
+	copy(key.parentFP[:], payload[5:9])
+
+	var version [4]byte
+
+	copy(version[:], payload[:4])
+
+	found := false
+
+	for _, versions := range knownVersions {
+		if versions.Private == version {
+			key.versions, key.private, found = versions, true, true
+			break
+		}
+
+		if versions.Public == version {
+			key.versions, key.private, found = versions, false, true
+			break
+		}
+	}
+
+	if !found {
+		return nil, xerrors.Wrapf(ErrUnknownVersion, "version %x", version)
+	}
+
+	if key.depth == 0 && (key.index != 0 || key.ParentFingerprint() != 0) {
+		return nil, xerrors.Wrapf(ErrExtendedKey, "master key with non-zero index or parent fingerprint")
+	}
+
+	if key.private {
+		if payload[45] != 0x00 {
+			return nil, xerrors.Wrapf(ErrExtendedKey, "private key prefix %x error", payload[45])
+		}
+
+		k := new(big.Int).SetBytes(payload[46:])
+
+		if k.Sign() == 0 || k.Cmp(secp256k1.SECP256K1().Params().N) >= 0 {
+			return nil, xerrors.Wrapf(ErrExtendedKey, "private key out of range")
+		}
+
+		key.key = append([]byte(nil), payload[46:]...)
+
+	} else {
+		if _, _, err := secp256k1.DecompressPubkey(payload[45:]); err != nil {
+			return nil, xerrors.Wrapf(ErrExtendedKey, "public key error: %s", err)
+		}
+
+		key.key = append([]byte(nil), payload[45:]...)
+	}
+
+	return key, nil
+}
+
+func checksum(buff []byte) []byte {
+	first := sha256.Sum256(buff)
+	second := sha256.Sum256(first[:])
+
+	return second[:4]
+}
@@ -0,0 +1,123 @@
+package hd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testChild struct {
+	index uint32
+	xprv  string
+	xpub  string
+}
+
+func TestVector1(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+
+	require.NoError(t, err)
+
+	master, err := NewMaster(seed)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi", master.String())
+	require.Equal(t, "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8", master.Neuter().String())
+
+	children := []testChild{
+		{
+			index: HardenedKeyStart,
+			xprv:  "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+			xpub:  "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+		},
+		{
+			index: 1,
+			xprv:  "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+			xpub:  "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		},
+		{
+			index: HardenedKeyStart + 2,
+			xprv:  "xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+			xpub:  "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+		},
+		{
+			index: 2,
+			xprv:  "xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+			xpub:  "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+		},
+		{
+			index: 1000000000,
+			xprv:  "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+			xpub:  "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+		},
+	}
+
+	current := master
+
+	for _, child := range children {
+		next, err := current.Child(child.index)
+
+		require.NoError(t, err)
+
+		require.Equal(t, child.xprv, next.String())
+		require.Equal(t, child.xpub, next.Neuter().String())
+
+		if child.index < HardenedKeyStart {
+			pub, err := current.Neuter().Child(child.index)
+
+			require.NoError(t, err)
+
+			require.Equal(t, child.xpub, pub.String())
+		}
+
+		current = next
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, serialized := range []string{
+		"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+		"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+	} {
+		key, err := Parse(serialized)
+
+		require.NoError(t, err)
+
+		require.Equal(t, serialized, key.String())
+	}
+
+	_, err := Parse("xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwR")
+
+	require.Error(t, err)
+
+	pub, err := Parse("xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ")
+
+	require.NoError(t, err)
+
+	_, err = pub.Child(HardenedKeyStart)
+
+	require.Equal(t, ErrHardened, err)
+}
+
+func TestParsePath(t *testing.T) {
+	indexes, err := ParsePath("m/44'/60'/0h/0/1")
+
+	require.NoError(t, err)
+
+	require.Equal(t, []uint32{HardenedKeyStart + 44, HardenedKeyStart + 60, HardenedKeyStart, 0, 1}, indexes)
+
+	indexes, err = ParsePath("m")
+
+	require.NoError(t, err)
+
+	require.Empty(t, indexes)
+
+	for _, path := range []string{"", "44'/60'", "m/", "m/a", "m/2147483648", "m/-1"} {
+		_, err = ParsePath(path)
+
+		require.Error(t, err, path)
+	}
+
+	require.Equal(t, "m/44'/60'/0'/0/3", BIP44Path(60, 0, 0, 3))
+}
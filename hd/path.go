@@ -0,0 +1,74 @@
+package hd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// ErrPath .
+var ErrPath = errors.New("invalid derivation path")
+
+// BIP44Path format bip44 path m/44'/coin'/account'/change/index
+func BIP44Path(coinType, account, change, index uint32) string {
+	return fmt.Sprintf("m/44'/%d'/%d'/%d/%d", coinType, account, change, index)
+}
+
+// ParsePath parse derivation path string like m/44'/60'/0'/0/0 to child index array,
+// hardened child can be marked with ', h or H
+func ParsePath(path string) ([]uint32, error) {
+	tokens := strings.Split(strings.TrimSpace(path), "/")
+
+	if tokens[0] != "m" {
+		return nil, xerrors.Wrapf(ErrPath, "path %s must start with m", path)
+	}
+
+	indexes := make([]uint32, 0, len(tokens)-1)
+
+	for _, token := range tokens[1:] {
+		hardened := false
+
+		if strings.HasSuffix(token, "'") || strings.HasSuffix(token, "h") || strings.HasSuffix(token, "H") {
+			hardened = true
+			token = token[:len(token)-1]
+		}
+
+		index, err := strconv.ParseUint(token, 10, 32)
+
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, xerrors.Wrapf(ErrPath, "path %s invalid child index %s", path, token)
+		}
+
+		if hardened {
+			index += uint64(HardenedKeyStart)
+		}
+
+		indexes = append(indexes, uint32(index))
+	}
+
+	return indexes, nil
+}
+
+// DerivePath derive child extended key by path string
+func (key *ExtendedKey) DerivePath(path string) (*ExtendedKey, error) {
+	indexes, err := ParsePath(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	current := key
+
+	for _, index := range indexes {
+		current, err = current.Child(index)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return current, nil
+}
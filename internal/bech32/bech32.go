@@ -0,0 +1,268 @@
+// Package bech32 bip173 bech32, bip350 bech32m and segwit address implement
+package bech32
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrEncoding = errors.New("invalid bech32 string")
+	ErrChecksum = errors.New("bech32 checksum mismatch")
+	ErrSegwit   = errors.New("invalid segwit address")
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Variant checksum constant of bech32 variants
+type Variant uint32
+
+// Variants
+const (
+	Bech32  Variant = 1          // bip173 bech32
+	Bech32m Variant = 0x2bc830a3 // bip350 bech32m
+)
+
+const maxLength = 90
+
+var generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func polymod(values []byte) uint32 {
+	chk := uint32(1)
+
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	buff := make([]byte, 0, len(hrp)*2+1)
+
+	for i := 0; i < len(hrp); i++ {
+		buff = append(buff, hrp[i]>>5)
+	}
+
+	buff = append(buff, 0)
+
+	for i := 0; i < len(hrp); i++ {
+		buff = append(buff, hrp[i]&31)
+	}
+
+	return buff
+}
+
+func createChecksum(hrp string, data []byte, variant Variant) []byte {
+	values := append(hrpExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+
+	mod := polymod(values) ^ uint32(variant)
+
+	checksum := make([]byte, 6)
+
+	for i := 0; i < 6; i++ {
+		checksum[i] = byte((mod >> uint(5*(5-i))) & 31)
+	}
+
+	return checksum
+}
+
+// Encode encode 5 bits data array with human readable part
+func Encode(hrp string, data []byte) (string, error) {
+	return EncodeVariant(hrp, data, Bech32)
+}
+
+// EncodeVariant encode 5 bits data array with human readable part and checksum variant
+func EncodeVariant(hrp string, data []byte, variant Variant) (string, error) {
+	if len(hrp)+len(data)+7 > maxLength {
+		return "", xerrors.Wrapf(ErrEncoding, "length %d exceeds %d", len(hrp)+len(data)+7, maxLength)
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString(strings.ToLower(hrp))
+	builder.WriteByte('1')
+
+	checksum := createChecksum(strings.ToLower(hrp), data, variant)
+
+	for _, v := range append(append([]byte(nil), data...), checksum...) {
+		if v >= 32 {
+			return "", xerrors.Wrapf(ErrEncoding, "data value %d out of 5 bits", v)
+		}
+
+		builder.WriteByte(charset[v])
+	}
+
+	return builder.String(), nil
+}
+
+// Decode decode bech32 string to human readable part and 5 bits data array
+func Decode(encoded string) (string, []byte, error) {
+	hrp, data, variant, err := DecodeVariant(encoded)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	if variant != Bech32 {
+		return "", nil, ErrChecksum
+	}
+
+	return hrp, data, nil
+}
+
+// DecodeVariant decode bech32 or bech32m string to human readable part, 5 bits data array and checksum variant
+func DecodeVariant(encoded string) (string, []byte, Variant, error) {
+	if len(encoded) > maxLength {
+		return "", nil, 0, xerrors.Wrapf(ErrEncoding, "length %d exceeds %d", len(encoded), maxLength)
+	}
+
+	if strings.ToLower(encoded) != encoded && strings.ToUpper(encoded) != encoded {
+		return "", nil, 0, xerrors.Wrapf(ErrEncoding, "mixed case")
+	}
+
+	encoded = strings.ToLower(encoded)
+
+	pos := strings.LastIndexByte(encoded, '1')
+
+	if pos < 1 || pos+7 > len(encoded) {
+		return "", nil, 0, xerrors.Wrapf(ErrEncoding, "separator position %d error", pos)
+	}
+
+	hrp := encoded[:pos]
+
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, xerrors.Wrapf(ErrEncoding, "hrp character %d out of range", hrp[i])
+		}
+	}
+
+	data := make([]byte, 0, len(encoded)-pos-1)
+
+	for i := pos + 1; i < len(encoded); i++ {
+		v := strings.IndexByte(charset, encoded[i])
+
+		if v < 0 {
+			return "", nil, 0, xerrors.Wrapf(ErrEncoding, "invalid character %c", encoded[i])
+		}
+
+		data = append(data, byte(v))
+	}
+
+	variant := Variant(polymod(append(hrpExpand(hrp), data...)))
+
+	if variant != Bech32 && variant != Bech32m {
+		return "", nil, 0, ErrChecksum
+	}
+
+	return hrp, data[:len(data)-6], variant, nil
+}
+
+// ConvertBits regroup bits array from fromBits width to toBits width
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxv := uint32(1)<<toBits - 1
+
+	var result []byte
+
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, xerrors.Wrapf(ErrEncoding, "value %d out of %d bits", v, fromBits)
+		}
+
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte((acc>>bits)&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte((acc<<(toBits-bits))&maxv))
+		}
+	} else if bits >= fromBits || (acc<<(toBits-bits))&maxv != 0 {
+		return nil, xerrors.Wrapf(ErrEncoding, "invalid padding")
+	}
+
+	return result, nil
+}
+
+// EncodeSegwit encode segwit address with witness version and program,
+// version 0 use bech32 and version 1+ use bech32m checksum
+func EncodeSegwit(hrp string, version byte, program []byte) (string, error) {
+	data, err := ConvertBits(program, 8, 5, true)
+
+	if err != nil {
+		return "", err
+	}
+
+	variant := Bech32
+
+	if version > 0 {
+		variant = Bech32m
+	}
+
+	address, err := EncodeVariant(hrp, append([]byte{version}, data...), variant)
+
+	if err != nil {
+		return "", err
+	}
+
+	if _, _, err := DecodeSegwit(hrp, address); err != nil {
+		return "", err
+	}
+
+	return address, nil
+}
+
+// DecodeSegwit decode segwit address to witness version and program
+func DecodeSegwit(hrp string, address string) (byte, []byte, error) {
+	decodedHRP, data, variant, err := DecodeVariant(address)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if decodedHRP != hrp {
+		return 0, nil, xerrors.Wrapf(ErrSegwit, "hrp %s mismatch %s", decodedHRP, hrp)
+	}
+
+	if len(data) < 1 || data[0] > 16 {
+		return 0, nil, xerrors.Wrapf(ErrSegwit, "invalid witness version")
+	}
+
+	if (data[0] == 0 && variant != Bech32) || (data[0] != 0 && variant != Bech32m) {
+		return 0, nil, xerrors.Wrapf(ErrSegwit, "witness version %d checksum variant mismatch", data[0])
+	}
+
+	program, err := ConvertBits(data[1:], 5, 8, false)
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if len(program) < 2 || len(program) > 40 {
+		return 0, nil, xerrors.Wrapf(ErrSegwit, "invalid witness program length %d", len(program))
+	}
+
+	if data[0] == 0 && len(program) != 20 && len(program) != 32 {
+		return 0, nil, xerrors.Wrapf(ErrSegwit, "invalid witness v0 program length %d", len(program))
+	}
+
+	return data[0], program, nil
+}
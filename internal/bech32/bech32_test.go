@@ -0,0 +1,89 @@
+package bech32
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidChecksum(t *testing.T) {
+	for _, encoded := range []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+	} {
+		hrp, data, err := Decode(encoded)
+
+		require.NoError(t, err, encoded)
+
+		reencoded, err := Encode(hrp, data)
+
+		require.NoError(t, err)
+
+		require.Equal(t, strings.ToLower(encoded), reencoded)
+	}
+
+	for _, encoded := range []string{
+		"pzry9x0s0muk",
+		"1pzry9x0s0muk",
+		"x1b4n0q5v",
+		"li1dgmt3",
+		"A1G7SGD8",
+		"10a06t8",
+		"1qzzfhee",
+	} {
+		_, _, err := Decode(encoded)
+
+		require.Error(t, err, encoded)
+	}
+}
+
+func TestSegwit(t *testing.T) {
+	version, program, err := DecodeSegwit("bc", "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4")
+
+	require.NoError(t, err)
+
+	require.Equal(t, byte(0), version)
+	require.Equal(t, "751e76e8199196d454941c45d1b3a323f1433bd6", hex.EncodeToString(program))
+
+	address, err := EncodeSegwit("bc", version, program)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", address)
+
+	_, _, err = DecodeSegwit("bc", "bc1zw508d6qejxtdg4y5r3zarvaryvqyzf3du")
+
+	require.Error(t, err)
+}
+
+func TestSegwitV1(t *testing.T) {
+	version, program, err := DecodeSegwit("bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0")
+
+	require.NoError(t, err)
+
+	require.Equal(t, byte(1), version)
+	require.Equal(t, "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", hex.EncodeToString(program))
+
+	address, err := EncodeSegwit("bc", version, program)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", address)
+
+	data, err := ConvertBits(program, 8, 5, true)
+
+	require.NoError(t, err)
+
+	address, err = EncodeVariant("bc", append([]byte{1}, data...), Bech32)
+
+	require.NoError(t, err)
+
+	_, _, err = DecodeSegwit("bc", address)
+
+	require.Error(t, err)
+}
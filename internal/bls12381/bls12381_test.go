@@ -0,0 +1,230 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomScalar(t *testing.T) *big.Int {
+	k, err := rand.Int(rand.Reader, Order)
+
+	require.NoError(t, err)
+
+	return k
+}
+
+func randomFe2(t *testing.T) fe2 {
+	c0, err := rand.Int(rand.Reader, fieldP)
+
+	require.NoError(t, err)
+
+	c1, err := rand.Int(rand.Reader, fieldP)
+
+	require.NoError(t, err)
+
+	return fe2{c0: c0, c1: c1}
+}
+
+func TestField(t *testing.T) {
+	for i := 0; i < 16; i++ {
+		a := randomFe2(t)
+
+		require.True(t, a.mul(a.inverse()).equal(fe2One()))
+
+		require.True(t, a.square().equal(a.mul(a)))
+
+		s, ok := a.square().sqrt()
+
+		require.True(t, ok)
+
+		require.True(t, s.square().equal(a.square()))
+
+		var f fe12
+
+		for j := range f {
+			f[j] = randomFe2(t)
+		}
+
+		require.True(t, f.mul(f.inverse()).isOne())
+
+		// frobenius is the p-th power
+		require.True(t, f.frobenius().equal(f.exp(fieldP)))
+	}
+
+	// the hard part by x equals the plain exponentiation
+	f := fe12One()
+
+	f[1] = randomFe2(t)
+
+	p2 := new(big.Int).Mul(fieldP, fieldP)
+
+	hard := new(big.Int).Mul(p2, p2)
+
+	hard.Sub(hard, p2).Add(hard, big.NewInt(1)).Div(hard, Order)
+
+	easy := f.conj().mul(f.inverse())
+
+	easy = easy.frobenius().frobenius().mul(easy)
+
+	require.True(t, f.finalExponentiation().equal(easy.exp(hard)))
+
+	// xi = 1 + u is not a square
+	_, ok := fe2Int(1, 1).sqrt()
+
+	require.False(t, ok)
+}
+
+func TestEncoding(t *testing.T) {
+	g1 := NewG1Generator()
+
+	require.Equal(t, "97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb", hex.EncodeToString(g1.Bytes()))
+
+	require.True(t, g1.p.inSubgroup())
+
+	require.Equal(t, "c0"+hex.EncodeToString(make([]byte, 47)), hex.EncodeToString(NewG1Identity().Bytes()))
+
+	g2 := HashToG2([]byte("msg"), []byte("dst"))
+
+	for i := 0; i < 4; i++ {
+		k := randomScalar(t)
+
+		p := g1.ScalarMult(k)
+
+		for _, buff := range [][]byte{p.Bytes(), p.Uncompressed()} {
+			decoded, err := NewG1(buff)
+
+			require.NoError(t, err)
+
+			require.True(t, p.Equal(decoded))
+		}
+
+		q := g2.ScalarMult(k)
+
+		for _, buff := range [][]byte{q.Bytes(), q.Uncompressed()} {
+			decoded, err := NewG2(buff)
+
+			require.NoError(t, err)
+
+			require.True(t, q.Equal(decoded))
+		}
+	}
+
+	for _, buff := range [][]byte{NewG1Identity().Bytes(), NewG1Identity().Uncompressed()} {
+		p, err := NewG1(buff)
+
+		require.NoError(t, err)
+
+		require.True(t, p.IsIdentity())
+	}
+
+	// wrong flags and length
+	buff := g1.Bytes()
+
+	buff[0] &^= flagCompressed
+
+	_, err := NewG1(buff)
+
+	require.Error(t, err)
+
+	_, err = NewG1(g1.Bytes()[1:])
+
+	require.Error(t, err)
+
+	// the point of E(GF(p)) out of the subgroup
+	for x := int64(0); ; x++ {
+		p := affinePoint(fe2Int(x, 0), fe2Zero())
+
+		y, ok := p.x.square().mul(p.x).add(b1).sqrt()
+
+		if !ok {
+			continue
+		}
+
+		p.y = y
+
+		_, err = NewG1(encode(p, G1CompressedSize, true))
+
+		require.Equal(t, ErrSubgroup, err)
+
+		break
+	}
+}
+
+func TestScalarMult(t *testing.T) {
+	g1 := NewG1Generator()
+
+	g2 := HashToG2([]byte("msg"), []byte("dst"))
+
+	scalars := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2), new(big.Int).Sub(Order, big.NewInt(1))}
+
+	for i := 0; i < 4; i++ {
+		scalars = append(scalars, randomScalar(t))
+	}
+
+	for _, k := range scalars {
+		require.True(t, g1.p.ladder(k).equal(g1.p.mult(k)))
+
+		require.True(t, g2.p.ladder(k).equal(g2.p.mult(k)))
+	}
+
+	require.True(t, g1.ScalarMult(Order).IsIdentity())
+
+	require.True(t, NewG1Identity().ScalarMult(big.NewInt(3)).IsIdentity())
+}
+
+func TestPairing(t *testing.T) {
+	g1 := NewG1Generator()
+
+	g2 := HashToG2([]byte("msg"), []byte("dst"))
+
+	a, b := randomScalar(t), randomScalar(t)
+
+	e := pairing(g1.p, g2.p)
+
+	require.False(t, e.isOne())
+
+	require.True(t, e.exp(Order).isOne())
+
+	// e(a P, b Q) = e(P, Q)^(a b) = e(a b P, Q)
+	ab := new(big.Int).Mul(a, b)
+
+	require.True(t, pairing(g1.ScalarMult(a).p, g2.ScalarMult(b).p).equal(e.exp(ab)))
+
+	require.True(t, PairingCheck([]*G1{g1.ScalarMult(a), g1.ScalarMult(ab).Neg()}, []*G2{g2.ScalarMult(b), g2}))
+
+	require.False(t, PairingCheck([]*G1{g1.ScalarMult(a), g1.ScalarMult(ab)}, []*G2{g2.ScalarMult(b), g2}))
+
+	require.True(t, PairingCheck([]*G1{NewG1Identity()}, []*G2{g2}))
+}
+
+func TestHashToG2(t *testing.T) {
+	require.Equal(t,
+		"68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235",
+		hex.EncodeToString(ExpandMessageXMD(nil, []byte("QUUX-V01-CS02-with-expander-SHA256-128"), 32)))
+
+	require.Equal(t,
+		"d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615",
+		hex.EncodeToString(ExpandMessageXMD([]byte("abc"), []byte("QUUX-V01-CS02-with-expander-SHA256-128"), 32)))
+
+	p := HashToG2(nil, []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_"))
+
+	x, y := p.p.affine()
+
+	require.Equal(t, fe2Hex(
+		"0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a",
+		"05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d",
+	), x)
+
+	require.Equal(t, fe2Hex(
+		"0503921d7f6a12805e72940b963c0cf3471c7b2a524950ca195d11062ee75ec076daf2d4bc358c4b190c0c98064fdd92",
+		"12424ac32561493f3fe3c260708a12b7c620e7be00099a974e259ddc7d1f6395c3c811cdd19f1e8dbf3e9ecfdcbab8d6",
+	), y)
+
+	require.True(t, p.p.isOnCurve(b2))
+
+	require.True(t, p.p.inSubgroup())
+}
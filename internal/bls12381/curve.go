@@ -0,0 +1,186 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+var (
+	// Order the prime order r of G1, G2 and GT
+	Order, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+	// curveX the curve parameter x = -0xd201000000010000, p and r are polynomials of it
+	curveX, _ = new(big.Int).SetString("-d201000000010000", 16)
+
+	b1 = fe2Int(4, 0) // G1 y^2 = x^3 + 4
+	b2 = fe2Int(4, 4) // G2 y^2 = x^3 + 4 (1 + u)
+)
+
+// point jacobian point (X / Z^2, Y / Z^3) of y^2 = x^3 + b, G1 points have zero imaginary parts,
+// the infinity has Z = 0
+type point struct {
+	x, y, z fe2
+}
+
+func infinity() *point {
+	return &point{x: fe2One(), y: fe2One(), z: fe2Zero()}
+}
+
+func affinePoint(x, y fe2) *point {
+	return &point{x: x, y: y, z: fe2One()}
+}
+
+func (p *point) isInfinity() bool {
+	return p.z.isZero()
+}
+
+// affine return the affine coordinates, the point must not be the infinity
+func (p *point) affine() (x, y fe2) {
+	zInv := p.z.inverse()
+
+	zInv2 := zInv.square()
+
+	return p.x.mul(zInv2), p.y.mul(zInv2.mul(zInv))
+}
+
+func (p *point) isOnCurve(b fe2) bool {
+	if p.isInfinity() {
+		return true
+	}
+
+	x, y := p.affine()
+
+	return y.square().equal(x.square().mul(x).add(b))
+}
+
+func (p *point) equal(q *point) bool {
+	if p.isInfinity() || q.isInfinity() {
+		return p.isInfinity() && q.isInfinity()
+	}
+
+	// X1 Z2^2 = X2 Z1^2 and Y1 Z2^3 = Y2 Z1^3
+	z1z1, z2z2 := p.z.square(), q.z.square()
+
+	if !p.x.mul(z2z2).equal(q.x.mul(z1z1)) {
+		return false
+	}
+
+	return p.y.mul(z2z2.mul(q.z)).equal(q.y.mul(z1z1.mul(p.z)))
+}
+
+func (p *point) neg() *point {
+	return &point{x: p.x, y: p.y.neg(), z: p.z}
+}
+
+// double dbl-2009-l for a = 0
+func (p *point) double() *point {
+	if p.isInfinity() || p.y.isZero() {
+		return infinity()
+	}
+
+	a := p.x.square()
+	b := p.y.square()
+	c := b.square()
+
+	d := p.x.add(b).square().sub(a).sub(c).double()
+	e := a.double().add(a)
+	f := e.square()
+
+	x3 := f.sub(d.double())
+	y3 := e.mul(d.sub(x3)).sub(c.double().double().double())
+	z3 := p.y.mul(p.z).double()
+
+	return &point{x: x3, y: y3, z: z3}
+}
+
+// add add-2007-bl
+func (p *point) add(q *point) *point {
+	if p.isInfinity() {
+		return q
+	}
+
+	if q.isInfinity() {
+		return p
+	}
+
+	z1z1 := p.z.square()
+	z2z2 := q.z.square()
+
+	u1 := p.x.mul(z2z2)
+	u2 := q.x.mul(z1z1)
+
+	s1 := p.y.mul(q.z).mul(z2z2)
+	s2 := q.y.mul(p.z).mul(z1z1)
+
+	h := u2.sub(u1)
+	r := s2.sub(s1)
+
+	if h.isZero() {
+		if r.isZero() {
+			return p.double()
+		}
+
+		return infinity()
+	}
+
+	i := h.double().square()
+	j := h.mul(i)
+
+	r = r.double()
+
+	v := u1.mul(i)
+
+	x3 := r.square().sub(j).sub(v.double())
+	y3 := r.mul(v.sub(x3)).sub(s1.mul(j).double())
+	z3 := p.z.add(q.z).square().sub(z1z1).sub(z2z2).mul(h)
+
+	return &point{x: x3, y: y3, z: z3}
+}
+
+// mult the scalar multiplication k * p by double and add, k must not be negative, variable time so
+// only for public scalars like the subgroup check and the cofactor clearing
+func (p *point) mult(k *big.Int) *point {
+	result := infinity()
+
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = result.double()
+
+		if k.Bit(i) == 1 {
+			result = result.add(p)
+		}
+	}
+
+	return result
+}
+
+// ladder the scalar multiplication k * p of the subgroup point p and 0 <= k < r for secret scalars,
+// k + r or k + 2r has exactly 256 bits so the montgomery ladder always runs the same 255 steps of one
+// addition and one doubling whatever the bit length and the hamming weight of k are, the big.Int field
+// arithmetic below it is still not constant time
+func (p *point) ladder(k *big.Int) *point {
+	bits := Order.BitLen() + 1
+
+	scalar := new(big.Int).Add(k, Order)
+
+	if scalar.BitLen() < bits {
+		scalar.Add(scalar, Order)
+	}
+
+	// r[1] - r[0] = p holds on every step
+	r := [2]*point{p, p.double()}
+
+	for i := bits - 2; i >= 0; i-- {
+		b := scalar.Bit(i)
+
+		sum := r[0].add(r[1])
+
+		r[b] = r[b].double()
+		r[1-b] = sum
+	}
+
+	return r[0]
+}
+
+// inSubgroup check r * p = O
+func (p *point) inSubgroup() bool {
+	return p.mult(Order).isInfinity()
+}
@@ -0,0 +1,204 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+var (
+	// fieldP the base field modulus
+	fieldP, _ = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+
+	// fieldHalf (p - 1) / 2, the bigger of y and -y is above it
+	fieldHalf = new(big.Int).Rsh(fieldP, 1)
+
+	// sqrtExp (p + 1) / 4, p = 3 mod 4
+	sqrtExp = new(big.Int).Rsh(new(big.Int).Add(fieldP, big.NewInt(1)), 2)
+)
+
+func fpMod(a *big.Int) *big.Int {
+	return a.Mod(a, fieldP)
+}
+
+func fpHex(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+
+	if !ok {
+		panic("invalid field element " + s)
+	}
+
+	return v
+}
+
+// fpSqrt square root in GF(p), ok is false if a is not a square
+func fpSqrt(a *big.Int) (*big.Int, bool) {
+	x := new(big.Int).Exp(a, sqrtExp, fieldP)
+
+	check := fpMod(new(big.Int).Mul(x, x))
+
+	return x, check.Cmp(fpMod(new(big.Int).Set(a))) == 0
+}
+
+// fe2 element c0 + c1 * u of GF(p^2) = GF(p)[u] / (u^2 + 1), the operations allocate new elements
+// and never modify the operands
+type fe2 struct {
+	c0, c1 *big.Int
+}
+
+func fe2Zero() fe2 {
+	return fe2{c0: new(big.Int), c1: new(big.Int)}
+}
+
+func fe2One() fe2 {
+	return fe2{c0: big.NewInt(1), c1: new(big.Int)}
+}
+
+// fe2Int the small integer a + b * u
+func fe2Int(a, b int64) fe2 {
+	return fe2{c0: fpMod(big.NewInt(a)), c1: fpMod(big.NewInt(b))}
+}
+
+func fe2Hex(c0, c1 string) fe2 {
+	return fe2{c0: fpHex(c0), c1: fpHex(c1)}
+}
+
+func (a fe2) isZero() bool {
+	return a.c0.Sign() == 0 && a.c1.Sign() == 0
+}
+
+func (a fe2) equal(b fe2) bool {
+	return a.c0.Cmp(b.c0) == 0 && a.c1.Cmp(b.c1) == 0
+}
+
+func (a fe2) add(b fe2) fe2 {
+	return fe2{c0: fpMod(new(big.Int).Add(a.c0, b.c0)), c1: fpMod(new(big.Int).Add(a.c1, b.c1))}
+}
+
+func (a fe2) sub(b fe2) fe2 {
+	return fe2{c0: fpMod(new(big.Int).Sub(a.c0, b.c0)), c1: fpMod(new(big.Int).Sub(a.c1, b.c1))}
+}
+
+func (a fe2) neg() fe2 {
+	return fe2{c0: fpMod(new(big.Int).Neg(a.c0)), c1: fpMod(new(big.Int).Neg(a.c1))}
+}
+
+// conj the conjugate c0 - c1 * u, which is also the frobenius a^p
+func (a fe2) conj() fe2 {
+	return fe2{c0: a.c0, c1: fpMod(new(big.Int).Neg(a.c1))}
+}
+
+func (a fe2) double() fe2 {
+	return a.add(a)
+}
+
+func (a fe2) mul(b fe2) fe2 {
+	t0 := new(big.Int).Mul(a.c0, b.c0)
+	t1 := new(big.Int).Mul(a.c1, b.c1)
+
+	// (a0 + a1)(b0 + b1) - a0 b0 - a1 b1
+	c1 := new(big.Int).Mul(new(big.Int).Add(a.c0, a.c1), new(big.Int).Add(b.c0, b.c1))
+
+	c1.Sub(c1, t0).Sub(c1, t1)
+
+	return fe2{c0: fpMod(t0.Sub(t0, t1)), c1: fpMod(c1)}
+}
+
+func (a fe2) square() fe2 {
+	c0 := new(big.Int).Mul(new(big.Int).Add(a.c0, a.c1), new(big.Int).Sub(a.c0, a.c1))
+	c1 := new(big.Int).Mul(a.c0, a.c1)
+
+	return fe2{c0: fpMod(c0), c1: fpMod(c1.Lsh(c1, 1))}
+}
+
+// mulScalar multiply by element of GF(p)
+func (a fe2) mulScalar(k *big.Int) fe2 {
+	return fe2{c0: fpMod(new(big.Int).Mul(a.c0, k)), c1: fpMod(new(big.Int).Mul(a.c1, k))}
+}
+
+// mulXi multiply by the non residue xi = 1 + u
+func (a fe2) mulXi() fe2 {
+	return fe2{c0: fpMod(new(big.Int).Sub(a.c0, a.c1)), c1: fpMod(new(big.Int).Add(a.c0, a.c1))}
+}
+
+// inverse the inverse of non zero element, (c0 - c1 * u) / (c0^2 + c1^2)
+func (a fe2) inverse() fe2 {
+	norm := new(big.Int).Mul(a.c0, a.c0)
+
+	norm.Add(norm, new(big.Int).Mul(a.c1, a.c1))
+
+	norm.ModInverse(fpMod(norm), fieldP)
+
+	return a.conj().mulScalar(norm)
+}
+
+func (a fe2) exp(k *big.Int) fe2 {
+	result := fe2One()
+
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = result.square()
+
+		if k.Bit(i) == 1 {
+			result = result.mul(a)
+		}
+	}
+
+	return result
+}
+
+// sqrt square root in GF(p^2) by the norm, ok is false if a is not a square
+func (a fe2) sqrt() (fe2, bool) {
+	if a.c1.Sign() == 0 {
+		if x, ok := fpSqrt(a.c0); ok {
+			return fe2{c0: x, c1: new(big.Int)}, true
+		}
+
+		// a = -(sqrt(-a))^2 = (sqrt(-a) * u)^2
+		x, ok := fpSqrt(fpMod(new(big.Int).Neg(a.c0)))
+
+		return fe2{c0: new(big.Int), c1: x}, ok
+	}
+
+	norm := new(big.Int).Mul(a.c0, a.c0)
+
+	n, ok := fpSqrt(fpMod(norm.Add(norm, new(big.Int).Mul(a.c1, a.c1))))
+
+	if !ok {
+		return fe2{}, false
+	}
+
+	half := new(big.Int).ModInverse(big.NewInt(2), fieldP)
+
+	// x0^2 = (c0 + n) / 2 or (c0 - n) / 2, x1 = c1 / (2 x0)
+	x0, ok := fpSqrt(fpMod(new(big.Int).Mul(new(big.Int).Add(a.c0, n), half)))
+
+	if !ok {
+		x0, ok = fpSqrt(fpMod(new(big.Int).Mul(new(big.Int).Sub(a.c0, n), half)))
+
+		if !ok {
+			return fe2{}, false
+		}
+	}
+
+	x1 := new(big.Int).ModInverse(new(big.Int).Lsh(x0, 1), fieldP)
+
+	x := fe2{c0: x0, c1: fpMod(x1.Mul(x1, a.c1))}
+
+	return x, x.square().equal(a)
+}
+
+// sgn0 the sign of rfc9380
+func (a fe2) sgn0() uint {
+	if a.c0.Sign() != 0 {
+		return a.c0.Bit(0)
+	}
+
+	return a.c1.Bit(0)
+}
+
+// lexicographicallyLargest the zcash serialization sign, compare c1 first then c0
+func (a fe2) lexicographicallyLargest() bool {
+	if a.c1.Sign() != 0 {
+		return a.c1.Cmp(fieldHalf) > 0
+	}
+
+	return a.c0.Cmp(fieldHalf) > 0
+}
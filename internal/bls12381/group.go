@@ -0,0 +1,300 @@
+package bls12381
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrEncoding = errors.New("invalid bls12-381 point encoding")
+	ErrSubgroup = errors.New("bls12-381 point not in the prime order subgroup")
+)
+
+// Sizes of the zcash serialization
+const (
+	G1CompressedSize   = 48
+	G1UncompressedSize = 96
+	G2CompressedSize   = 96
+	G2UncompressedSize = 192
+)
+
+// the top three bits of the first byte
+const (
+	flagCompressed = 0x80
+	flagInfinity   = 0x40
+	flagLargest    = 0x20
+	flagMask       = 0xe0
+)
+
+var (
+	g1Generator = affinePoint(
+		fe2Hex("17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb", "0"),
+		fe2Hex("08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1", "0"),
+	)
+)
+
+// G1 point of the prime order subgroup of E(GF(p)): y^2 = x^3 + 4
+type G1 struct {
+	p *point
+}
+
+// G2 point of the prime order subgroup of the twist E'(GF(p^2)): y^2 = x^3 + 4 (1 + u)
+type G2 struct {
+	p *point
+}
+
+// NewG1Generator the standard G1 generator
+func NewG1Generator() *G1 {
+	return &G1{p: g1Generator}
+}
+
+// NewG1Identity the G1 point at infinity
+func NewG1Identity() *G1 {
+	return &G1{p: infinity()}
+}
+
+// Add p + q
+func (p *G1) Add(q *G1) *G1 {
+	return &G1{p: p.p.add(q.p)}
+}
+
+// Neg -p
+func (p *G1) Neg() *G1 {
+	return &G1{p: p.p.neg()}
+}
+
+// ScalarMult k * p, k is reduced mod r, the scalar may be secret, e.g. the signing key
+func (p *G1) ScalarMult(k *big.Int) *G1 {
+	return &G1{p: p.p.ladder(new(big.Int).Mod(k, Order))}
+}
+
+// IsIdentity check the point is the infinity
+func (p *G1) IsIdentity() bool {
+	return p.p.isInfinity()
+}
+
+// Equal check p = q
+func (p *G1) Equal(q *G1) bool {
+	return p.p.equal(q.p)
+}
+
+// Bytes the 48 bytes compressed encoding
+func (p *G1) Bytes() []byte {
+	return encode(p.p, G1CompressedSize, true)
+}
+
+// Uncompressed the 96 bytes uncompressed encoding
+func (p *G1) Uncompressed() []byte {
+	return encode(p.p, G1UncompressedSize, false)
+}
+
+// NewG1 decode the compressed or uncompressed encoding, the point must be in the subgroup
+func NewG1(buff []byte) (*G1, error) {
+	p, err := decode(buff, G1CompressedSize, b1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &G1{p: p}, nil
+}
+
+// NewG2Identity the G2 point at infinity
+func NewG2Identity() *G2 {
+	return &G2{p: infinity()}
+}
+
+// Add p + q
+func (p *G2) Add(q *G2) *G2 {
+	return &G2{p: p.p.add(q.p)}
+}
+
+// Neg -p
+func (p *G2) Neg() *G2 {
+	return &G2{p: p.p.neg()}
+}
+
+// ScalarMult k * p, k is reduced mod r, the scalar may be secret, e.g. the signing key
+func (p *G2) ScalarMult(k *big.Int) *G2 {
+	return &G2{p: p.p.ladder(new(big.Int).Mod(k, Order))}
+}
+
+// IsIdentity check the point is the infinity
+func (p *G2) IsIdentity() bool {
+	return p.p.isInfinity()
+}
+
+// Equal check p = q
+func (p *G2) Equal(q *G2) bool {
+	return p.p.equal(q.p)
+}
+
+// Bytes the 96 bytes compressed encoding
+func (p *G2) Bytes() []byte {
+	return encode(p.p, G2CompressedSize, true)
+}
+
+// Uncompressed the 192 bytes uncompressed encoding
+func (p *G2) Uncompressed() []byte {
+	return encode(p.p, G2UncompressedSize, false)
+}
+
+// NewG2 decode the compressed or uncompressed encoding, the point must be in the subgroup
+func NewG2(buff []byte) (*G2, error) {
+	p, err := decode(buff, G2CompressedSize, b2)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &G2{p: p}, nil
+}
+
+// elementSize the size of GF(p) or GF(p^2) element
+func elementSize(size int, compressed bool) int {
+	if compressed {
+		return size
+	}
+
+	return size / 2
+}
+
+// putElement big endian element, GF(p^2) element is c1 || c0
+func putElement(buff []byte, a fe2) {
+	if len(buff) == 48 {
+		a.c0.FillBytes(buff)
+		return
+	}
+
+	a.c1.FillBytes(buff[:48])
+	a.c0.FillBytes(buff[48:])
+}
+
+func element(buff []byte) (fe2, error) {
+	a := fe2{c0: new(big.Int).SetBytes(buff), c1: new(big.Int)}
+
+	if len(buff) == 96 {
+		a = fe2{c0: new(big.Int).SetBytes(buff[48:]), c1: new(big.Int).SetBytes(buff[:48])}
+	}
+
+	if a.c0.Cmp(fieldP) >= 0 || a.c1.Cmp(fieldP) >= 0 {
+		return a, xerrors.Wrapf(ErrEncoding, "coordinate not less than p")
+	}
+
+	return a, nil
+}
+
+// encode the zcash serialization, x or x || y with the flags in the top three bits
+func encode(p *point, size int, compressed bool) []byte {
+	buff := make([]byte, size)
+
+	var flags byte
+
+	if compressed {
+		flags = flagCompressed
+	}
+
+	if p.isInfinity() {
+		buff[0] = flags | flagInfinity
+
+		return buff
+	}
+
+	x, y := p.affine()
+
+	n := elementSize(size, compressed)
+
+	putElement(buff[:n], x)
+
+	if !compressed {
+		putElement(buff[n:], y)
+	} else if y.lexicographicallyLargest() {
+		flags |= flagLargest
+	}
+
+	buff[0] |= flags
+
+	return buff
+}
+
+// decode the zcash serialization of compressedSize compressed or twice uncompressed encoding
+func decode(buff []byte, compressedSize int, b fe2) (*point, error) {
+	compressed := len(buff) == compressedSize
+
+	if !compressed && len(buff) != 2*compressedSize {
+		return nil, xerrors.Wrapf(ErrEncoding, "length %d error", len(buff))
+	}
+
+	flags := buff[0] & flagMask
+
+	if (flags&flagCompressed != 0) != compressed {
+		return nil, xerrors.Wrapf(ErrEncoding, "compression flag error")
+	}
+
+	if !compressed && flags&flagLargest != 0 {
+		return nil, xerrors.Wrapf(ErrEncoding, "sort flag of uncompressed encoding")
+	}
+
+	data := append([]byte{}, buff...)
+
+	data[0] &^= flagMask
+
+	if flags&flagInfinity != 0 {
+		if flags&flagLargest != 0 {
+			return nil, xerrors.Wrapf(ErrEncoding, "sort flag of infinity")
+		}
+
+		for _, v := range data {
+			if v != 0 {
+				return nil, xerrors.Wrapf(ErrEncoding, "non zero infinity")
+			}
+		}
+
+		return infinity(), nil
+	}
+
+	n := elementSize(len(buff), compressed)
+
+	x, err := element(data[:n])
+
+	if err != nil {
+		return nil, err
+	}
+
+	var y fe2
+
+	if compressed {
+		var ok bool
+
+		y, ok = x.square().mul(x).add(b).sqrt()
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrEncoding, "x is not on the curve")
+		}
+
+		if y.lexicographicallyLargest() != (flags&flagLargest != 0) {
+			y = y.neg()
+		}
+	} else {
+		y, err = element(data[n:])
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p := affinePoint(x, y)
+
+	if !p.isOnCurve(b) {
+		return nil, xerrors.Wrapf(ErrEncoding, "point is not on the curve")
+	}
+
+	if !p.inSubgroup() {
+		return nil, ErrSubgroup
+	}
+
+	return p, nil
+}
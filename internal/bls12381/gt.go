@@ -0,0 +1,196 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+// fe12 element c0 + c1 w + ... + c5 w^5 of GF(p^12) = GF(p^2)[w] / (w^6 - xi), it is the tower
+// GF(p^6) = GF(p^2)[v] / (v^3 - xi) and GF(p^12) = GF(p^6)[w] / (w^2 - v) flattened
+type fe12 [6]fe2
+
+var (
+	// frobeniusCoeffs xi^(k (p - 1) / 6), w^p = w xi^((p - 1) / 6)
+	frobeniusCoeffs [6]fe2
+
+	// hardExp (x - 1)^2 / 3 of the hard part of the final exponentiation
+	hardExp *big.Int
+)
+
+func init() {
+	e := new(big.Int).Sub(fieldP, big.NewInt(1))
+
+	gamma := fe2Int(1, 1).exp(e.Div(e, big.NewInt(6)))
+
+	frobeniusCoeffs[0] = fe2One()
+
+	for k := 1; k < 6; k++ {
+		frobeniusCoeffs[k] = frobeniusCoeffs[k-1].mul(gamma)
+	}
+
+	hardExp = new(big.Int).Sub(curveX, big.NewInt(1))
+
+	hardExp.Mul(hardExp, hardExp).Div(hardExp, big.NewInt(3))
+}
+
+func fe12One() fe12 {
+	var a fe12
+
+	a[0] = fe2One()
+
+	for i := 1; i < 6; i++ {
+		a[i] = fe2Zero()
+	}
+
+	return a
+}
+
+func (a fe12) isOne() bool {
+	if !a[0].equal(fe2One()) {
+		return false
+	}
+
+	for i := 1; i < 6; i++ {
+		if !a[i].isZero() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a fe12) equal(b fe12) bool {
+	for i := range a {
+		if !a[i].equal(b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mul schoolbook product with lazy reduction, w^(6 + k) = xi w^k
+func (a fe12) mul(b fe12) fe12 {
+	var re, im [11]*big.Int
+
+	for k := range re {
+		re[k], im[k] = new(big.Int), new(big.Int)
+	}
+
+	t := new(big.Int)
+
+	for i := 0; i < 6; i++ {
+		if a[i].isZero() {
+			continue
+		}
+
+		for j := 0; j < 6; j++ {
+			if b[j].isZero() {
+				continue
+			}
+
+			re[i+j].Add(re[i+j], t.Mul(a[i].c0, b[j].c0))
+			re[i+j].Sub(re[i+j], t.Mul(a[i].c1, b[j].c1))
+			im[i+j].Add(im[i+j], t.Mul(a[i].c0, b[j].c1))
+			im[i+j].Add(im[i+j], t.Mul(a[i].c1, b[j].c0))
+		}
+	}
+
+	var c fe12
+
+	for k := 0; k < 6; k++ {
+		c0, c1 := re[k], im[k]
+
+		if k < 5 {
+			// (x0 + x1 u)(1 + u) = (x0 - x1) + (x0 + x1) u
+			c0.Add(c0, re[k+6]).Sub(c0, im[k+6])
+			c1.Add(c1, re[k+6]).Add(c1, im[k+6])
+		}
+
+		c[k] = fe2{c0: fpMod(c0), c1: fpMod(c1)}
+	}
+
+	return c
+}
+
+func (a fe12) square() fe12 {
+	return a.mul(a)
+}
+
+// conj the conjugate over GF(p^6), w -> -w, which is a^(p^6)
+func (a fe12) conj() fe12 {
+	c := a
+
+	for i := 1; i < 6; i += 2 {
+		c[i] = a[i].neg()
+	}
+
+	return c
+}
+
+// frobenius a^p
+func (a fe12) frobenius() fe12 {
+	var c fe12
+
+	for k := range a {
+		c[k] = a[k].conj().mul(frobeniusCoeffs[k])
+	}
+
+	return c
+}
+
+// inverse a^-1 = conj(a) / (a conj(a)), the norm a conj(a) lies in GF(p^6)
+func (a fe12) inverse() fe12 {
+	conj := a.conj()
+
+	norm := a.mul(conj)
+
+	// norm = n0 + n1 v + n2 v^2 with v = w^2
+	n0, n1, n2 := norm[0], norm[2], norm[4]
+
+	t0 := n0.square().sub(n1.mul(n2).mulXi())
+	t1 := n2.square().mulXi().sub(n0.mul(n1))
+	t2 := n1.square().sub(n0.mul(n2))
+
+	d := n0.mul(t0).add(n2.mul(t1).add(n1.mul(t2)).mulXi()).inverse()
+
+	inv := fe12One()
+
+	inv[0], inv[2], inv[4] = t0.mul(d), t1.mul(d), t2.mul(d)
+
+	return conj.mul(inv)
+}
+
+func (a fe12) exp(k *big.Int) fe12 {
+	result := fe12One()
+
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = result.square()
+
+		if k.Bit(i) == 1 {
+			result = result.mul(a)
+		}
+	}
+
+	return result
+}
+
+// expX a^x of the element of the cyclotomic subgroup, where the inverse is the conjugate
+func (a fe12) expX() fe12 {
+	return a.exp(new(big.Int).Neg(curveX)).conj()
+}
+
+// finalExponentiation a^((p^12 - 1) / r), the easy part (p^6 - 1)(p^2 + 1) by frobenius, the hard
+// part (p^4 - p^2 + 1) / r = (x - 1)^2 / 3 (x + p) (x^2 + p^2 - 1) + 1
+func (a fe12) finalExponentiation() fe12 {
+	t := a.conj().mul(a.inverse())
+
+	t = t.frobenius().frobenius().mul(t)
+
+	b := t.exp(hardExp)
+
+	b = b.expX().mul(b.frobenius())
+
+	b = b.expX().expX().mul(b.frobenius().frobenius()).mul(b.conj())
+
+	return b.mul(t)
+}
@@ -0,0 +1,179 @@
+package bls12381
+
+import (
+	"crypto/sha256"
+	"math/big"
+)
+
+var (
+	// simplified swu of the isogenous curve y^2 = x^3 + A' x + B', which is 3-isogenous to the G2 twist
+	sswuA = fe2Int(0, 240)
+	sswuB = fe2Int(1012, 1012)
+	sswuZ = fe2Int(-2, -1)
+
+	// the 3-isogeny map of rfc9380 appendix E.3, x = xNum / xDen, y = y' yNum / yDen
+	isoXNum = []fe2{
+		fe2Hex("5c759507e8e333ebb5b7a9a47d7ed8532c52d39fd3a042a88b58423c50ae15d5c2638e343d9c71c6238aaaaaaaa97d6", "5c759507e8e333ebb5b7a9a47d7ed8532c52d39fd3a042a88b58423c50ae15d5c2638e343d9c71c6238aaaaaaaa97d6"),
+		fe2Hex("0", "11560bf17baa99bc32126fced787c88f984f87adf7ae0c7f9a208c6b4f20a4181472aaa9cb8d555526a9ffffffffc71a"),
+		fe2Hex("11560bf17baa99bc32126fced787c88f984f87adf7ae0c7f9a208c6b4f20a4181472aaa9cb8d555526a9ffffffffc71e", "8ab05f8bdd54cde190937e76bc3e447cc27c3d6fbd7063fcd104635a790520c0a395554e5c6aaaa9354ffffffffe38d"),
+		fe2Hex("171d6541fa38ccfaed6dea691f5fb614cb14b4e7f4e810aa22d6108f142b85757098e38d0f671c7188e2aaaaaaaa5ed1", "0"),
+	}
+
+	isoXDen = []fe2{
+		fe2Hex("0", "1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaa63"),
+		fe2Hex("c", "1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaa9f"),
+		fe2One(),
+	}
+
+	isoYNum = []fe2{
+		fe2Hex("1530477c7ab4113b59a4c18b076d11930f7da5d4a07f649bf54439d87d27e500fc8c25ebf8c92f6812cfc71c71c6d706", "1530477c7ab4113b59a4c18b076d11930f7da5d4a07f649bf54439d87d27e500fc8c25ebf8c92f6812cfc71c71c6d706"),
+		fe2Hex("0", "5c759507e8e333ebb5b7a9a47d7ed8532c52d39fd3a042a88b58423c50ae15d5c2638e343d9c71c6238aaaaaaaa97be"),
+		fe2Hex("11560bf17baa99bc32126fced787c88f984f87adf7ae0c7f9a208c6b4f20a4181472aaa9cb8d555526a9ffffffffc71c", "8ab05f8bdd54cde190937e76bc3e447cc27c3d6fbd7063fcd104635a790520c0a395554e5c6aaaa9354ffffffffe38f"),
+		fe2Hex("124c9ad43b6cf79bfbf7043de3811ad0761b0f37a1e26286b0e977c69aa274524e79097a56dc4bd9e1b371c71c718b10", "0"),
+	}
+
+	isoYDen = []fe2{
+		fe2Hex("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffa8fb", "1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffa8fb"),
+		fe2Hex("0", "1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffa9d3"),
+		fe2Hex("12", "1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaa99"),
+		fe2One(),
+	}
+
+	// hEff the effective cofactor 3 (x^2 - 1) h2 that clears the cofactor h2 of the twist
+	hEff, _ = new(big.Int).SetString("bc69f08f2ee75b3584c6a0ea91b352888e2a8e9145ad7689986ff031508ffe1329c2f178731db956d82bf015d1212b02ec0ec69d7477c1ae954cbc06689f6a359894c0adebbf6b4e8020005aaa95551", 16)
+)
+
+// ExpandMessageXMD rfc9380 expand_message_xmd with sha256
+func ExpandMessageXMD(msg, dst []byte, length int) []byte {
+	if len(dst) > 255 {
+		h := sha256.Sum256(append([]byte("H2C-OVERSIZE-DST-"), dst...))
+
+		dst = h[:]
+	}
+
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	hasher := sha256.New()
+
+	hasher.Write(make([]byte, sha256.BlockSize))
+	hasher.Write(msg)
+	hasher.Write([]byte{byte(length >> 8), byte(length), 0})
+	hasher.Write(dstPrime)
+
+	b0 := hasher.Sum(nil)
+
+	var result, bi []byte
+
+	for i := 1; len(result) < length; i++ {
+		// b_i = H(strxor(b_0, b_(i - 1)) || i || dst_prime), b_1 = H(b_0 || 1 || dst_prime)
+		input := append([]byte{}, b0...)
+
+		for j := range bi {
+			input[j] ^= bi[j]
+		}
+
+		hasher.Reset()
+		hasher.Write(input)
+		hasher.Write([]byte{byte(i)})
+		hasher.Write(dstPrime)
+
+		bi = hasher.Sum(nil)
+
+		result = append(result, bi...)
+	}
+
+	return result[:length]
+}
+
+// hashToField two GF(p^2) elements with 64 bytes of each GF(p) element
+func hashToField(msg, dst []byte) [2]fe2 {
+	const l = 64
+
+	buff := ExpandMessageXMD(msg, dst, 4*l)
+
+	var u [2]fe2
+
+	for i := range u {
+		offset := 2 * l * i
+
+		u[i] = fe2{
+			c0: fpMod(new(big.Int).SetBytes(buff[offset : offset+l])),
+			c1: fpMod(new(big.Int).SetBytes(buff[offset+l : offset+2*l])),
+		}
+	}
+
+	return u
+}
+
+// mapToCurveSSWU simplified swu map of rfc9380 to the isogenous curve
+func mapToCurveSSWU(u fe2) (x, y fe2) {
+	zu2 := sswuZ.mul(u.square())
+
+	tv := zu2.square().add(zu2)
+
+	// x1 = (-B / A) (1 + 1 / (Z^2 u^4 + Z u^2)), or B / (Z A) if the denominator is zero
+	var x1 fe2
+
+	if tv.isZero() {
+		x1 = sswuB.mul(sswuZ.mul(sswuA).inverse())
+	} else {
+		x1 = sswuB.neg().mul(sswuA.inverse()).mul(fe2One().add(tv.inverse()))
+	}
+
+	gx := func(x fe2) fe2 {
+		return x.square().mul(x).add(sswuA.mul(x)).add(sswuB)
+	}
+
+	x = x1
+
+	y, ok := gx(x1).sqrt()
+
+	if !ok {
+		x = zu2.mul(x1)
+
+		// g(x2) = Z^3 u^6 g(x1) is a square if g(x1) is not
+		y, _ = gx(x).sqrt()
+	}
+
+	if u.sgn0() != y.sgn0() {
+		y = y.neg()
+	}
+
+	return x, y
+}
+
+func horner(coeffs []fe2, x fe2) fe2 {
+	result := coeffs[len(coeffs)-1]
+
+	for i := len(coeffs) - 2; i >= 0; i-- {
+		result = result.mul(x).add(coeffs[i])
+	}
+
+	return result
+}
+
+// iso3 the 3-isogeny from the isogenous curve to the G2 twist
+func iso3(x, y fe2) *point {
+	xDen := horner(isoXDen, x)
+	yDen := horner(isoYDen, x)
+
+	if xDen.isZero() || yDen.isZero() {
+		return infinity()
+	}
+
+	return affinePoint(
+		horner(isoXNum, x).mul(xDen.inverse()),
+		y.mul(horner(isoYNum, x)).mul(yDen.inverse()),
+	)
+}
+
+// HashToG2 rfc9380 hash_to_curve of the random oracle suite BLS12381G2_XMD:SHA-256_SSWU_RO_
+// with the domain separation tag
+func HashToG2(msg, dst []byte) *G2 {
+	u := hashToField(msg, dst)
+
+	q0 := iso3(mapToCurveSSWU(u[0]))
+	q1 := iso3(mapToCurveSSWU(u[1]))
+
+	return &G2{p: q0.add(q1).mult(hEff)}
+}
@@ -0,0 +1,102 @@
+package bls12381
+
+import (
+	"math/big"
+)
+
+// the G2 point (x, y) of the twist maps to (x w^-2, y w^-3) of E(GF(p^12)), the line through T with
+// slope lambda of E evaluated at P = (xp, yp) is yp - yT - lambda (xp - xT), the slope of E is the
+// slope of the twist times w^-1, and scaled by w^3, which the final exponentiation kills, the line is
+// (lambda xT - yT) + (-lambda xp) w^2 + yp w^3 with the twist coordinates
+func line(lambda, xt, yt fe2, xp, yp fe2) fe12 {
+	l := fe12{fe2Zero(), fe2Zero(), fe2Zero(), fe2Zero(), fe2Zero(), fe2Zero()}
+
+	l[0] = lambda.mul(xt).sub(yt)
+	l[2] = lambda.mul(xp).neg()
+	l[3] = yp
+
+	return l
+}
+
+// millerLoop the product of f_{|x|,Q}(P) of the pairs, T is kept in affine coordinates of the twist
+func millerLoop(ps []*point, qs []*point) fe12 {
+	type pair struct {
+		xp, yp fe2
+		xq, yq fe2
+		xt, yt fe2
+	}
+
+	var pairs []*pair
+
+	for i := range ps {
+		if ps[i].isInfinity() || qs[i].isInfinity() {
+			continue
+		}
+
+		xp, yp := ps[i].affine()
+		xq, yq := qs[i].affine()
+
+		pairs = append(pairs, &pair{xp: xp, yp: yp, xq: xq, yq: yq, xt: xq, yt: yq})
+	}
+
+	f := fe12One()
+
+	x := new(big.Int).Neg(curveX)
+
+	for i := x.BitLen() - 2; i >= 0; i-- {
+		f = f.square()
+
+		for _, pair := range pairs {
+			// lambda = 3 xT^2 / 2 yT
+			xt2 := pair.xt.square()
+
+			lambda := xt2.double().add(xt2).mul(pair.yt.double().inverse())
+
+			f = f.mul(line(lambda, pair.xt, pair.yt, pair.xp, pair.yp))
+
+			xt := lambda.square().sub(pair.xt.double())
+
+			pair.yt = lambda.mul(pair.xt.sub(xt)).sub(pair.yt)
+			pair.xt = xt
+		}
+
+		if x.Bit(i) == 0 {
+			continue
+		}
+
+		for _, pair := range pairs {
+			// lambda = (yQ - yT) / (xQ - xT)
+			lambda := pair.yq.sub(pair.yt).mul(pair.xq.sub(pair.xt).inverse())
+
+			f = f.mul(line(lambda, pair.xt, pair.yt, pair.xp, pair.yp))
+
+			xt := lambda.square().sub(pair.xt).sub(pair.xq)
+
+			pair.yt = lambda.mul(pair.xt.sub(xt)).sub(pair.yt)
+			pair.xt = xt
+		}
+	}
+
+	return f
+}
+
+// pairing the optimal ate pairing e(P, Q), the conjugate accounts for the negative x
+func pairing(p, q *point) fe12 {
+	return millerLoop([]*point{p}, []*point{q}).finalExponentiation().conj()
+}
+
+// PairingCheck check e(P_1, Q_1) * ... * e(P_n, Q_n) = 1 with one final exponentiation
+func PairingCheck(g1s []*G1, g2s []*G2) bool {
+	if len(g1s) != len(g2s) {
+		return false
+	}
+
+	ps := make([]*point, len(g1s))
+	qs := make([]*point, len(g2s))
+
+	for i := range g1s {
+		ps[i], qs[i] = g1s[i].p, g2s[i].p
+	}
+
+	return millerLoop(ps, qs).finalExponentiation().isOne()
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"errors"
 	"math/big"
 )
 
@@ -15,10 +16,79 @@ func PublicKeyBytes(pub *ecdsa.PublicKey) []byte {
 	return elliptic.Marshal(pub.Curve, pub.X, pub.Y)
 }
 
-// BytesToPublicKey .
+// CompressedPublicKeyBytes encode public key as 0x02/0x03 || x compressed format
+func CompressedPublicKeyBytes(pub *ecdsa.PublicKey) []byte {
+	if pub == nil || pub.X == nil || pub.Y == nil {
+		return nil
+	}
+
+	buff := make([]byte, 1+(pub.Curve.Params().BitSize+7)/8)
+
+	buff[0] = 0x02 | byte(pub.Y.Bit(0))
+
+	pub.X.FillBytes(buff[1:])
+
+	return buff
+}
+
+// DecompressY compute y of x on curve y^2 = x^3 + ax + b with the given parity,
+// a is -3 unless the curve implements A() *big.Int
+func DecompressY(curve elliptic.Curve, x *big.Int, odd bool) (*big.Int, error) {
+	params := curve.Params()
+
+	a := big.NewInt(-3)
+
+	if curveA, ok := curve.(interface{ A() *big.Int }); ok {
+		a = curveA.A()
+	}
+
+	// Y = +-sqrt(x^3 + ax + B)
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+	x3.Add(x3, new(big.Int).Mul(a, x))
+	x3.Add(x3, params.B)
+	x3.Mod(x3, params.P)
+
+	y := new(big.Int).ModSqrt(x3, params.P)
+
+	if y == nil {
+		return nil, errors.New("invalid square root")
+	}
+
+	if odd != (y.Bit(0) == 1) {
+		y.Sub(params.P, y)
+	}
+
+	// Verify that y-coord has expected parity, y is zero has no odd solution
+	if odd != (y.Bit(0) == 1) {
+		return nil, errors.New("ybit doesn't match oddness")
+	}
+
+	return y, nil
+}
+
+// BytesToPublicKey decode uncompressed or compressed public key, return nil if the point is invalid
 func BytesToPublicKey(curve elliptic.Curve, buff []byte) *ecdsa.PublicKey {
 
-	x, y := elliptic.Unmarshal(curve, buff)
+	var x, y *big.Int
+
+	if len(buff) == 1+(curve.Params().BitSize+7)/8 && (buff[0] == 0x02 || buff[0] == 0x03) {
+		x = new(big.Int).SetBytes(buff[1:])
+
+		if x.Cmp(curve.Params().P) >= 0 {
+			return nil
+		}
+
+		var err error
+
+		y, err = DecompressY(curve, x, buff[0] == 0x03)
+
+		if err != nil || !curve.IsOnCurve(x, y) {
+			return nil
+		}
+	} else {
+		x, y = elliptic.Unmarshal(curve, buff)
+	}
 
 	if x == nil {
 		return nil
@@ -0,0 +1,205 @@
+// Package ed25519x ed25519 key and provider shared by ed25519 based chains
+package ed25519x
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+)
+
+// Errors
+var (
+	ErrPrivateKey = errors.New("ed25519 private key must be 32 bytes seed or 64 bytes seed||pubkey")
+)
+
+// Addresser ed25519 based chain address codec
+type Addresser interface {
+	Name() string                                    // driver name
+	PublicKeyToAddress(pub ed25519.PublicKey) string // address display string
+	ValidAddress(address string) bool                // check address format
+}
+
+// NewProvider create ed25519 key provider with chain address codec
+func NewProvider(addresser Addresser) key.Provider {
+	return &providerIml{addresser: addresser}
+}
+
+// SeedFromBytes get 32 bytes seed from seed or 64 bytes seed||pubkey private key, the public key
+// half of the 64 bytes private key must match the seed
+func SeedFromBytes(priKey []byte) ([]byte, error) {
+	switch len(priKey) {
+	case ed25519.SeedSize:
+		return append([]byte(nil), priKey...), nil
+	case ed25519.PrivateKeySize:
+		seed := priKey[:ed25519.SeedSize]
+
+		pubkey := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+
+		if !bytes.Equal(pubkey, priKey[ed25519.SeedSize:]) {
+			return nil, xerrors.Wrapf(ErrPrivateKey, "public key mismatch")
+		}
+
+		return append([]byte(nil), seed...), nil
+	default:
+		return nil, xerrors.Wrapf(ErrPrivateKey, "length %d error", len(priKey))
+	}
+}
+
+type keyImpl struct {
+	provider *providerIml
+	key      ed25519.PrivateKey
+	address  string // address
+}
+
+func (key *keyImpl) Address() string {
+	return key.address
+}
+
+func (key *keyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+// PriKey the 32 bytes ed25519 seed
+func (key *keyImpl) PriKey() []byte {
+	return key.key.Seed()
+}
+
+func (key *keyImpl) PubKey() []byte {
+	return []byte(key.key.Public().(ed25519.PublicKey))
+}
+
+// CryptoPrivateKey the standard library private key
+func (key *keyImpl) CryptoPrivateKey() crypto.PrivateKey {
+	return key.key
+}
+
+func (key *keyImpl) PublicKey() key.PublicKey {
+	return &publicKeyImpl{
+		provider: key.provider,
+		key:      key.key.Public().(ed25519.PublicKey),
+		address:  key.address,
+	}
+}
+
+// SetBytes set 32 bytes ed25519 seed or 64 bytes seed||pubkey private key, any other input is
+// ignored and the previous key is kept, use SeedFromBytes first to check the input
+func (key *keyImpl) SetBytes(priKey []byte) {
+	seed, err := SeedFromBytes(priKey)
+
+	if err != nil {
+		return
+	}
+
+	key.key = ed25519.NewKeyFromSeed(seed)
+
+	key.address = key.provider.addresser.PublicKeyToAddress(key.key.Public().(ed25519.PublicKey))
+}
+
+// Sign ed25519 sign the message, the message is hashed by ed25519 itself
+func (key *keyImpl) Sign(hashed []byte) ([]byte, error) {
+	return ed25519.Sign(key.key, hashed), nil
+}
+
+type publicKeyImpl struct {
+	provider *providerIml
+	key      ed25519.PublicKey
+	address  string // address
+}
+
+func (key *publicKeyImpl) Address() string {
+	return key.address
+}
+
+func (key *publicKeyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *publicKeyImpl) Bytes() []byte {
+	return []byte(key.key)
+}
+
+// Compressed ed25519 public key has only one 32 bytes encoding
+func (key *publicKeyImpl) Compressed() []byte {
+	return []byte(key.key)
+}
+
+// Uncompressed ed25519 public key has only one 32 bytes encoding
+func (key *publicKeyImpl) Uncompressed() []byte {
+	return []byte(key.key)
+}
+
+// CryptoPublicKey the standard library public key
+func (key *publicKeyImpl) CryptoPublicKey() crypto.PublicKey {
+	return key.key
+}
+
+func (key *publicKeyImpl) Verify(sig []byte, hash []byte) bool {
+	return len(sig) == ed25519.SignatureSize && ed25519.Verify(key.key, hash, sig)
+}
+
+type providerIml struct {
+	addresser Addresser
+}
+
+func (provider *providerIml) Name() string {
+	return provider.addresser.Name()
+}
+
+// HashMessage ed25519 hashes the message itself, the message is signed as is
+func (provider *providerIml) HashMessage(message []byte) []byte {
+	return message
+}
+
+func (provider *providerIml) New() (key.Key, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "ed25519 GenerateKey error")
+	}
+
+	return &keyImpl{
+		provider: provider,
+		key:      privateKey,
+		address:  provider.addresser.PublicKeyToAddress(privateKey.Public().(ed25519.PublicKey)),
+	}, nil
+}
+
+func (provider *providerIml) PublicKey(pubkey []byte) (key.PublicKey, error) {
+	if len(pubkey) != ed25519.PublicKeySize {
+		return nil, xerrors.Wrapf(key.ErrPublicKey, "ed25519 public key length %d error", len(pubkey))
+	}
+
+	publicKey := ed25519.PublicKey(append([]byte(nil), pubkey...))
+
+	return &publicKeyImpl{
+		provider: provider,
+		key:      publicKey,
+		address:  provider.addresser.PublicKeyToAddress(publicKey),
+	}, nil
+}
+
+func (provider *providerIml) Verify(pubkey []byte, sig []byte, hash []byte) bool {
+	if len(pubkey) != ed25519.PublicKeySize || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+
+	return ed25519.Verify(ed25519.PublicKey(pubkey), hash, sig)
+}
+
+func (provider *providerIml) PublicKeyToAddress(pubkey []byte) (string, error) {
+	if len(pubkey) != ed25519.PublicKeySize {
+		return "", xerrors.Wrapf(key.ErrPublicKey, "ed25519 public key length %d error", len(pubkey))
+	}
+
+	return provider.addresser.PublicKeyToAddress(ed25519.PublicKey(pubkey)), nil
+}
+
+func (provider *providerIml) ValidAddress(address string) bool {
+	return provider.addresser.ValidAddress(address)
+}
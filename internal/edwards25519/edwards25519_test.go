@@ -0,0 +1,144 @@
+package edwards25519
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func feToBig(v *fieldElement) *big.Int {
+	buff := v.bytes()
+
+	reverse(buff[:])
+
+	return new(big.Int).SetBytes(buff[:])
+}
+
+func randomBig(t *testing.T, max *big.Int) *big.Int {
+	v, err := rand.Int(rand.Reader, max)
+
+	require.NoError(t, err)
+
+	return v
+}
+
+func TestField(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		a := randomBig(t, fieldP)
+		b := randomBig(t, fieldP)
+
+		fa, fb := feFromBig(a), feFromBig(b)
+
+		var z fieldElement
+
+		require.Equal(t, new(big.Int).Mod(new(big.Int).Add(a, b), fieldP), feToBig(z.add(&fa, &fb)))
+
+		require.Equal(t, new(big.Int).Mod(new(big.Int).Sub(a, b), fieldP), feToBig(z.sub(&fa, &fb)))
+
+		require.Equal(t, new(big.Int).Mod(new(big.Int).Mul(a, b), fieldP), feToBig(z.mul(&fa, &fb)))
+
+		require.Equal(t, new(big.Int).ModInverse(a, fieldP), feToBig(z.invert(&fa)))
+	}
+
+	// p + 1 is not canonical and reduces to one
+	var v fieldElement
+
+	buff := []byte{0xee}
+
+	buff = append(buff, make([]byte, 30)...)
+
+	buff = append(buff, 0x7f)
+
+	for i := 1; i < 31; i++ {
+		buff[i] = 0xff
+	}
+
+	require.Equal(t, big.NewInt(1), feToBig(v.setBytes(buff)))
+}
+
+func TestScalarBaseMult(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		seed := make([]byte, ed25519.SeedSize)
+
+		_, err := rand.Read(seed)
+
+		require.NoError(t, err)
+
+		digest := sha512.Sum512(seed)
+
+		digest[0] &= 248
+		digest[31] &= 127
+		digest[31] |= 64
+
+		p := new(Point).ScalarBaseMult(digest[:32])
+
+		require.Equal(t, []byte(ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)), p.Bytes())
+
+		q, err := new(Point).SetBytes(p.Bytes())
+
+		require.NoError(t, err)
+
+		require.Equal(t, 1, q.Equal(p))
+
+		require.Equal(t, 1, q.IsTorsionFree())
+
+		require.Equal(t, 1, new(Point).Subtract(p, q).IsIdentity())
+	}
+
+	require.Equal(t, 1, NewIdentityPoint().IsTorsionFree())
+
+	// (0, -1) has order 2
+	minusOne := make([]byte, 32)
+
+	minusOne[0] = 0xec
+
+	for i := 1; i < 31; i++ {
+		minusOne[i] = 0xff
+	}
+
+	minusOne[31] = 0x7f
+
+	p, err := new(Point).SetBytes(minusOne)
+
+	require.NoError(t, err)
+
+	require.Equal(t, 0, p.IsTorsionFree())
+
+	require.Equal(t, 1, new(Point).MultByCofactor(p).IsIdentity())
+
+	// y = 2 is not on the curve
+	_, err = new(Point).SetBytes(append([]byte{2}, make([]byte, 31)...))
+
+	require.Error(t, err)
+}
+
+func TestScalarMult(t *testing.T) {
+	a := randomBig(t, L)
+	b := randomBig(t, L)
+
+	scalar := func(v *big.Int) []byte {
+		buff := new(big.Int).Mod(v, L).FillBytes(make([]byte, 32))
+
+		reverse(buff)
+
+		return buff
+	}
+
+	// a * (b * B) = (a * b) * B
+	p := new(Point).ScalarMult(scalar(a), new(Point).ScalarBaseMult(scalar(b)))
+
+	q := new(Point).ScalarBaseMult(scalar(new(big.Int).Mul(a, b)))
+
+	require.Equal(t, p.Bytes(), q.Bytes())
+
+	// a * B + b * B = (a + b) * B
+	p.Add(new(Point).ScalarBaseMult(scalar(a)), new(Point).ScalarBaseMult(scalar(b)))
+
+	q.ScalarBaseMult(scalar(new(big.Int).Add(a, b)))
+
+	require.Equal(t, 1, p.Equal(q))
+}
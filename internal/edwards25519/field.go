@@ -0,0 +1,292 @@
+package edwards25519
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+// fieldElement element of GF(2^255 - 19) in five 51 bits limbs little endian, the limbs may exceed
+// 51 bits by a few bits between operations, all arithmetic runs in constant time
+type fieldElement [5]uint64
+
+const maskLow51Bits = (1 << 51) - 1
+
+var fieldP, _ = new(big.Int).SetString("7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed", 16)
+
+var (
+	feZero = fieldElement{}
+	feOne  = fieldElement{1}
+)
+
+// reverse convert between big endian and little endian in place
+func reverse(buff []byte) {
+	for i, j := 0, len(buff)-1; i < j; i, j = i+1, j-1 {
+		buff[i], buff[j] = buff[j], buff[i]
+	}
+}
+
+func feFromBig(v *big.Int) fieldElement {
+	var buff [32]byte
+
+	new(big.Int).Mod(v, fieldP).FillBytes(buff[:])
+
+	reverse(buff[:])
+
+	var fe fieldElement
+
+	fe.setBytes(buff[:])
+
+	return fe
+}
+
+// setBytes set 32 bytes little endian value, the top bit is ignored and non canonical values
+// are accepted as is
+func (v *fieldElement) setBytes(x []byte) *fieldElement {
+	v[0] = binary.LittleEndian.Uint64(x[0:8]) & maskLow51Bits
+	v[1] = (binary.LittleEndian.Uint64(x[6:14]) >> 3) & maskLow51Bits
+	v[2] = (binary.LittleEndian.Uint64(x[12:20]) >> 6) & maskLow51Bits
+	v[3] = (binary.LittleEndian.Uint64(x[19:27]) >> 1) & maskLow51Bits
+	v[4] = (binary.LittleEndian.Uint64(x[24:32]) >> 12) & maskLow51Bits
+
+	return v
+}
+
+// bytes canonical 32 bytes little endian encoding
+func (v *fieldElement) bytes() [32]byte {
+	t := *v
+
+	t.reduce()
+
+	var buff [32]byte
+
+	binary.LittleEndian.PutUint64(buff[0:8], t[0]|t[1]<<51)
+	binary.LittleEndian.PutUint64(buff[8:16], t[1]>>13|t[2]<<38)
+	binary.LittleEndian.PutUint64(buff[16:24], t[2]>>26|t[3]<<25)
+	binary.LittleEndian.PutUint64(buff[24:32], t[3]>>39|t[4]<<12)
+
+	return buff
+}
+
+// carryPropagate bring the limbs back to 51 bits, the carry of the top limb wraps around times 19
+func (v *fieldElement) carryPropagate() *fieldElement {
+	c0 := v[0] >> 51
+	c1 := v[1] >> 51
+	c2 := v[2] >> 51
+	c3 := v[3] >> 51
+	c4 := v[4] >> 51
+
+	v[0] = v[0]&maskLow51Bits + c4*19
+	v[1] = v[1]&maskLow51Bits + c0
+	v[2] = v[2]&maskLow51Bits + c1
+	v[3] = v[3]&maskLow51Bits + c2
+	v[4] = v[4]&maskLow51Bits + c3
+
+	return v
+}
+
+// reduce fully reduce to the canonical value in [0, p)
+func (v *fieldElement) reduce() *fieldElement {
+	v.carryPropagate()
+
+	// q is 1 if v >= p, computed from the carry of v + 19
+	q := (v[0] + 19) >> 51
+	q = (v[1] + q) >> 51
+	q = (v[2] + q) >> 51
+	q = (v[3] + q) >> 51
+	q = (v[4] + q) >> 51
+
+	v[0] += 19 * q
+
+	v[1] += v[0] >> 51
+	v[0] &= maskLow51Bits
+	v[2] += v[1] >> 51
+	v[1] &= maskLow51Bits
+	v[3] += v[2] >> 51
+	v[2] &= maskLow51Bits
+	v[4] += v[3] >> 51
+	v[3] &= maskLow51Bits
+	v[4] &= maskLow51Bits
+
+	return v
+}
+
+func (v *fieldElement) add(a, b *fieldElement) *fieldElement {
+	v[0] = a[0] + b[0]
+	v[1] = a[1] + b[1]
+	v[2] = a[2] + b[2]
+	v[3] = a[3] + b[3]
+	v[4] = a[4] + b[4]
+
+	return v.carryPropagate()
+}
+
+// sub a - b computed as a + 2p - b so the limbs never underflow
+func (v *fieldElement) sub(a, b *fieldElement) *fieldElement {
+	v[0] = (a[0] + 0xFFFFFFFFFFFDA) - b[0]
+	v[1] = (a[1] + 0xFFFFFFFFFFFFE) - b[1]
+	v[2] = (a[2] + 0xFFFFFFFFFFFFE) - b[2]
+	v[3] = (a[3] + 0xFFFFFFFFFFFFE) - b[3]
+	v[4] = (a[4] + 0xFFFFFFFFFFFFE) - b[4]
+
+	return v.carryPropagate()
+}
+
+func (v *fieldElement) neg(a *fieldElement) *fieldElement {
+	return v.sub(&feZero, a)
+}
+
+// uint128 accumulator of the limb products
+type uint128 struct {
+	lo, hi uint64
+}
+
+func (v *uint128) addMul(a, b uint64) {
+	hi, lo := bits.Mul64(a, b)
+
+	var carry uint64
+
+	v.lo, carry = bits.Add64(v.lo, lo, 0)
+	v.hi, _ = bits.Add64(v.hi, hi, carry)
+}
+
+func (v *uint128) shiftRightBy51() uint64 {
+	return v.hi<<13 | v.lo>>51
+}
+
+// mul schoolbook multiplication, the limbs above 2^255 are folded back times 19
+func (v *fieldElement) mul(a, b *fieldElement) *fieldElement {
+	a1x19 := a[1] * 19
+	a2x19 := a[2] * 19
+	a3x19 := a[3] * 19
+	a4x19 := a[4] * 19
+
+	var r0, r1, r2, r3, r4 uint128
+
+	r0.addMul(a[0], b[0])
+	r0.addMul(a1x19, b[4])
+	r0.addMul(a2x19, b[3])
+	r0.addMul(a3x19, b[2])
+	r0.addMul(a4x19, b[1])
+
+	r1.addMul(a[0], b[1])
+	r1.addMul(a[1], b[0])
+	r1.addMul(a2x19, b[4])
+	r1.addMul(a3x19, b[3])
+	r1.addMul(a4x19, b[2])
+
+	r2.addMul(a[0], b[2])
+	r2.addMul(a[1], b[1])
+	r2.addMul(a[2], b[0])
+	r2.addMul(a3x19, b[4])
+	r2.addMul(a4x19, b[3])
+
+	r3.addMul(a[0], b[3])
+	r3.addMul(a[1], b[2])
+	r3.addMul(a[2], b[1])
+	r3.addMul(a[3], b[0])
+	r3.addMul(a4x19, b[4])
+
+	r4.addMul(a[0], b[4])
+	r4.addMul(a[1], b[3])
+	r4.addMul(a[2], b[2])
+	r4.addMul(a[3], b[1])
+	r4.addMul(a[4], b[0])
+
+	c0 := r0.shiftRightBy51()
+	c1 := r1.shiftRightBy51()
+	c2 := r2.shiftRightBy51()
+	c3 := r3.shiftRightBy51()
+	c4 := r4.shiftRightBy51()
+
+	v[0] = r0.lo&maskLow51Bits + c4*19
+	v[1] = r1.lo&maskLow51Bits + c0
+	v[2] = r2.lo&maskLow51Bits + c1
+	v[3] = r3.lo&maskLow51Bits + c2
+	v[4] = r4.lo&maskLow51Bits + c3
+
+	return v.carryPropagate()
+}
+
+func (v *fieldElement) square(a *fieldElement) *fieldElement {
+	return v.mul(a, a)
+}
+
+// pow a^e by square and multiply over the bits of the public exponent e
+func (v *fieldElement) pow(a *fieldElement, e *big.Int) *fieldElement {
+	x := *a
+	z := feOne
+
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		z.square(&z)
+
+		if e.Bit(i) == 1 {
+			z.mul(&z, &x)
+		}
+	}
+
+	*v = z
+
+	return v
+}
+
+var (
+	invertExp = new(big.Int).Sub(fieldP, big.NewInt(2))                      // p - 2
+	sqrtExp   = new(big.Int).Rsh(new(big.Int).Sub(fieldP, big.NewInt(5)), 3) // (p - 5) / 8
+)
+
+// invert a^(p-2), zero maps to zero
+func (v *fieldElement) invert(a *fieldElement) *fieldElement {
+	return v.pow(a, invertExp)
+}
+
+// selectFe v = a if flag is 1 else b
+func (v *fieldElement) selectFe(a, b *fieldElement, flag int) *fieldElement {
+	m := -uint64(flag)
+
+	for i := range v {
+		v[i] = (m & a[i]) | (^m & b[i])
+	}
+
+	return v
+}
+
+func (v *fieldElement) equal(u *fieldElement) int {
+	a, b := v.bytes(), u.bytes()
+
+	return subtle.ConstantTimeCompare(a[:], b[:])
+}
+
+func (v *fieldElement) isNegative() int {
+	buff := v.bytes()
+
+	return int(buff[0] & 1)
+}
+
+// sqrtRatio compute sqrt(u / w) with the non negative root, the flag is 0 if u / w is not square
+func (v *fieldElement) sqrtRatio(u, w *fieldElement) (*fieldElement, int) {
+	var w3, w7, x, check, negU, t fieldElement
+
+	w3.mul(w3.square(w), w)
+	w7.mul(w7.square(&w3), w)
+
+	// x = u * w^3 * (u * w^7)^((p - 5) / 8)
+	x.mul(x.mul(u, &w3), t.pow(t.mul(u, &w7), sqrtExp))
+
+	check.mul(w, check.square(&x))
+
+	negU.neg(u)
+
+	correct := check.equal(u)
+	flipped := check.equal(&negU)
+
+	x.selectFe(t.mul(&x, &sqrtM1), &x, flipped)
+
+	// choose the non negative root
+	x.selectFe(t.neg(&x), &x, x.isNegative())
+
+	*v = x
+
+	return v, correct | flipped
+}
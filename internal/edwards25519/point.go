@@ -0,0 +1,274 @@
+// Package edwards25519 the prime order group of ed25519 over the twisted edwards curve
+// -x^2 + y^2 = 1 + d x^2 y^2, points use extended coordinates with the complete addition formula
+// and the scalar multiplication runs in constant time
+package edwards25519
+
+import (
+	"crypto/subtle"
+	"errors"
+	"math/big"
+)
+
+// Errors
+var (
+	ErrEncoding = errors.New("invalid edwards25519 point encoding")
+)
+
+var (
+	// L the order of the prime order subgroup 2^252 + 27742317777372353535851937790883648493
+	L, _ = new(big.Int).SetString("1000000000000000000000000000000014def9dea2f79cd65812631a5cf5d3ed", 16)
+
+	feD    fieldElement // d = -121665 / 121666
+	feD2   fieldElement // 2d
+	sqrtM1 fieldElement // sqrt(-1) = 2^((p - 1) / 4)
+
+	generator Point
+)
+
+func init() {
+	d := new(big.Int).ModInverse(big.NewInt(121666), fieldP)
+
+	d.Mul(d, big.NewInt(-121665))
+
+	feD = feFromBig(d)
+	feD2 = feFromBig(d.Lsh(d, 1))
+
+	sqrtM1 = feFromBig(new(big.Int).Exp(big.NewInt(2), new(big.Int).Rsh(new(big.Int).Sub(fieldP, big.NewInt(1)), 2), fieldP))
+
+	// the base point has y = 4 / 5 and non negative x
+	y := new(big.Int).ModInverse(big.NewInt(5), fieldP)
+
+	y.Mul(y, big.NewInt(4))
+
+	fe := feFromBig(y)
+
+	buff := fe.bytes()
+
+	if _, err := generator.SetBytes(buff[:]); err != nil {
+		panic(err)
+	}
+}
+
+// Point edwards25519 point in extended coordinates x = X / Z, y = Y / Z, x * y = T / Z,
+// the zero value is not a valid point
+type Point struct {
+	x, y, z, t fieldElement
+}
+
+// NewIdentityPoint the neutral element (0, 1)
+func NewIdentityPoint() *Point {
+	return &Point{y: feOne, z: feOne}
+}
+
+// NewGeneratorPoint the ed25519 base point
+func NewGeneratorPoint() *Point {
+	p := generator
+
+	return &p
+}
+
+// Set v = u
+func (v *Point) Set(u *Point) *Point {
+	*v = *u
+
+	return v
+}
+
+// SetBytes rfc8032 5.1.3 decoding of 32 bytes encoding, non canonical y is rejected, the point may
+// have small order component, check it with IsTorsionFree
+func (v *Point) SetBytes(x []byte) (*Point, error) {
+	if len(x) != 32 {
+		return nil, ErrEncoding
+	}
+
+	var y fieldElement
+
+	y.setBytes(x)
+
+	// reject y >= p
+	canonical := y.bytes()
+
+	canonical[31] |= x[31] & 0x80
+
+	if subtle.ConstantTimeCompare(canonical[:], x) != 1 {
+		return nil, ErrEncoding
+	}
+
+	var u, w, yy fieldElement
+
+	// x^2 = (y^2 - 1) / (d y^2 + 1)
+	yy.square(&y)
+
+	u.sub(&yy, &feOne)
+	w.add(w.mul(&yy, &feD), &feOne)
+
+	xx, ok := new(fieldElement).sqrtRatio(&u, &w)
+
+	if ok != 1 {
+		return nil, ErrEncoding
+	}
+
+	sign := int(x[31] >> 7)
+
+	if sign == 1 && xx.equal(&feZero) == 1 {
+		return nil, ErrEncoding
+	}
+
+	var negX fieldElement
+
+	xx.selectFe(negX.neg(xx), xx, sign)
+
+	v.x = *xx
+	v.y = y
+	v.z = feOne
+	v.t.mul(xx, &y)
+
+	return v, nil
+}
+
+// Bytes rfc8032 5.1.2 encoding, y with the sign of x in the top bit
+func (v *Point) Bytes() []byte {
+	var zInv, x, y fieldElement
+
+	zInv.invert(&v.z)
+
+	x.mul(&v.x, &zInv)
+	y.mul(&v.y, &zInv)
+
+	buff := y.bytes()
+
+	buff[31] |= byte(x.isNegative() << 7)
+
+	return buff[:]
+}
+
+// Add v = p + q, complete formula add-2008-hwcd-3 of a = -1 twisted edwards curves
+func (v *Point) Add(p, q *Point) *Point {
+	var a, b, c, d, e, f, g, h, t fieldElement
+
+	a.mul(a.sub(&p.y, &p.x), t.sub(&q.y, &q.x))
+	b.mul(b.add(&p.y, &p.x), t.add(&q.y, &q.x))
+	c.mul(c.mul(&p.t, &feD2), &q.t)
+	d.mul(&p.z, &q.z)
+	d.add(&d, &d)
+
+	e.sub(&b, &a)
+	f.sub(&d, &c)
+	g.add(&d, &c)
+	h.add(&b, &a)
+
+	v.x.mul(&e, &f)
+	v.y.mul(&g, &h)
+	v.t.mul(&e, &h)
+	v.z.mul(&f, &g)
+
+	return v
+}
+
+// Negate v = -p
+func (v *Point) Negate(p *Point) *Point {
+	v.x.neg(&p.x)
+	v.y = p.y
+	v.z = p.z
+	v.t.neg(&p.t)
+
+	return v
+}
+
+// Subtract v = p - q
+func (v *Point) Subtract(p, q *Point) *Point {
+	var neg Point
+
+	return v.Add(p, neg.Negate(q))
+}
+
+// Equal return 1 if v and u are the same point, compare X1 Z2 = X2 Z1 and Y1 Z2 = Y2 Z1
+func (v *Point) Equal(u *Point) int {
+	var t1, t2, t3, t4 fieldElement
+
+	t1.mul(&v.x, &u.z)
+	t2.mul(&u.x, &v.z)
+	t3.mul(&v.y, &u.z)
+	t4.mul(&u.y, &v.z)
+
+	return t1.equal(&t2) & t3.equal(&t4)
+}
+
+func (v *Point) selectPoint(a, b *Point, flag int) *Point {
+	v.x.selectFe(&a.x, &b.x, flag)
+	v.y.selectFe(&a.y, &b.y, flag)
+	v.z.selectFe(&a.z, &b.z, flag)
+	v.t.selectFe(&a.t, &b.t, flag)
+
+	return v
+}
+
+// ScalarMult v = k * p for 32 bytes little endian scalar k, the scalar is not reduced, fixed
+// 4 bits windows with constant time table lookup
+func (v *Point) ScalarMult(k []byte, p *Point) *Point {
+	var table [16]Point
+
+	table[0] = *NewIdentityPoint()
+
+	for i := 1; i < 16; i++ {
+		table[i].Add(&table[i-1], p)
+	}
+
+	result := NewIdentityPoint()
+
+	var selected Point
+
+	for i := 63; i >= 0; i-- {
+		for j := 0; j < 4; j++ {
+			result.Add(result, result)
+		}
+
+		var window int
+
+		if i/2 < len(k) {
+			window = int(k[i/2]>>(4*uint(i%2))) & 0xf
+		}
+
+		selected = table[0]
+
+		for j := 1; j < 16; j++ {
+			selected.selectPoint(&table[j], &selected, subtle.ConstantTimeByteEq(uint8(j), uint8(window)))
+		}
+
+		result.Add(result, &selected)
+	}
+
+	return v.Set(result)
+}
+
+// ScalarBaseMult v = k * B for 32 bytes little endian scalar k
+func (v *Point) ScalarBaseMult(k []byte) *Point {
+	return v.ScalarMult(k, &generator)
+}
+
+// MultByCofactor v = 8 * p
+func (v *Point) MultByCofactor(p *Point) *Point {
+	v.Set(p)
+
+	for i := 0; i < 3; i++ {
+		v.Add(v, v)
+	}
+
+	return v
+}
+
+// IsIdentity return 1 if v is the neutral element
+func (v *Point) IsIdentity() int {
+	return v.Equal(NewIdentityPoint())
+}
+
+// IsTorsionFree return 1 if v is in the prime order subgroup, L * v is the identity
+func (v *Point) IsTorsionFree() int {
+	var buff [32]byte
+
+	L.FillBytes(buff[:])
+
+	reverse(buff[:])
+
+	return new(Point).ScalarMult(buff[:], v).IsIdentity()
+}
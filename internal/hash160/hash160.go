@@ -17,3 +17,8 @@ func calcHash(buf []byte, hasher hash.Hash) []byte {
 func Hash160(buf []byte) []byte {
 	return calcHash(calcHash(buf, sha256.New()), ripemd160.New())
 }
+
+// DoubleHash calculates the hash sha256(sha256(b)).
+func DoubleHash(buf []byte) []byte {
+	return calcHash(calcHash(buf, sha256.New()), sha256.New())
+}
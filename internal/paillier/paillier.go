@@ -0,0 +1,211 @@
+// Package paillier paillier cryptosystem over a modulus of safe primes, the modulus doubles as
+// ring pedersen commitment parameters for the zero knowledge range proofs of threshold ecdsa
+package paillier
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrMessage    = errors.New("message out of range")
+	ErrCiphertext = errors.New("invalid ciphertext")
+	ErrPrime      = errors.New("invalid safe prime")
+)
+
+var (
+	one = big.NewInt(1)
+	two = big.NewInt(2)
+)
+
+// PublicKey paillier public key with generator N + 1
+type PublicKey struct {
+	N *big.Int `json:"n"`
+}
+
+// PrivateKey paillier private key, P and Q are safe primes
+type PrivateKey struct {
+	PublicKey
+	P *big.Int `json:"p"`
+	Q *big.Int `json:"q"`
+}
+
+var smallPrimes = []uint64{
+	3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73, 79, 83, 89, 97,
+	101, 103, 107, 109, 113, 127, 131, 137, 139, 149, 151, 157, 163, 167, 173, 179, 181, 191, 193, 197, 199,
+	211, 223, 227, 229, 233, 239, 241, 251,
+}
+
+// SafePrime generate safe prime p = 2q + 1 of bits length with prime q, the two top bits are set
+// so the product of two such primes has exactly 2 * bits length, it takes seconds
+func SafePrime(random io.Reader, bits int) (*big.Int, error) {
+	max := new(big.Int).Lsh(one, uint(bits-1))
+
+	m := new(big.Int)
+
+	for {
+		q, err := rand.Int(random, max)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "read random error")
+		}
+
+		q.SetBit(q, bits-2, 1)
+		q.SetBit(q, bits-3, 1)
+		q.SetBit(q, 0, 1)
+
+		// sieve both q and 2q + 1
+		candidate := true
+
+		for _, sp := range smallPrimes {
+			r := m.Mod(q, new(big.Int).SetUint64(sp)).Uint64()
+
+			if r == 0 || (2*r+1)%sp == 0 {
+				candidate = false
+				break
+			}
+		}
+
+		if !candidate || !q.ProbablyPrime(1) {
+			continue
+		}
+
+		p := new(big.Int).Lsh(q, 1)
+		p.Add(p, one)
+
+		if p.ProbablyPrime(20) && q.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// GenerateKey generate paillier key of two safe primes with bits / 2 length each
+func GenerateKey(random io.Reader, bits int) (*PrivateKey, error) {
+	for {
+		p, err := SafePrime(random, bits/2)
+
+		if err != nil {
+			return nil, err
+		}
+
+		q, err := SafePrime(random, bits/2)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if p.Cmp(q) != 0 {
+			return NewPrivateKey(p, q)
+		}
+	}
+}
+
+// NewPrivateKey create paillier key from safe primes
+func NewPrivateKey(p, q *big.Int) (*PrivateKey, error) {
+	for _, v := range []*big.Int{p, q} {
+		if !v.ProbablyPrime(20) || !new(big.Int).Rsh(v, 1).ProbablyPrime(20) {
+			return nil, xerrors.Wrapf(ErrPrime, "%s is not a safe prime", v.Text(16))
+		}
+	}
+
+	if p.Cmp(q) == 0 {
+		return nil, xerrors.Wrapf(ErrPrime, "same primes")
+	}
+
+	return &PrivateKey{
+		PublicKey: PublicKey{N: new(big.Int).Mul(p, q)},
+		P:         p,
+		Q:         q,
+	}, nil
+}
+
+// N2 N^2
+func (pub *PublicKey) N2() *big.Int {
+	return new(big.Int).Mul(pub.N, pub.N)
+}
+
+// RandomUnit random element of Z*_N
+func RandomUnit(random io.Reader, n *big.Int) (*big.Int, error) {
+	for {
+		r, err := rand.Int(random, n)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "read random error")
+		}
+
+		if r.Sign() > 0 && new(big.Int).GCD(nil, nil, r, n).Cmp(one) == 0 {
+			return r, nil
+		}
+	}
+}
+
+// EncryptWithNonce c = (N + 1)^m * r^N mod N^2 = (1 + mN) * r^N mod N^2, m may be negative
+func (pub *PublicKey) EncryptWithNonce(m, r *big.Int) *big.Int {
+	n2 := pub.N2()
+
+	c := new(big.Int).Mul(m, pub.N)
+	c.Add(c, one)
+	c.Mod(c, n2)
+
+	return c.Mod(c.Mul(c, new(big.Int).Exp(r, pub.N, n2)), n2)
+}
+
+// Encrypt encrypt m in (-N/2, N) with random nonce, return the ciphertext and the nonce
+func (pub *PublicKey) Encrypt(random io.Reader, m *big.Int) (*big.Int, *big.Int, error) {
+	if new(big.Int).Abs(m).Cmp(pub.N) >= 0 {
+		return nil, nil, xerrors.Wrapf(ErrMessage, "message exceeds modulus")
+	}
+
+	r, err := RandomUnit(random, pub.N)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pub.EncryptWithNonce(m, r), r, nil
+}
+
+// ValidCiphertext check c in Z*_N^2
+func (pub *PublicKey) ValidCiphertext(c *big.Int) bool {
+	n2 := pub.N2()
+
+	return c != nil && c.Sign() > 0 && c.Cmp(n2) < 0 && new(big.Int).GCD(nil, nil, c, n2).Cmp(one) == 0
+}
+
+// Add homomorphic addition, the ciphertext of m1 + m2
+func (pub *PublicKey) Add(c1, c2 *big.Int) *big.Int {
+	n2 := pub.N2()
+
+	return new(big.Int).Mod(new(big.Int).Mul(c1, c2), n2)
+}
+
+// Mul homomorphic multiplication by plaintext, the ciphertext of k * m
+func (pub *PublicKey) Mul(c, k *big.Int) *big.Int {
+	return new(big.Int).Exp(c, k, pub.N2())
+}
+
+// Phi euler totient (P - 1)(Q - 1)
+func (priv *PrivateKey) Phi() *big.Int {
+	return new(big.Int).Mul(new(big.Int).Sub(priv.P, one), new(big.Int).Sub(priv.Q, one))
+}
+
+// Decrypt m = L(c^φ mod N^2) * φ^-1 mod N with L(x) = (x - 1) / N
+func (priv *PrivateKey) Decrypt(c *big.Int) (*big.Int, error) {
+	if !priv.ValidCiphertext(c) {
+		return nil, ErrCiphertext
+	}
+
+	phi := priv.Phi()
+
+	m := new(big.Int).Exp(c, phi, priv.N2())
+	m.Sub(m, one)
+	m.Div(m, priv.N)
+	m.Mul(m, new(big.Int).ModInverse(phi, priv.N))
+
+	return m.Mod(m, priv.N), nil
+}
@@ -0,0 +1,93 @@
+package paillier
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pre-generated 1024 bits safe primes
+var testPrimes = []string{
+	"dcbe8594ac7d5c378a81d77fe06ed1b307ec3287e3124a35f6fbf3a73a0d630983975c4b53c4ff5a9125630dc89b39093c24433c9c6807d36547a922dcec85482fb5a6ade163dc924e4b0b7d4e55e6c04d27229d11b3cf994f0e6eac29930ceb3d5f639c6d0f85fc8148ef3cd115f7385071611af9bb3e38fdd9e26b431eace3",
+	"fd6c20a04e2b8ab017349dd808efa4defd84903ffe1bbb9e710dbc00833b0c0015f169f9e32cf0c4c968cec91f106ec956d5016f78ecaf19d630f317637414a073bf86f4fe6af6740d6284bbec7706367951906863095c687e329fe6f4f53061ccebeb183a8129f6c2dd448217afcac877ab65ca29fce48f35bff2b6a49a925f",
+}
+
+func testKey(t *testing.T) *PrivateKey {
+	p, _ := new(big.Int).SetString(testPrimes[0], 16)
+	q, _ := new(big.Int).SetString(testPrimes[1], 16)
+
+	key, err := NewPrivateKey(p, q)
+
+	require.NoError(t, err)
+
+	require.Equal(t, MinBits, key.N.BitLen())
+
+	return key
+}
+
+func TestPaillier(t *testing.T) {
+	key := testKey(t)
+
+	m1 := big.NewInt(123456789)
+	m2 := big.NewInt(987654321)
+
+	c1, _, err := key.Encrypt(rand.Reader, m1)
+
+	require.NoError(t, err)
+
+	c2, _, err := key.Encrypt(rand.Reader, m2)
+
+	require.NoError(t, err)
+
+	m, err := key.Decrypt(c1)
+
+	require.NoError(t, err)
+
+	require.Equal(t, m1, m)
+
+	m, err = key.Decrypt(key.Add(c1, c2))
+
+	require.NoError(t, err)
+
+	require.Equal(t, new(big.Int).Add(m1, m2), m)
+
+	m, err = key.Decrypt(key.Mul(c1, m2))
+
+	require.NoError(t, err)
+
+	require.Equal(t, new(big.Int).Mul(m1, m2), m)
+
+	_, err = NewPrivateKey(big.NewInt(13), big.NewInt(11))
+
+	require.Error(t, err)
+}
+
+func TestProofs(t *testing.T) {
+	key := testKey(t)
+
+	require.NoError(t, key.VerifyModulus(key.ProveModulus()))
+
+	params, lambda, err := key.RingPedersen(rand.Reader)
+
+	require.NoError(t, err)
+
+	proof, err := key.ProvePedersen(rand.Reader, params, lambda)
+
+	require.NoError(t, err)
+
+	require.NoError(t, params.Verify(proof))
+
+	// wrong λ
+	proof, err = key.ProvePedersen(rand.Reader, params, new(big.Int).Add(lambda, big.NewInt(1)))
+
+	require.NoError(t, err)
+
+	require.Error(t, params.Verify(proof))
+
+	// N^2 is not square free
+	modulus := key.ProveModulus()
+
+	require.Error(t, (&PublicKey{N: key.N2()}).VerifyModulus(modulus))
+}
@@ -0,0 +1,256 @@
+package paillier
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrProof = errors.New("invalid paillier proof")
+)
+
+const (
+	// MinBits minimum modulus length accepted from peers
+	MinBits = 2048
+
+	// modulusRounds goldberg et al. square free proof rounds with trial division up to 251
+	modulusRounds = 16
+
+	// pedersenRounds binary challenges of the ring pedersen parameters proof
+	pedersenRounds = 80
+)
+
+// RingPedersen ring pedersen commitment parameters s = t^λ mod N, the commitment of x with
+// randomness r is s^x * t^r mod N
+type RingPedersen struct {
+	N *big.Int `json:"n"`
+	S *big.Int `json:"s"`
+	T *big.Int `json:"t"`
+}
+
+// ModulusProof N-th roots of challenges derived from N, proves gcd(N, φ(N)) = 1
+type ModulusProof struct {
+	Roots []*big.Int `json:"roots"`
+}
+
+// PedersenProof proof of knowledge of λ with s = t^λ mod N, https://eprint.iacr.org/2021/060 figure 17
+type PedersenProof struct {
+	A []*big.Int `json:"a"`
+	Z []*big.Int `json:"z"`
+}
+
+// HashInt sha256 based hash of length prefixed values expanded to bits length
+func HashInt(bits int, values ...*big.Int) *big.Int {
+	hasher := sha256.New()
+
+	for _, v := range values {
+		buff := v.Bytes()
+
+		var length [9]byte
+
+		// sign and length prefix keep the encoding injective
+		if v.Sign() < 0 {
+			length[0] = 1
+		}
+
+		binary.BigEndian.PutUint64(length[1:], uint64(len(buff)))
+
+		hasher.Write(length[:])
+		hasher.Write(buff)
+	}
+
+	seed := hasher.Sum(nil)
+
+	var output []byte
+
+	for counter := uint32(0); len(output)*8 < bits; counter++ {
+		var buff [4]byte
+
+		binary.BigEndian.PutUint32(buff[:], counter)
+
+		block := sha256.Sum256(append(seed, buff[:]...))
+
+		output = append(output, block[:]...)
+	}
+
+	v := new(big.Int).SetBytes(output)
+
+	return v.Rsh(v, uint(len(output)*8-bits))
+}
+
+func modulusChallenge(n *big.Int, i int) *big.Int {
+	c := HashInt(n.BitLen()+64, n, big.NewInt(int64(i)))
+
+	return c.Mod(c, n)
+}
+
+// ProveModulus prove the modulus is square free
+func (priv *PrivateKey) ProveModulus() *ModulusProof {
+	exponent := new(big.Int).ModInverse(priv.N, priv.Phi())
+
+	proof := &ModulusProof{}
+
+	for i := 0; i < modulusRounds; i++ {
+		proof.Roots = append(proof.Roots, new(big.Int).Exp(modulusChallenge(priv.N, i), exponent, priv.N))
+	}
+
+	return proof
+}
+
+// VerifyModulus verify the modulus proof and the modulus length
+func (pub *PublicKey) VerifyModulus(proof *ModulusProof) error {
+	if pub.N == nil || pub.N.BitLen() < MinBits || pub.N.Bit(0) == 0 {
+		return xerrors.Wrapf(ErrProof, "modulus length less than %d bits", MinBits)
+	}
+
+	for _, sp := range smallPrimes {
+		if new(big.Int).Mod(pub.N, new(big.Int).SetUint64(sp)).Sign() == 0 {
+			return xerrors.Wrapf(ErrProof, "modulus has small factor %d", sp)
+		}
+	}
+
+	if proof == nil || len(proof.Roots) != modulusRounds {
+		return xerrors.Wrapf(ErrProof, "modulus proof rounds mismatch")
+	}
+
+	for i, root := range proof.Roots {
+		challenge := modulusChallenge(pub.N, i)
+
+		if new(big.Int).GCD(nil, nil, challenge, pub.N).Cmp(one) != 0 {
+			return xerrors.Wrapf(ErrProof, "modulus challenge %d not a unit", i)
+		}
+
+		if root == nil || root.Sign() <= 0 || root.Cmp(pub.N) >= 0 || new(big.Int).Exp(root, pub.N, pub.N).Cmp(challenge) != 0 {
+			return xerrors.Wrapf(ErrProof, "modulus proof round %d", i)
+		}
+	}
+
+	return nil
+}
+
+// RingPedersen generate ring pedersen parameters on the modulus, return the parameters and λ
+func (priv *PrivateKey) RingPedersen(random io.Reader) (*RingPedersen, *big.Int, error) {
+	r, err := RandomUnit(random, priv.N)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := new(big.Int).Exp(r, two, priv.N)
+
+	lambda, err := RandomUnit(random, priv.Phi())
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &RingPedersen{
+		N: priv.N,
+		S: new(big.Int).Exp(t, lambda, priv.N),
+		T: t,
+	}, lambda, nil
+}
+
+// Commit s^x * t^r mod N, x and r may be negative
+func (params *RingPedersen) Commit(x, r *big.Int) *big.Int {
+	return new(big.Int).Mod(new(big.Int).Mul(params.exp(params.S, x), params.exp(params.T, r)), params.N)
+}
+
+// exp modular exponentiation with negative exponent support
+func (params *RingPedersen) exp(base, e *big.Int) *big.Int {
+	if e.Sign() >= 0 {
+		return new(big.Int).Exp(base, e, params.N)
+	}
+
+	inv := new(big.Int).ModInverse(base, params.N)
+
+	if inv == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Exp(inv, new(big.Int).Neg(e), params.N)
+}
+
+func pedersenChallenge(params *RingPedersen, a []*big.Int) *big.Int {
+	return HashInt(pedersenRounds, append([]*big.Int{params.N, params.S, params.T}, a...)...)
+}
+
+// ProvePedersen prove s = t^λ mod N
+func (priv *PrivateKey) ProvePedersen(random io.Reader, params *RingPedersen, lambda *big.Int) (*PedersenProof, error) {
+	phi := priv.Phi()
+
+	proof := &PedersenProof{}
+
+	nonces := make([]*big.Int, pedersenRounds)
+
+	for i := range nonces {
+		a, err := RandomUnit(random, phi)
+
+		if err != nil {
+			return nil, err
+		}
+
+		nonces[i] = a
+
+		proof.A = append(proof.A, new(big.Int).Exp(params.T, a, params.N))
+	}
+
+	e := pedersenChallenge(params, proof.A)
+
+	for i, a := range nonces {
+		z := new(big.Int).Set(a)
+
+		if e.Bit(i) == 1 {
+			z.Add(z, lambda)
+		}
+
+		proof.Z = append(proof.Z, z.Mod(z, phi))
+	}
+
+	return proof, nil
+}
+
+// Verify verify the ring pedersen parameters proof
+func (params *RingPedersen) Verify(proof *PedersenProof) error {
+	if params.N == nil || params.S == nil || params.T == nil || params.N.BitLen() < MinBits {
+		return xerrors.Wrapf(ErrProof, "invalid ring pedersen parameters")
+	}
+
+	for _, v := range []*big.Int{params.S, params.T} {
+		if v.Sign() <= 0 || v.Cmp(params.N) >= 0 || v.Cmp(one) == 0 || new(big.Int).GCD(nil, nil, v, params.N).Cmp(one) != 0 {
+			return xerrors.Wrapf(ErrProof, "ring pedersen parameter not a unit")
+		}
+	}
+
+	if proof == nil || len(proof.A) != pedersenRounds || len(proof.Z) != pedersenRounds {
+		return xerrors.Wrapf(ErrProof, "ring pedersen proof rounds mismatch")
+	}
+
+	for _, v := range append(proof.A, proof.Z...) {
+		if v == nil || v.Sign() < 0 || v.Cmp(params.N) >= 0 {
+			return xerrors.Wrapf(ErrProof, "ring pedersen proof value out of range")
+		}
+	}
+
+	e := pedersenChallenge(params, proof.A)
+
+	for i := range proof.A {
+		expected := new(big.Int).Set(proof.A[i])
+
+		if e.Bit(i) == 1 {
+			expected.Mod(expected.Mul(expected, params.S), params.N)
+		}
+
+		if new(big.Int).Exp(params.T, proof.Z[i], params.N).Cmp(expected) != 0 {
+			return xerrors.Wrapf(ErrProof, "ring pedersen proof round %d", i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+// Package rlp ethereum recursive length prefix encoding
+package rlp
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrType      = errors.New("unsupported rlp value type")
+	ErrEncoding  = errors.New("invalid rlp encoding")
+	ErrCanonical = errors.New("non-canonical rlp encoding")
+)
+
+// List rlp list of values
+type List []interface{}
+
+// Encode encode value, the value must be []byte, string, uint64, *big.Int, List or []byte slice
+func Encode(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return encodeBytes(v), nil
+	case string:
+		return encodeBytes([]byte(v)), nil
+	case uint64:
+		return encodeBytes(uintBytes(v)), nil
+	case *big.Int:
+		if v == nil {
+			return encodeBytes(nil), nil
+		}
+
+		if v.Sign() < 0 {
+			return nil, xerrors.Wrapf(ErrType, "negative integer %s", v)
+		}
+
+		return encodeBytes(v.Bytes()), nil
+	case [][]byte:
+		list := make(List, len(v))
+
+		for i, item := range v {
+			list[i] = item
+		}
+
+		return Encode(list)
+	case List:
+		var payload []byte
+
+		for _, item := range v {
+			buff, err := Encode(item)
+
+			if err != nil {
+				return nil, err
+			}
+
+			payload = append(payload, buff...)
+		}
+
+		return append(encodeLength(len(payload), 0xc0), payload...), nil
+	default:
+		return nil, xerrors.Wrapf(ErrType, "type %T", value)
+	}
+}
+
+func uintBytes(v uint64) []byte {
+	buff := make([]byte, 8)
+
+	binary.BigEndian.PutUint64(buff, v)
+
+	for len(buff) > 0 && buff[0] == 0 {
+		buff = buff[1:]
+	}
+
+	return buff
+}
+
+func encodeBytes(buff []byte) []byte {
+	if len(buff) == 1 && buff[0] < 0x80 {
+		return []byte{buff[0]}
+	}
+
+	return append(encodeLength(len(buff), 0x80), buff...)
+}
+
+func encodeLength(length int, offset byte) []byte {
+	if length < 56 {
+		return []byte{offset + byte(length)}
+	}
+
+	lengthBytes := uintBytes(uint64(length))
+
+	return append([]byte{offset + 55 + byte(len(lengthBytes))}, lengthBytes...)
+}
+
+// Decode decode the whole buff as one rlp item, the result is []byte or List
+func Decode(buff []byte) (interface{}, error) {
+	value, rest, err := decode(buff)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) != 0 {
+		return nil, xerrors.Wrapf(ErrEncoding, "%d bytes trailing data", len(rest))
+	}
+
+	return value, nil
+}
+
+func decode(buff []byte) (interface{}, []byte, error) {
+	if len(buff) == 0 {
+		return nil, nil, xerrors.Wrapf(ErrEncoding, "unexpected end of input")
+	}
+
+	prefix := buff[0]
+
+	switch {
+	case prefix < 0x80:
+		return buff[:1], buff[1:], nil
+	case prefix < 0xc0:
+		payload, rest, err := decodePayload(buff, 0x80)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(payload) == 1 && payload[0] < 0x80 {
+			return nil, nil, xerrors.Wrapf(ErrCanonical, "single byte %#x must not be prefixed", payload[0])
+		}
+
+		return payload, rest, nil
+	default:
+		payload, rest, err := decodePayload(buff, 0xc0)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		list := List{}
+
+		for len(payload) > 0 {
+			var item interface{}
+
+			item, payload, err = decode(payload)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			list = append(list, item)
+		}
+
+		return list, rest, nil
+	}
+}
+
+func decodePayload(buff []byte, offset byte) ([]byte, []byte, error) {
+	prefix := buff[0] - offset
+
+	buff = buff[1:]
+
+	length := uint64(prefix)
+
+	if prefix >= 56 {
+		lengthSize := int(prefix - 55)
+
+		if len(buff) < lengthSize {
+			return nil, nil, xerrors.Wrapf(ErrEncoding, "unexpected end of length")
+		}
+
+		if buff[0] == 0 || lengthSize > 8 {
+			return nil, nil, xerrors.Wrapf(ErrCanonical, "invalid length prefix")
+		}
+
+		length = 0
+
+		for _, b := range buff[:lengthSize] {
+			length = length<<8 | uint64(b)
+		}
+
+		if length < 56 {
+			return nil, nil, xerrors.Wrapf(ErrCanonical, "length %d must use short form", length)
+		}
+
+		buff = buff[lengthSize:]
+	}
+
+	if uint64(len(buff)) < length {
+		return nil, nil, xerrors.Wrapf(ErrEncoding, "payload length %d exceeds input %d", length, len(buff))
+	}
+
+	return buff[:length], buff[length:], nil
+}
+
+// Bytes get decoded string item
+func Bytes(item interface{}) ([]byte, error) {
+	buff, ok := item.([]byte)
+
+	if !ok {
+		return nil, xerrors.Wrapf(ErrEncoding, "expect string item got list")
+	}
+
+	return buff, nil
+}
+
+// BigInt get decoded integer item
+func BigInt(item interface{}) (*big.Int, error) {
+	buff, err := Bytes(item)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buff) > 0 && buff[0] == 0 {
+		return nil, xerrors.Wrapf(ErrCanonical, "integer has leading zero")
+	}
+
+	return new(big.Int).SetBytes(buff), nil
+}
+
+// Uint64 get decoded uint64 integer item
+func Uint64(item interface{}) (uint64, error) {
+	v, err := BigInt(item)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !v.IsUint64() {
+		return 0, xerrors.Wrapf(ErrEncoding, "integer %s overflow uint64", v)
+	}
+
+	return v.Uint64(), nil
+}
+
+// ToList get decoded list item
+func ToList(item interface{}) (List, error) {
+	list, ok := item.(List)
+
+	if !ok {
+		return nil, xerrors.Wrapf(ErrEncoding, "expect list item got string")
+	}
+
+	return list, nil
+}
@@ -0,0 +1,71 @@
+package rlp
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	lorem := "Lorem ipsum dolor sit amet, consectetur adipisicing elit"
+
+	for _, test := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{"dog", "83646f67"},
+		{List{"cat", "dog"}, "c88363617483646f67"},
+		{"", "80"},
+		{List{}, "c0"},
+		{uint64(0), "80"},
+		{[]byte{0x00}, "00"},
+		{uint64(15), "0f"},
+		{uint64(1024), "820400"},
+		{big.NewInt(1024), "820400"},
+		{List{List{}, List{List{}}, List{List{}, List{List{}}}}, "c7c0c1c0c3c0c1c0"},
+		{lorem, "b838" + hex.EncodeToString([]byte(lorem))},
+	} {
+		buff, err := Encode(test.value)
+
+		require.NoError(t, err)
+
+		require.Equal(t, test.expected, hex.EncodeToString(buff))
+
+		decoded, err := Decode(buff)
+
+		require.NoError(t, err)
+
+		reencoded, err := Encode(decoded)
+
+		require.NoError(t, err)
+
+		require.Equal(t, buff, reencoded)
+	}
+
+	_, err := Encode(big.NewInt(-1))
+
+	require.Error(t, err)
+}
+
+func TestDecodeNonCanonical(t *testing.T) {
+	for _, encoded := range []string{
+		"8100",                              // single byte must not be prefixed
+		"b80100",                            // short string in long form
+		"b90038" + strings.Repeat("00", 56), // length with leading zero
+		"83646f",                            // truncated
+		"83646f6767",                        // trailing data
+	} {
+		buff, _ := hex.DecodeString(encoded)
+
+		_, err := Decode(buff)
+
+		require.Error(t, err, encoded)
+	}
+
+	_, err := BigInt([]byte{0x00, 0x01})
+
+	require.Error(t, err)
+}
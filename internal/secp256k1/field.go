@@ -0,0 +1,307 @@
+package secp256k1
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// limbs 256 bits little endian fixed width integer, all arithmetic below runs in constant time
+// with respect to the limb values
+type limbs [4]uint64
+
+// modulus arithmetic modulo an odd 256 bits m, elements are kept fully reduced in montgomery
+// form a * 2^256 mod m, pseudo mersenne modulus m = 2^256 - c use the faster special reduction
+// with the canonical form instead
+type modulus struct {
+	m   limbs
+	c   uint64 // 2^256 - m if it fits one limb, zero for montgomery arithmetic
+	inv uint64 // -m^-1 mod 2^64
+	rr  limbs  // 2^512 mod m, conversion factor to montgomery form
+	one limbs  // 2^256 mod m, one in montgomery form
+}
+
+func limbsFromBig(v *big.Int) limbs {
+	var buff [32]byte
+
+	v.FillBytes(buff[:])
+
+	return limbsFromBytes(&buff)
+}
+
+func limbsFromBytes(buff *[32]byte) limbs {
+	var l limbs
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			l[3-i] |= uint64(buff[i*8+j]) << (56 - 8*uint(j))
+		}
+	}
+
+	return l
+}
+
+func (l *limbs) bytes() [32]byte {
+	var buff [32]byte
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			buff[i*8+j] = byte(l[3-i] >> (56 - 8*uint(j)))
+		}
+	}
+
+	return buff
+}
+
+func newModulus(m *big.Int) *modulus {
+	mod := &modulus{m: limbsFromBig(m)}
+
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+
+	if c := new(big.Int).Sub(r, m); c.IsUint64() {
+		mod.c = c.Uint64()
+		mod.rr = limbs{1}
+		mod.one = limbs{1}
+
+		return mod
+	}
+
+	// newton iteration for m^-1 mod 2^64, each step doubles the correct low bits
+	inv := uint64(1)
+
+	for i := 0; i < 6; i++ {
+		inv *= 2 - mod.m[0]*inv
+	}
+
+	mod.inv = -inv
+
+	mod.one = limbsFromBig(new(big.Int).Mod(r, m))
+	mod.rr = limbsFromBig(new(big.Int).Mod(new(big.Int).Mul(r, r), m))
+
+	return mod
+}
+
+// mask return all ones if flag is 1, zero if flag is 0
+func mask(flag uint64) uint64 {
+	return -flag
+}
+
+// selectLimbs set z to a if flag is 1, b if flag is 0
+func selectLimbs(z, a, b *limbs, flag uint64) {
+	m := mask(flag)
+
+	for i := 0; i < 4; i++ {
+		z[i] = (a[i] & m) | (b[i] &^ m)
+	}
+}
+
+// equalWord return 1 if a == b else 0
+func equalWord(a, b uint64) uint64 {
+	d := a ^ b
+
+	return 1 ^ ((d | -d) >> 63)
+}
+
+// isZero return 1 if l is zero else 0
+func (l *limbs) isZero() uint64 {
+	return equalWord(l[0]|l[1]|l[2]|l[3], 0)
+}
+
+// equal return 1 if a == b else 0
+func equal(a, b *limbs) uint64 {
+	d := limbs{a[0] ^ b[0], a[1] ^ b[1], a[2] ^ b[2], a[3] ^ b[3]}
+
+	return d.isZero()
+}
+
+// add z = x + y mod m
+func (mod *modulus) add(z, x, y *limbs) {
+	var s, t limbs
+	var carry, borrow uint64
+
+	s[0], carry = bits.Add64(x[0], y[0], 0)
+	s[1], carry = bits.Add64(x[1], y[1], carry)
+	s[2], carry = bits.Add64(x[2], y[2], carry)
+	s[3], carry = bits.Add64(x[3], y[3], carry)
+
+	t[0], borrow = bits.Sub64(s[0], mod.m[0], 0)
+	t[1], borrow = bits.Sub64(s[1], mod.m[1], borrow)
+	t[2], borrow = bits.Sub64(s[2], mod.m[2], borrow)
+	t[3], borrow = bits.Sub64(s[3], mod.m[3], borrow)
+
+	// x + y >= m if the addition overflows or the subtraction doesn't borrow
+	selectLimbs(z, &t, &s, carry|(1^borrow))
+}
+
+// sub z = x - y mod m
+func (mod *modulus) sub(z, x, y *limbs) {
+	var d limbs
+	var borrow, carry uint64
+
+	d[0], borrow = bits.Sub64(x[0], y[0], 0)
+	d[1], borrow = bits.Sub64(x[1], y[1], borrow)
+	d[2], borrow = bits.Sub64(x[2], y[2], borrow)
+	d[3], borrow = bits.Sub64(x[3], y[3], borrow)
+
+	m := mask(borrow)
+
+	z[0], carry = bits.Add64(d[0], mod.m[0]&m, 0)
+	z[1], carry = bits.Add64(d[1], mod.m[1]&m, carry)
+	z[2], carry = bits.Add64(d[2], mod.m[2]&m, carry)
+	z[3], _ = bits.Add64(d[3], mod.m[3]&m, carry)
+}
+
+// madd return the 128 bits a * b + c + d as hi, lo
+func madd(a, b, c, d uint64) (uint64, uint64) {
+	hi, lo := bits.Mul64(a, b)
+
+	var carry uint64
+
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+
+	return hi, lo
+}
+
+// mul z = x * y * 2^-256 mod m, coarsely integrated operand scanning montgomery multiplication
+func (mod *modulus) mul(z, x, y *limbs) {
+	if mod.c != 0 {
+		mod.mulPseudoMersenne(z, x, y)
+		return
+	}
+
+	var t [6]uint64
+	var c, carry uint64
+
+	for i := 0; i < 4; i++ {
+		c = 0
+
+		for j := 0; j < 4; j++ {
+			c, t[j] = madd(x[j], y[i], t[j], c)
+		}
+
+		t[4], carry = bits.Add64(t[4], c, 0)
+		t[5] = carry
+
+		u := t[0] * mod.inv
+
+		c, _ = madd(u, mod.m[0], t[0], 0)
+
+		for j := 1; j < 4; j++ {
+			c, t[j-1] = madd(u, mod.m[j], t[j], c)
+		}
+
+		t[3], carry = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + carry
+	}
+
+	// t < 2m, subtract m once if t >= m
+	var r limbs
+	var borrow uint64
+
+	r[0], borrow = bits.Sub64(t[0], mod.m[0], 0)
+	r[1], borrow = bits.Sub64(t[1], mod.m[1], borrow)
+	r[2], borrow = bits.Sub64(t[2], mod.m[2], borrow)
+	r[3], borrow = bits.Sub64(t[3], mod.m[3], borrow)
+	_, borrow = bits.Sub64(t[4], 0, borrow)
+
+	selectLimbs(z, &r, &limbs{t[0], t[1], t[2], t[3]}, 1^borrow)
+}
+
+// mulPseudoMersenne z = x * y mod m for m = 2^256 - c, the high half of the product is folded
+// twice with 2^256 = c mod m
+func (mod *modulus) mulPseudoMersenne(z, x, y *limbs) {
+	var t [8]uint64
+
+	for i := 0; i < 4; i++ {
+		var c uint64
+
+		for j := 0; j < 4; j++ {
+			c, t[i+j] = madd(x[j], y[i], t[i+j], c)
+		}
+
+		t[i+4] = c
+	}
+
+	// t_low + t_high * c < 2^(256 + 65)
+	var r limbs
+	var c, carry uint64
+
+	for j := 0; j < 4; j++ {
+		c, r[j] = madd(t[4+j], mod.c, t[j], c)
+	}
+
+	// r + c * top < 2^257
+	hi, lo := bits.Mul64(c, mod.c)
+
+	r[0], carry = bits.Add64(r[0], lo, 0)
+	r[1], carry = bits.Add64(r[1], hi, carry)
+	r[2], carry = bits.Add64(r[2], 0, carry)
+	r[3], carry = bits.Add64(r[3], 0, carry)
+
+	// on overflow r is tiny, so folding the carry once more can't overflow again
+	r[0], carry = bits.Add64(r[0], mod.c&mask(carry), 0)
+	r[1], carry = bits.Add64(r[1], 0, carry)
+	r[2], carry = bits.Add64(r[2], 0, carry)
+	r[3], _ = bits.Add64(r[3], 0, carry)
+
+	mod.reduce(z, &r)
+}
+
+// square z = x * x * 2^-256 mod m
+func (mod *modulus) square(z, x *limbs) {
+	mod.mul(z, x, x)
+}
+
+// toMont convert x < m to montgomery form
+func (mod *modulus) toMont(z, x *limbs) {
+	mod.mul(z, x, &mod.rr)
+}
+
+// fromMont convert montgomery form x to the canonical value
+func (mod *modulus) fromMont(z, x *limbs) {
+	mod.mul(z, x, &limbs{1})
+}
+
+// neg z = -x mod m
+func (mod *modulus) neg(z, x *limbs) {
+	var zero limbs
+
+	mod.sub(z, &zero, x)
+}
+
+// reduce convert any 256 bits value to the canonical value mod m, m must be larger than 2^255
+func (mod *modulus) reduce(z, x *limbs) {
+	var zero limbs
+
+	mod.add(z, x, &zero)
+}
+
+// exp z = x^e in montgomery form with 4 bits fixed window, the exponent e is public
+// so the window scan leaks nothing secret
+func (mod *modulus) exp(z, x *limbs, e *big.Int) {
+	var table [16]limbs
+
+	table[0] = mod.one
+	table[1] = *x
+
+	for i := 2; i < 16; i++ {
+		mod.mul(&table[i], &table[i-1], x)
+	}
+
+	r := mod.one
+
+	for i := (e.BitLen()+3)/4*4 - 4; i >= 0; i -= 4 {
+		mod.square(&r, &r)
+		mod.square(&r, &r)
+		mod.square(&r, &r)
+		mod.square(&r, &r)
+
+		window := e.Bit(i) | e.Bit(i+1)<<1 | e.Bit(i+2)<<2 | e.Bit(i+3)<<3
+
+		mod.mul(&r, &r, &table[window])
+	}
+
+	*z = r
+}
@@ -0,0 +1,395 @@
+package secp256k1
+
+import (
+	"math/big"
+	"math/bits"
+	"sync"
+)
+
+// affinePoint precomputed affine point in montgomery form, never the identity
+type affinePoint struct {
+	x, y limbs
+}
+
+// glv endomorphism φ(x, y) = (βx, y) = λ(x, y) and the lattice basis used to split scalars,
+// https://www.iacr.org/archive/crypto2001/21390189.pdf
+var (
+	beta      limbs // cube root of unity mod p in montgomery form
+	lambda    limbs // cube root of unity mod n in montgomery form
+	minusB1   limbs // -b1 mod n in montgomery form
+	minusB2   limbs // -b2 mod n in montgomery form
+	g1        limbs // round(2^384 * b2 / n)
+	g2        limbs // round(2^384 * -b1 / n)
+	halfOrder limbs // (n - 1) / 2
+
+	baseOnce    sync.Once
+	baseTable   *[64][16]affinePoint // baseTable[i][j] = j * 16^i * G
+	baseWindows *[16]point           // baseWindows[j] = j * G
+)
+
+func hexInt(s string) *big.Int {
+	v, _ := new(big.Int).SetString(s, 16)
+
+	return v
+}
+
+func montFromBig(mod *modulus, v *big.Int) limbs {
+	l := limbsFromBig(v)
+
+	mod.toMont(&l, &l)
+
+	return l
+}
+
+func initGLV() {
+	n := secp256k1.N
+
+	b1 := new(big.Int).Neg(hexInt("e4437ed6010e88286f547fa90abfe4c3"))
+	b2 := hexInt("3086d221a7d46bcde86c90e49284eb15")
+
+	beta = fieldFromBig(hexInt("7ae96a2b657c07106e64479eac3434e99cf0497512f58995c1396c28719501ee"))
+	lambda = montFromBig(fn, hexInt("5363ad4cc05c30e0a5261c028812645a122e22ea20816678df02967c1b23bd72"))
+
+	minusB1 = montFromBig(fn, new(big.Int).Mod(new(big.Int).Neg(b1), n))
+	minusB2 = montFromBig(fn, new(big.Int).Mod(new(big.Int).Neg(b2), n))
+
+	// round(2^384 * b / n) = floor((2^384 * b + n / 2) / n)
+	round := func(b *big.Int) limbs {
+		v := new(big.Int).Lsh(b, 384)
+		v.Add(v, new(big.Int).Rsh(n, 1))
+
+		return limbsFromBig(v.Div(v, n))
+	}
+
+	g1 = round(b2)
+	g2 = round(new(big.Int).Neg(b1))
+
+	halfOrder = limbsFromBig(new(big.Int).Rsh(n, 1))
+}
+
+// mulShift384 round(a * b / 2^384)
+func mulShift384(a, b *limbs) limbs {
+	var t [8]uint64
+
+	for i := 0; i < 4; i++ {
+		var c uint64
+
+		for j := 0; j < 4; j++ {
+			c, t[i+j] = madd(a[j], b[i], t[i+j], c)
+		}
+
+		t[i+4] = c
+	}
+
+	var r limbs
+	var carry uint64
+
+	r[0], carry = bits.Add64(t[6], t[5]>>63, 0)
+	r[1], carry = bits.Add64(t[7], 0, carry)
+	r[2] = carry
+
+	return r
+}
+
+// condNegate replace k mod n by n - k if k > n / 2, return 1 if negated
+func condNegate(k *limbs) uint64 {
+	var borrow uint64
+
+	_, borrow = bits.Sub64(halfOrder[0], k[0], 0)
+	_, borrow = bits.Sub64(halfOrder[1], k[1], borrow)
+	_, borrow = bits.Sub64(halfOrder[2], k[2], borrow)
+	_, borrow = bits.Sub64(halfOrder[3], k[3], borrow)
+
+	var neg limbs
+
+	fn.neg(&neg, k)
+
+	selectLimbs(k, &neg, k, borrow)
+
+	return borrow
+}
+
+// splitScalar split k < n into k = k1 + k2 * λ mod n, return the absolute values of k1 and k2 below 2^129
+// and whether they are negated, constant time
+func splitScalar(k *limbs) (k1, k2 limbs, neg1, neg2 uint64) {
+	c1 := mulShift384(k, &g1)
+	c2 := mulShift384(k, &g2)
+
+	// k2 = -c1 * b1 - c2 * b2, the montgomery factor of the constants cancels the one of mul
+	var t limbs
+
+	fn.mul(&k2, &c1, &minusB1)
+	fn.mul(&t, &c2, &minusB2)
+	fn.add(&k2, &k2, &t)
+
+	// k1 = k - k2 * λ
+	fn.mul(&t, &k2, &lambda)
+	fn.sub(&k1, k, &t)
+
+	neg1 = condNegate(&k1)
+	neg2 = condNegate(&k2)
+
+	return
+}
+
+// addAffine mixed addition p = a + b with affine b, complete unless b is the identity,
+// https://eprint.iacr.org/2015/1060 algorithm 8
+func (p *point) addAffine(a *point, b *affinePoint) *point {
+	var t0, t1, t2, t3, t4, x3, y3, z3 limbs
+
+	fp.mul(&t0, &a.x, &b.x)
+	fp.mul(&t1, &a.y, &b.y)
+	fp.add(&t3, &b.x, &b.y)
+	fp.add(&t4, &a.x, &a.y)
+	fp.mul(&t3, &t3, &t4)
+	fp.add(&t4, &t0, &t1)
+	fp.sub(&t3, &t3, &t4)
+	fp.mul(&t4, &b.y, &a.z)
+	fp.add(&t4, &t4, &a.y)
+	fp.mul(&y3, &b.x, &a.z)
+	fp.add(&y3, &y3, &a.x)
+	fp.add(&x3, &t0, &t0)
+	fp.add(&t0, &x3, &t0)
+	fp.mul(&t2, &b3, &a.z)
+	fp.add(&z3, &t1, &t2)
+	fp.sub(&t1, &t1, &t2)
+	fp.mul(&y3, &b3, &y3)
+	fp.mul(&x3, &t4, &y3)
+	fp.mul(&t2, &t3, &t1)
+	fp.sub(&x3, &t2, &x3)
+	fp.mul(&y3, &y3, &t0)
+	fp.mul(&t1, &t1, &z3)
+	fp.add(&y3, &t1, &y3)
+	fp.mul(&t0, &t0, &t3)
+	fp.mul(&z3, &z3, &t4)
+	fp.add(&z3, &z3, &t0)
+
+	p.x, p.y, p.z = x3, y3, z3
+
+	return p
+}
+
+// selectAffine set p to a if flag is 1, b if flag is 0
+func (p *affinePoint) selectAffine(a, b *affinePoint, flag uint64) *affinePoint {
+	selectLimbs(&p.x, &a.x, &b.x, flag)
+	selectLimbs(&p.y, &a.y, &b.y, flag)
+
+	return p
+}
+
+// normalize convert projective points to affine with a single shared field inversion
+func normalize(points []point) []affinePoint {
+	prefix := make([]limbs, len(points))
+
+	acc := fp.one
+
+	for i := range points {
+		prefix[i] = acc
+		fp.mul(&acc, &acc, &points[i].z)
+	}
+
+	var inv limbs
+
+	fp.exp(&inv, &acc, pMinus2)
+
+	result := make([]affinePoint, len(points))
+
+	for i := len(points) - 1; i >= 0; i-- {
+		var zinv limbs
+
+		fp.mul(&zinv, &inv, &prefix[i])
+		fp.mul(&inv, &inv, &points[i].z)
+
+		fp.mul(&result[i].x, &points[i].x, &zinv)
+		fp.mul(&result[i].y, &points[i].y, &zinv)
+	}
+
+	return result
+}
+
+// multiples the window table j * q for j in [0, 16)
+func multiples(q *point) *[16]point {
+	var table [16]point
+
+	table[0].setIdentity()
+	table[1] = *q
+
+	for i := 2; i < 16; i++ {
+		table[i].add(&table[i-1], q)
+	}
+
+	return &table
+}
+
+func initBaseTable() {
+	points := make([]point, 64*15)
+
+	q := *newPoint(secp256k1.Gx, secp256k1.Gy)
+
+	for i := 0; i < 64; i++ {
+		row := points[i*15 : i*15+15]
+
+		row[0] = q
+
+		for j := 1; j < 15; j++ {
+			row[j].add(&row[j-1], &q)
+		}
+
+		q.add(&row[14], &q)
+	}
+
+	affine := normalize(points)
+
+	baseTable = new([64][16]affinePoint)
+
+	for i := 0; i < 64; i++ {
+		copy(baseTable[i][1:], affine[i*15:i*15+15])
+
+		// never selected as result, the window 0 addition is discarded
+		baseTable[i][0] = baseTable[i][1]
+	}
+
+	baseWindows = multiples(newPoint(secp256k1.Gx, secp256k1.Gy))
+}
+
+// scalarBaseMult p = k * G with the precomputed comb table, 64 mixed additions with full table
+// scans and no doublings, constant time
+func (p *point) scalarBaseMult(k *[32]byte) *point {
+	baseOnce.Do(initBaseTable)
+
+	var r, s point
+	var t affinePoint
+
+	r.setIdentity()
+
+	for i := 0; i < 64; i++ {
+		window := uint64(k[31-i/2]>>(uint(i%2)*4)) & 0x0f
+
+		for j := range baseTable[i] {
+			t.selectAffine(&baseTable[i][j], &t, equalWord(uint64(j), window))
+		}
+
+		s.addAffine(&r, &t)
+
+		r.selectPoint(&r, &s, equalWord(window, 0))
+	}
+
+	*p = r
+
+	return p
+}
+
+// glvTables the window tables of q and φ(q) with the signs of the split scalar applied
+func glvTables(windows *[16]point, neg1, neg2 uint64) (*[16]point, *[16]point) {
+	var table1, table2 [16]point
+	var y limbs
+
+	for i := range windows {
+		table1[i] = windows[i]
+		table2[i] = windows[i]
+
+		fp.mul(&table2[i].x, &table2[i].x, &beta)
+
+		fp.neg(&y, &windows[i].y)
+
+		selectLimbs(&table1[i].y, &y, &table1[i].y, neg1)
+		selectLimbs(&table2[i].y, &y, &table2[i].y, neg2)
+	}
+
+	return &table1, &table2
+}
+
+// scalarMultGLV p = k * q = k1 * q + k2 * φ(q) with the 129 bits halves of k sharing the doublings,
+// constant time
+func (p *point) scalarMultGLV(q *point, k *[32]byte) *point {
+	scalar := limbsFromBytes(k)
+
+	fn.reduce(&scalar, &scalar)
+
+	k1, k2, neg1, neg2 := splitScalar(&scalar)
+
+	table1, table2 := glvTables(multiples(q), neg1, neg2)
+
+	b1 := k1.bytes()
+	b2 := k2.bytes()
+
+	var r, t point
+
+	r.setIdentity()
+
+	for i := 15; i < 32; i++ {
+		for _, shift := range [2]uint{4, 0} {
+			r.double(&r)
+			r.double(&r)
+			r.double(&r)
+			r.double(&r)
+
+			for _, window := range [2]struct {
+				table *[16]point
+				w     uint64
+			}{{table1, uint64(b1[i]>>shift) & 0x0f}, {table2, uint64(b2[i]>>shift) & 0x0f}} {
+				t.setIdentity()
+
+				for j := range window.table {
+					t.selectPoint(&window.table[j], &t, equalWord(uint64(j), window.w))
+				}
+
+				r.add(&r, &t)
+			}
+		}
+	}
+
+	*p = r
+
+	return p
+}
+
+// combinedMult p = s1 * G + s2 * q with shamir's trick, variable time so only for public scalars
+func (p *point) combinedMult(q *point, s1, s2 *[32]byte) *point {
+	baseOnce.Do(initBaseTable)
+
+	return p.multiMult([]*[16]point{baseWindows, multiples(q)}, []*[32]byte{s1, s2})
+}
+
+// multiMult p = sum(scalars[i] * windows[i][1]) with shamir's trick, the glv halves of all scalars share
+// one chain of doublings, variable time so only for public scalars
+func (p *point) multiMult(windows []*[16]point, scalars []*[32]byte) *point {
+	tables := make([]*[16]point, 0, 2*len(windows))
+	halves := make([][32]byte, 0, 2*len(windows))
+
+	for i := range windows {
+		scalar := limbsFromBytes(scalars[i])
+
+		fn.reduce(&scalar, &scalar)
+
+		k1, k2, neg1, neg2 := splitScalar(&scalar)
+
+		table1, table2 := glvTables(windows[i], neg1, neg2)
+
+		tables = append(tables, table1, table2)
+		halves = append(halves, k1.bytes(), k2.bytes())
+	}
+
+	var r point
+
+	r.setIdentity()
+
+	for i := 15; i < 32; i++ {
+		for _, shift := range [2]uint{4, 0} {
+			r.double(&r)
+			r.double(&r)
+			r.double(&r)
+			r.double(&r)
+
+			for j := range tables {
+				if w := (halves[j][i] >> shift) & 0x0f; w != 0 {
+					r.add(&r, &tables[j][w])
+				}
+			}
+		}
+	}
+
+	*p = r
+
+	return p
+}
@@ -0,0 +1,192 @@
+package secp256k1
+
+import (
+	"math/big"
+)
+
+// point projective point (X:Y:Z) representing affine (X/Z, Y/Z), coordinates are field elements
+// in montgomery form and the identity is (0:1:0)
+type point struct {
+	x, y, z limbs
+}
+
+// fieldFromBig convert big integer to field element in montgomery form
+func fieldFromBig(v *big.Int) limbs {
+	if v.Sign() < 0 || v.Cmp(secp256k1.P) >= 0 {
+		v = new(big.Int).Mod(v, secp256k1.P)
+	}
+
+	l := limbsFromBig(v)
+
+	fp.toMont(&l, &l)
+
+	return l
+}
+
+func fieldToBig(l *limbs) *big.Int {
+	var v limbs
+
+	fp.fromMont(&v, l)
+
+	buff := v.bytes()
+
+	return new(big.Int).SetBytes(buff[:])
+}
+
+func (p *point) setIdentity() *point {
+	p.x = limbs{}
+	p.y = fp.one
+	p.z = limbs{}
+
+	return p
+}
+
+// newPoint convert affine point to projective point, (0, 0) is the point at infinity
+func newPoint(x, y *big.Int) *point {
+	p := &point{}
+
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return p.setIdentity()
+	}
+
+	p.x = fieldFromBig(x)
+	p.y = fieldFromBig(y)
+	p.z = fp.one
+
+	return p
+}
+
+// affine convert to affine point, the point at infinity is returned as (0, 0)
+func (p *point) affine() (*big.Int, *big.Int) {
+	if p.z.isZero() == 1 {
+		return new(big.Int), new(big.Int)
+	}
+
+	var zinv, x, y limbs
+
+	fp.exp(&zinv, &p.z, pMinus2)
+
+	fp.mul(&x, &p.x, &zinv)
+	fp.mul(&y, &p.y, &zinv)
+
+	return fieldToBig(&x), fieldToBig(&y)
+}
+
+// add complete addition p = a + b for curves with a = 0, valid for all inputs including
+// the identity and a == b, https://eprint.iacr.org/2015/1060 algorithm 7
+func (p *point) add(a, b *point) *point {
+	var t0, t1, t2, t3, t4, x3, y3, z3 limbs
+
+	fp.mul(&t0, &a.x, &b.x)
+	fp.mul(&t1, &a.y, &b.y)
+	fp.mul(&t2, &a.z, &b.z)
+	fp.add(&t3, &a.x, &a.y)
+	fp.add(&t4, &b.x, &b.y)
+	fp.mul(&t3, &t3, &t4)
+	fp.add(&t4, &t0, &t1)
+	fp.sub(&t3, &t3, &t4)
+	fp.add(&t4, &a.y, &a.z)
+	fp.add(&x3, &b.y, &b.z)
+	fp.mul(&t4, &t4, &x3)
+	fp.add(&x3, &t1, &t2)
+	fp.sub(&t4, &t4, &x3)
+	fp.add(&x3, &a.x, &a.z)
+	fp.add(&y3, &b.x, &b.z)
+	fp.mul(&x3, &x3, &y3)
+	fp.add(&y3, &t0, &t2)
+	fp.sub(&y3, &x3, &y3)
+	fp.add(&x3, &t0, &t0)
+	fp.add(&t0, &x3, &t0)
+	fp.mul(&t2, &b3, &t2)
+	fp.add(&z3, &t1, &t2)
+	fp.sub(&t1, &t1, &t2)
+	fp.mul(&y3, &b3, &y3)
+	fp.mul(&x3, &t4, &y3)
+	fp.mul(&t2, &t3, &t1)
+	fp.sub(&x3, &t2, &x3)
+	fp.mul(&y3, &y3, &t0)
+	fp.mul(&t1, &t1, &z3)
+	fp.add(&y3, &t1, &y3)
+	fp.mul(&t0, &t0, &t3)
+	fp.mul(&z3, &z3, &t4)
+	fp.add(&z3, &z3, &t0)
+
+	p.x, p.y, p.z = x3, y3, z3
+
+	return p
+}
+
+// double complete doubling p = 2a for curves with a = 0, https://eprint.iacr.org/2015/1060 algorithm 9
+func (p *point) double(a *point) *point {
+	var t0, t1, t2, x3, y3, z3 limbs
+
+	fp.square(&t0, &a.y)
+	fp.add(&z3, &t0, &t0)
+	fp.add(&z3, &z3, &z3)
+	fp.add(&z3, &z3, &z3)
+	fp.mul(&t1, &a.y, &a.z)
+	fp.square(&t2, &a.z)
+	fp.mul(&t2, &b3, &t2)
+	fp.mul(&x3, &t2, &z3)
+	fp.add(&y3, &t0, &t2)
+	fp.mul(&z3, &t1, &z3)
+	fp.add(&t1, &t2, &t2)
+	fp.add(&t2, &t1, &t2)
+	fp.sub(&t0, &t0, &t2)
+	fp.mul(&y3, &t0, &y3)
+	fp.add(&y3, &x3, &y3)
+	fp.mul(&t1, &a.x, &a.y)
+	fp.mul(&x3, &t0, &t1)
+	fp.add(&x3, &x3, &x3)
+
+	p.x, p.y, p.z = x3, y3, z3
+
+	return p
+}
+
+// selectPoint set p to a if flag is 1, b if flag is 0
+func (p *point) selectPoint(a, b *point, flag uint64) *point {
+	selectLimbs(&p.x, &a.x, &b.x, flag)
+	selectLimbs(&p.y, &a.y, &b.y, flag)
+	selectLimbs(&p.z, &a.z, &b.z, flag)
+
+	return p
+}
+
+// scalarMult p = k * q with a fixed 4 bits window, every window performs the same doublings,
+// a full table scan and a complete addition so the timing only depends on the length of k
+func (p *point) scalarMult(q *point, k []byte) *point {
+	var table [16]point
+
+	table[0].setIdentity()
+	table[1] = *q
+
+	for i := 2; i < 16; i++ {
+		table[i].add(&table[i-1], q)
+	}
+
+	var r, t point
+
+	r.setIdentity()
+
+	for _, b := range k {
+		for _, window := range [2]uint64{uint64(b >> 4), uint64(b & 0x0f)} {
+			r.double(&r)
+			r.double(&r)
+			r.double(&r)
+			r.double(&r)
+
+			t.setIdentity()
+
+			for i := range table {
+				t.selectPoint(&table[i], &t, equalWord(uint64(i), window))
+			}
+
+			r.add(&r, &t)
+		}
+	}
+
+	*p = r
+
+	return p
+}
@@ -29,6 +29,7 @@
 package secp256k1
 
 import (
+	"crypto/ecdsa"
 	"crypto/elliptic"
 	"errors"
 	"math/big"
@@ -38,7 +39,13 @@ import (
 var (
 	initonce  sync.Once
 	secp256k1 *secp256k1Curve
-	three     = new(big.Int).SetUint64(3)
+	fp        *modulus // field prime p arithmetic
+	fn        *modulus // group order n arithmetic
+	b3        limbs    // 3b in montgomery form for the complete formulas
+	pMinus2   *big.Int // fermat inversion exponent of field
+	nMinus2   *big.Int // fermat inversion exponent of group order
+	sqrtExp   *big.Int // (p + 1) / 4 square root exponent of field
+	curveB    limbs    // b in montgomery form
 )
 
 type secp256k1Curve struct {
@@ -49,6 +56,10 @@ func (curve *secp256k1Curve) H() int {
 	return 1
 }
 
+func (curve *secp256k1Curve) A() *big.Int {
+	return new(big.Int)
+}
+
 func initSECP256K1() {
 	// http://www.secg.org/sec2-v2.pdf
 	secp256k1 = &secp256k1Curve{elliptic.CurveParams{Name: "secp256k1"}}
@@ -58,6 +69,18 @@ func initSECP256K1() {
 	secp256k1.Gx, _ = new(big.Int).SetString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", 16)
 	secp256k1.Gy, _ = new(big.Int).SetString("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8", 16)
 	secp256k1.BitSize = 256
+
+	fp = newModulus(secp256k1.P)
+	fn = newModulus(secp256k1.N)
+
+	curveB = fieldFromBig(secp256k1.B)
+	b3 = fieldFromBig(new(big.Int).Mul(secp256k1.B, big.NewInt(3)))
+
+	pMinus2 = new(big.Int).Sub(secp256k1.P, big.NewInt(2))
+	nMinus2 = new(big.Int).Sub(secp256k1.N, big.NewInt(2))
+	sqrtExp = new(big.Int).Rsh(new(big.Int).Add(secp256k1.P, big.NewInt(1)), 2)
+
+	initGLV()
 }
 
 // SECP256K1 .
@@ -85,215 +108,191 @@ func (curve *secp256k1Curve) IsOnCurve(x, y *big.Int) bool {
 }
 
 func (curve *secp256k1Curve) Double(x1, y1 *big.Int) (*big.Int, *big.Int) {
-	z1 := zForAffine(x1, y1)
-	return curve.affineFromJacobian(curve.doubleJacobian(x1, y1, z1))
+	return new(point).double(newPoint(x1, y1)).affine()
 }
 
-// zForAffine returns a Jacobian Z value for the affine point (x, y). If x and
-// y are zero, it assumes that they represent the point at infinity because (0,
-// 0) is not on the any of the curves handled here.
-func zForAffine(x, y *big.Int) *big.Int {
-	z := new(big.Int)
-	if x.Sign() != 0 || y.Sign() != 0 {
-		z.SetInt64(1)
-	}
-	return z
+func (curve *secp256k1Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	return new(point).add(newPoint(x1, y1), newPoint(x2, y2)).affine()
 }
 
-// affineFromJacobian reverses the Jacobian transform. See the comment at the
-// top of the file. If the point is ∞ it returns 0, 0.
-func (curve *secp256k1Curve) affineFromJacobian(x, y, z *big.Int) (xOut, yOut *big.Int) {
-	if z.Sign() == 0 {
-		return new(big.Int), new(big.Int)
+// scalarBytes left pad k to 32 bytes, return false if k is longer
+func scalarBytes(k []byte) (*[32]byte, bool) {
+	if len(k) > 32 {
+		return nil, false
 	}
 
-	zinv := new(big.Int).ModInverse(z, curve.P)
-	zinvsq := new(big.Int).Mul(zinv, zinv)
+	var buff [32]byte
 
-	xOut = new(big.Int).Mul(x, zinvsq)
-	xOut.Mod(xOut, curve.P)
-	zinvsq.Mul(zinvsq, zinv)
-	yOut = new(big.Int).Mul(y, zinvsq)
-	yOut.Mod(yOut, curve.P)
-	return
-}
+	copy(buff[32-len(k):], k)
 
-func (curve *secp256k1Curve) Add(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
-	z1 := zForAffine(x1, y1)
-	z2 := zForAffine(x2, y2)
-	return curve.affineFromJacobian(curve.addJacobian(x1, y1, z1, x2, y2, z2))
+	return &buff, true
 }
 
-// addJacobian takes two points in Jacobian coordinates, (x1, y1, z1) and
-// (x2, y2, z2) and returns their sum, also in Jacobian form.
-func (curve *secp256k1Curve) addJacobian(x1, y1, z1, x2, y2, z2 *big.Int) (*big.Int, *big.Int, *big.Int) {
-	// See http://hyperelliptic.org/EFD/g1p/auto-shortw-jacobian-0.html#addition-add-2007-bl
-	x3, y3, z3 := new(big.Int), new(big.Int), new(big.Int)
-	if z1.Sign() == 0 {
-		x3.Set(x2)
-		y3.Set(y2)
-		z3.Set(z2)
-		return x3, y3, z3
-	}
-	if z2.Sign() == 0 {
-		x3.Set(x1)
-		y3.Set(y1)
-		z3.Set(z1)
-		return x3, y3, z3
+// ScalarMult constant time scalar multiplication with the glv endomorphism, the timing only depends
+// on the length of k
+func (curve *secp256k1Curve) ScalarMult(Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
+	if buff, ok := scalarBytes(k); ok {
+		return new(point).scalarMultGLV(newPoint(Bx, By), buff).affine()
 	}
 
-	z1z1 := new(big.Int).Mul(z1, z1)
-	z1z1.Mod(z1z1, curve.P)
-	z2z2 := new(big.Int).Mul(z2, z2)
-	z2z2.Mod(z2z2, curve.P)
-
-	u1 := new(big.Int).Mul(x1, z2z2)
-	u1.Mod(u1, curve.P)
-	u2 := new(big.Int).Mul(x2, z1z1)
-	u2.Mod(u2, curve.P)
-	h := new(big.Int).Sub(u2, u1)
-	xEqual := h.Sign() == 0
-	if h.Sign() == -1 {
-		h.Add(h, curve.P)
-	}
-	i := new(big.Int).Lsh(h, 1)
-	i.Mul(i, i)
-	j := new(big.Int).Mul(h, i)
-
-	s1 := new(big.Int).Mul(y1, z2)
-	s1.Mul(s1, z2z2)
-	s1.Mod(s1, curve.P)
-	s2 := new(big.Int).Mul(y2, z1)
-	s2.Mul(s2, z1z1)
-	s2.Mod(s2, curve.P)
-	r := new(big.Int).Sub(s2, s1)
-	if r.Sign() == -1 {
-		r.Add(r, curve.P)
-	}
-	yEqual := r.Sign() == 0
-	if xEqual && yEqual {
-		return curve.doubleJacobian(x1, y1, z1)
-	}
-	r.Lsh(r, 1)
-	v := new(big.Int).Mul(u1, i)
-
-	x3.Set(r)
-	x3.Mul(x3, x3)
-	x3.Sub(x3, j)
-	x3.Sub(x3, v)
-	x3.Sub(x3, v)
-	x3.Mod(x3, curve.P)
-
-	y3.Set(r)
-	v.Sub(v, x3)
-	y3.Mul(y3, v)
-	s1.Mul(s1, j)
-	s1.Lsh(s1, 1)
-	y3.Sub(y3, s1)
-	y3.Mod(y3, curve.P)
-
-	z3.Add(z1, z2)
-	z3.Mul(z3, z3)
-	z3.Sub(z3, z1z1)
-	z3.Sub(z3, z2z2)
-	z3.Mul(z3, h)
-	z3.Mod(z3, curve.P)
-
-	return x3, y3, z3
+	return new(point).scalarMult(newPoint(Bx, By), k).affine()
 }
 
-// doubleJacobian takes a point in Jacobian coordinates, (x, y, z), and
-// returns its double, also in Jacobian form.
-func (curve *secp256k1Curve) doubleJacobian(x, y, z *big.Int) (*big.Int, *big.Int, *big.Int) {
-	// See http://hyperelliptic.org/EFD/g1p/auto-shortw-jacobian-0.html#doubling-dbl-2009-l
-	var a, b, c, d, e, f, x3, y3, z3 big.Int
-
-	a.Mul(x, x)
-	a.Mod(&a, curve.P)
-	b.Mul(y, y)
-	b.Mod(&b, curve.P)
-	c.Mul(&b, &b)
-	c.Mod(&c, curve.P)
-
-	d.Add(x, &b)
-	d.Mul(&d, &d)
-	d.Sub(&d, &a)
-	d.Sub(&d, &c)
-	d.Lsh(&d, 1)
-	if d.Sign() < 0 {
-		d.Add(&d, curve.P)
-	} else {
-		d.Mod(&d, curve.P)
+// ScalarBaseMult constant time scalar multiplication of G with the precomputed comb table
+func (curve *secp256k1Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
+	if buff, ok := scalarBytes(k); ok {
+		return new(point).scalarBaseMult(buff).affine()
 	}
 
-	e.Mul(three, &a)
-	e.Mod(&e, curve.P)
-	f.Mul(&e, &e)
-	f.Mod(&f, curve.P)
-
-	x3.Lsh(&d, 1)
-	x3.Sub(&f, &x3)
-	if x3.Sign() < 0 {
-		x3.Add(&x3, curve.P)
-	} else {
-		x3.Mod(&x3, curve.P)
+	return new(point).scalarMult(newPoint(curve.Gx, curve.Gy), k).affine()
+}
+
+// CombinedMult baseScalar * G + scalar * P in one pass sharing the doublings, variable time so only
+// for public scalars, sign/recoverable and sign/schnorr detect it to speed up public key recovery and
+// signature verification
+func (curve *secp256k1Curve) CombinedMult(Px, Py *big.Int, baseScalar, scalar []byte) (*big.Int, *big.Int) {
+	s1, ok1 := scalarBytes(baseScalar)
+	s2, ok2 := scalarBytes(scalar)
+
+	if !ok1 || !ok2 {
+		s1, _ = scalarBytes(new(big.Int).Mod(new(big.Int).SetBytes(baseScalar), curve.N).Bytes())
+		s2, _ = scalarBytes(new(big.Int).Mod(new(big.Int).SetBytes(scalar), curve.N).Bytes())
 	}
 
-	y3.Sub(&d, &x3)
-	y3.Mul(&e, &y3)
-	c.Lsh(&c, 3)
-	y3.Sub(&y3, &c)
-	if y3.Sign() < 0 {
-		y3.Add(&y3, curve.P)
-	} else {
-		y3.Mod(&y3, curve.P)
+	return new(point).combinedMult(newPoint(Px, Py), s1, s2).affine()
+}
+
+// Inverse constant time k^-1 mod N, used by ecdsa signing instead of the variable time big.Int ModInverse
+func (curve *secp256k1Curve) Inverse(k *big.Int) *big.Int {
+	if k.Sign() < 0 || k.Cmp(curve.N) >= 0 {
+		k = new(big.Int).Mod(k, curve.N)
 	}
 
-	z3.Mul(y, z)
-	z3.Lsh(&z3, 1)
-	z3.Mod(&z3, curve.P)
+	l := limbsFromBig(k)
+
+	fn.toMont(&l, &l)
+	fn.exp(&l, &l, nMinus2)
+	fn.fromMont(&l, &l)
 
-	return &x3, &y3, &z3
+	buff := l.bytes()
+
+	return new(big.Int).SetBytes(buff[:])
 }
 
-func (curve *secp256k1Curve) ScalarMult(Bx, By *big.Int, k []byte) (*big.Int, *big.Int) {
-	Bz := new(big.Int).SetInt64(1)
-	x, y, z := new(big.Int), new(big.Int), new(big.Int)
-
-	for _, byte := range k {
-		for bitNum := 0; bitNum < 8; bitNum++ {
-			x, y, z = curve.doubleJacobian(x, y, z)
-			if byte&0x80 == 0x80 {
-				x, y, z = curve.addJacobian(Bx, By, Bz, x, y, z)
-			}
-			byte <<= 1
+// MultiScalarMult sum of scalars[i] * (xs[i], ys[i]) with shamir's trick, variable time so only for public scalars
+// like batch verification, the scalars are reduced modulo N
+func MultiScalarMult(xs, ys []*big.Int, scalars [][]byte) (*big.Int, *big.Int) {
+	curve := SECP256K1().(*secp256k1Curve)
+
+	windows := make([]*[16]point, len(scalars))
+	buffs := make([]*[32]byte, len(scalars))
+
+	for i := range scalars {
+		buff, ok := scalarBytes(scalars[i])
+
+		if !ok {
+			buff, _ = scalarBytes(new(big.Int).Mod(new(big.Int).SetBytes(scalars[i]), curve.N).Bytes())
 		}
-	}
 
-	return curve.affineFromJacobian(x, y, z)
-}
+		windows[i] = multiples(newPoint(xs[i], ys[i]))
+		buffs[i] = buff
+	}
 
-func (curve *secp256k1Curve) ScalarBaseMult(k []byte) (*big.Int, *big.Int) {
-	return curve.ScalarMult(curve.Gx, curve.Gy, k)
+	return new(point).multiMult(windows, buffs).affine()
 }
 
 func decompressY(x *big.Int, ybit uint) (*big.Int, error) {
-	c := SECP256K1().Params()
+	SECP256K1()
 
 	// y^2 = x^3 + b
-	// y   = sqrt(x^3 + b)
-	var y, x3b big.Int
-	x3b.Mul(x, x)
-	x3b.Mul(&x3b, x)
-	x3b.Add(&x3b, c.B)
-	x3b.Mod(&x3b, c.P)
-	y.ModSqrt(&x3b, c.P)
-
-	if y.Bit(0) != ybit {
-		y.Sub(c.P, &y)
+	// y   = (x^3 + b)^((p + 1) / 4) since p = 3 mod 4
+	var y, y2, x3b limbs
+
+	fx := fieldFromBig(x)
+
+	fp.square(&x3b, &fx)
+	fp.mul(&x3b, &x3b, &fx)
+	fp.add(&x3b, &x3b, &curveB)
+	fp.exp(&y, &x3b, sqrtExp)
+
+	fp.square(&y2, &y)
+
+	if equal(&y2, &x3b) == 0 {
+		return nil, errors.New("x is not on the curve")
 	}
-	if y.Bit(0) != ybit {
+
+	result := fieldToBig(&y)
+
+	if result.Bit(0) != ybit {
+		result.Sub(secp256k1.P, result)
+	}
+	if result.Bit(0) != ybit {
 		return nil, errors.New("incorrectly encoded X and Y bit")
 	}
-	return &y, nil
+	return result, nil
+}
+
+// CompressPubkey encode public key point as 33 bytes compressed format
+func CompressPubkey(x, y *big.Int) []byte {
+	buff := make([]byte, 33)
+
+	buff[0] = 0x02 | byte(y.Bit(0))
+
+	xBytes := x.Bytes()
+
+	copy(buff[33-len(xBytes):], xBytes)
+
+	return buff
+}
+
+// DecompressPubkey decode 33 bytes compressed public key to point
+func DecompressPubkey(buff []byte) (x, y *big.Int, err error) {
+	if len(buff) != 33 || (buff[0] != 0x02 && buff[0] != 0x03) {
+		return nil, nil, errors.New("invalid compressed public key")
+	}
+
+	x = new(big.Int).SetBytes(buff[1:])
+
+	if x.Cmp(SECP256K1().Params().P) >= 0 {
+		return nil, nil, errors.New("invalid compressed public key x coordinate")
+	}
+
+	y, err = decompressY(x, uint(buff[0]&1))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !SECP256K1().IsOnCurve(x, y) {
+		return nil, nil, errors.New("compressed public key not on curve")
+	}
+
+	return x, y, nil
+}
+
+// ParsePubkey parse 33 bytes compressed or 65 bytes uncompressed public key
+func ParsePubkey(buff []byte) (*ecdsa.PublicKey, error) {
+	var x, y *big.Int
+
+	switch len(buff) {
+	case 33:
+		var err error
+		x, y, err = DecompressPubkey(buff)
+
+		if err != nil {
+			return nil, err
+		}
+
+	case 65:
+		x, y = elliptic.Unmarshal(SECP256K1(), buff)
+
+		if x == nil {
+			return nil, errors.New("invalid uncompressed public key")
+		}
+
+	default:
+		return nil, errors.New("invalid public key length")
+	}
+
+	return &ecdsa.PublicKey{Curve: SECP256K1(), X: x, Y: y}, nil
 }
@@ -0,0 +1,309 @@
+package secp256k1
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// referenceAdd affine addition with math/big as reference implementation
+func referenceAdd(x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	p := SECP256K1().Params().P
+
+	if x1.Sign() == 0 && y1.Sign() == 0 {
+		return x2, y2
+	}
+
+	if x2.Sign() == 0 && y2.Sign() == 0 {
+		return x1, y1
+	}
+
+	var lambda *big.Int
+
+	if x1.Cmp(x2) == 0 {
+		if new(big.Int).Add(y1, y2).Cmp(p) == 0 {
+			return new(big.Int), new(big.Int)
+		}
+
+		// lambda = 3x^2 / 2y
+		lambda = new(big.Int).Mul(x1, x1)
+		lambda.Mul(lambda, big.NewInt(3))
+		lambda.Mul(lambda, new(big.Int).ModInverse(new(big.Int).Lsh(y1, 1), p))
+	} else {
+		// lambda = (y2 - y1) / (x2 - x1)
+		lambda = new(big.Int).Sub(y2, y1)
+		lambda.Mul(lambda, new(big.Int).ModInverse(new(big.Int).Mod(new(big.Int).Sub(x2, x1), p), p))
+	}
+
+	lambda.Mod(lambda, p)
+
+	x3 := new(big.Int).Mul(lambda, lambda)
+	x3.Sub(x3, x1)
+	x3.Sub(x3, x2)
+	x3.Mod(x3, p)
+
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, lambda)
+	y3.Sub(y3, y1)
+	y3.Mod(y3, p)
+
+	return x3, y3
+}
+
+func referenceScalarMult(x, y *big.Int, k []byte) (*big.Int, *big.Int) {
+	rx, ry := new(big.Int), new(big.Int)
+
+	for _, b := range k {
+		for i := 7; i >= 0; i-- {
+			rx, ry = referenceAdd(rx, ry, rx, ry)
+
+			if b>>uint(i)&1 == 1 {
+				rx, ry = referenceAdd(rx, ry, x, y)
+			}
+		}
+	}
+
+	return rx, ry
+}
+
+func randomScalar(t *testing.T) []byte {
+	k, err := rand.Int(rand.Reader, SECP256K1().Params().N)
+
+	require.NoError(t, err)
+
+	return k.FillBytes(make([]byte, 32))
+}
+
+func TestField(t *testing.T) {
+	SECP256K1()
+
+	p := secp256k1.P
+
+	for i := 0; i < 100; i++ {
+		a, _ := rand.Int(rand.Reader, p)
+		b, _ := rand.Int(rand.Reader, p)
+
+		if i == 0 {
+			a.Sub(p, big.NewInt(1))
+			b.Sub(p, big.NewInt(1))
+		}
+
+		x := fieldFromBig(a)
+		y := fieldFromBig(b)
+
+		var z limbs
+
+		fp.mul(&z, &x, &y)
+		require.Equal(t, new(big.Int).Mod(new(big.Int).Mul(a, b), p).Text(16), fieldToBig(&z).Text(16))
+
+		fp.add(&z, &x, &y)
+		require.Equal(t, new(big.Int).Mod(new(big.Int).Add(a, b), p).Text(16), fieldToBig(&z).Text(16))
+
+		fp.sub(&z, &x, &y)
+		require.Equal(t, new(big.Int).Mod(new(big.Int).Sub(a, b), p).Text(16), fieldToBig(&z).Text(16))
+
+		fp.exp(&z, &x, pMinus2)
+		require.Equal(t, new(big.Int).ModInverse(a, p).Text(16), fieldToBig(&z).Text(16))
+	}
+}
+
+func TestInverse(t *testing.T) {
+	curve := SECP256K1().(*secp256k1Curve)
+
+	for i := 0; i < 100; i++ {
+		k := new(big.Int).SetBytes(randomScalar(t))
+
+		if k.Sign() == 0 {
+			continue
+		}
+
+		require.Equal(t, new(big.Int).ModInverse(k, curve.N), curve.Inverse(k))
+	}
+}
+
+func TestScalarMult(t *testing.T) {
+	curve := SECP256K1()
+	params := curve.Params()
+
+	// 2G
+	x, y := curve.ScalarBaseMult([]byte{2})
+
+	require.Equal(t, "c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5", x.Text(16))
+	require.Equal(t, "1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a", y.Text(16))
+
+	x2, y2 := curve.Double(params.Gx, params.Gy)
+
+	require.Equal(t, x, x2)
+	require.Equal(t, y, y2)
+
+	x2, y2 = curve.Add(params.Gx, params.Gy, params.Gx, params.Gy)
+
+	require.Equal(t, x, x2)
+	require.Equal(t, y, y2)
+
+	// (n - 1)G = -G
+	x, y = curve.ScalarBaseMult(new(big.Int).Sub(params.N, big.NewInt(1)).Bytes())
+
+	require.Equal(t, params.Gx, x)
+	require.Equal(t, new(big.Int).Sub(params.P, params.Gy), y)
+
+	// G + -G = infinity
+	x, y = curve.Add(params.Gx, params.Gy, x, y)
+
+	require.Zero(t, x.Sign())
+	require.Zero(t, y.Sign())
+
+	for _, k := range [][]byte{params.N.Bytes(), {0}, nil} {
+		x, y = curve.ScalarBaseMult(k)
+
+		require.Zero(t, x.Sign())
+		require.Zero(t, y.Sign())
+	}
+
+	for i := 0; i < 10; i++ {
+		bx, by := curve.ScalarBaseMult(randomScalar(t))
+
+		require.True(t, curve.IsOnCurve(bx, by))
+
+		k := randomScalar(t)
+
+		x, y := curve.ScalarMult(bx, by, k)
+		ex, ey := referenceScalarMult(bx, by, k)
+
+		require.Equal(t, ex, x)
+		require.Equal(t, ey, y)
+	}
+}
+
+func TestGLV(t *testing.T) {
+	curve := SECP256K1()
+	params := curve.Params()
+
+	// φ(G) = λG
+	x, y := curve.ScalarMult(params.Gx, params.Gy, limbsToBig(&lambda, fn).Bytes())
+
+	require.Equal(t, new(big.Int).Mod(new(big.Int).Mul(params.Gx, limbsToBig(&beta, fp)), params.P), x)
+	require.Equal(t, params.Gy, y)
+
+	for i := 0; i < 100; i++ {
+		k := limbsFromBig(new(big.Int).SetBytes(randomScalar(t)))
+
+		if i == 0 {
+			k = limbsFromBig(new(big.Int).Sub(params.N, big.NewInt(1)))
+		}
+
+		k1, k2, neg1, neg2 := splitScalar(&k)
+
+		require.Zero(t, k1[2]|k1[3])
+		require.Zero(t, k2[2]|k2[3])
+
+		b1, b2 := k1.bytes(), k2.bytes()
+
+		v1 := new(big.Int).SetBytes(b1[:])
+		v2 := new(big.Int).SetBytes(b2[:])
+
+		if neg1 == 1 {
+			v1.Neg(v1)
+		}
+
+		if neg2 == 1 {
+			v2.Neg(v2)
+		}
+
+		v2.Mul(v2, limbsToBig(&lambda, fn))
+		v2.Add(v2, v1)
+		v2.Mod(v2, params.N)
+
+		buff := k.bytes()
+
+		require.Equal(t, new(big.Int).SetBytes(buff[:]), v2)
+	}
+}
+
+func TestCombinedMult(t *testing.T) {
+	curve := SECP256K1().(*secp256k1Curve)
+	params := curve.Params()
+
+	for i := 0; i < 10; i++ {
+		px, py := curve.ScalarBaseMult(randomScalar(t))
+
+		s1 := randomScalar(t)
+		s2 := randomScalar(t)
+
+		x, y := curve.CombinedMult(px, py, s1, s2)
+
+		x1, y1 := referenceScalarMult(params.Gx, params.Gy, s1)
+		x2, y2 := referenceScalarMult(px, py, s2)
+
+		ex, ey := referenceAdd(x1, y1, x2, y2)
+
+		require.Equal(t, ex, x)
+		require.Equal(t, ey, y)
+	}
+
+	// G - G = infinity
+	x, y := curve.CombinedMult(params.Gx, params.Gy, []byte{1}, new(big.Int).Sub(params.N, big.NewInt(1)).Bytes())
+
+	require.Zero(t, x.Sign())
+	require.Zero(t, y.Sign())
+}
+
+func limbsToBig(l *limbs, mod *modulus) *big.Int {
+	var v limbs
+
+	mod.fromMont(&v, l)
+
+	buff := v.bytes()
+
+	return new(big.Int).SetBytes(buff[:])
+}
+
+func BenchmarkScalarBaseMult(b *testing.B) {
+	curve := SECP256K1()
+
+	k := make([]byte, 32)
+
+	rand.Read(k)
+
+	curve.ScalarBaseMult(k)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		curve.ScalarBaseMult(k)
+	}
+}
+
+func BenchmarkScalarMult(b *testing.B) {
+	curve := SECP256K1()
+
+	k := make([]byte, 32)
+
+	rand.Read(k)
+
+	x, y := curve.ScalarBaseMult(k)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		curve.ScalarMult(x, y, k)
+	}
+}
+
+func BenchmarkCombinedMult(b *testing.B) {
+	curve := SECP256K1().(*secp256k1Curve)
+
+	k := make([]byte, 32)
+
+	rand.Read(k)
+
+	x, y := curve.ScalarBaseMult(k)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		curve.CombinedMult(x, y, k, k)
+	}
+}
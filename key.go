@@ -7,12 +7,20 @@ import (
 	"github.com/dynamicgo/xerrors"
 
 	"github.com/dynamicgo/injector"
+
+	"github.com/laplacenetwork/key/hd"
 )
 
 // Errors
 var (
-	ErrDriver    = errors.New("unknown driver")
-	ErrPublicKey = errors.New("invalid public key")
+	ErrDriver     = errors.New("unknown driver")
+	ErrPublicKey  = errors.New("invalid public key")
+	ErrPrivateKey = errors.New("invalid private key")
+	ErrHD         = errors.New("provider not support hd derivation")
+	ErrAddress    = errors.New("invalid address")
+	ErrMessage    = errors.New("provider not support message signing")
+	ErrAggregate  = errors.New("provider not support signature aggregation")
+	ErrVerifyItem = errors.New("invalid batch verify item")
 )
 
 // Key blockchain key facade
@@ -22,13 +30,25 @@ type Key interface {
 	PubKey() []byte                     // public key byte array
 	SetBytes(priKey []byte)             // set private key bytes
 	Sign(hashed []byte) ([]byte, error) // sign the hashed message
+	PublicKey() PublicKey               // watch-only public key
 	Provider() Provider                 // provider
 }
 
+// PublicKey watch-only public key facade
+type PublicKey interface {
+	Address() string                     // address display string
+	Bytes() []byte                       // public key byte array, same format as Key PubKey
+	Compressed() []byte                  // compressed public key byte array
+	Uncompressed() []byte                // uncompressed public key byte array
+	Verify(sig []byte, hash []byte) bool // verify the signature of hashed message
+	Provider() Provider                  // provider
+}
+
 // Provider the key service provider
 type Provider interface {
-	Name() string      // driver name
-	New() (Key, error) // create new key
+	Name() string                               // driver name
+	New() (Key, error)                          // create new key
+	PublicKey(pubkey []byte) (PublicKey, error) // create watch-only public key
 	Verify(pubkey []byte, sig []byte, hash []byte) bool
 	PublicKeyToAddress(pubkey []byte) (string, error)
 	ValidAddress(address string) bool
@@ -40,12 +60,49 @@ type RecoverableProvider interface {
 	Recover(sig []byte, hash []byte) (pubkey []byte, err error)
 }
 
+// HDProvider the provider support bip44 derivation path
+type HDProvider interface {
+	Provider
+	CoinType() uint32                // slip44 coin type
+	DefaultPath(index uint32) string // default account path for address index
+}
+
+// DeriveProvider the provider derive keys from seed with its own scheme instead of bip32, e.g. eip2333
+type DeriveProvider interface {
+	Provider
+	Derive(seed []byte, path string) (Key, error) // key of the derivation path
+}
+
+// MessageProvider the provider declare the canonical hash of arbitrary message
+type MessageProvider interface {
+	Provider
+	HashMessage(message []byte) []byte // canonical message digest to be signed
+}
+
+// AddressProvider the provider report why the address is invalid and normalize address
+type AddressProvider interface {
+	Provider
+	CheckAddress(address string) error               // nil if the address is valid, otherwise the reason
+	NormalizeAddress(address string) (string, error) // canonical address display string
+}
+
+// CompressibleKey the key can switch PubKey between compressed and uncompressed encoding
+type CompressibleKey interface {
+	SetCompressed(compressed bool)
+}
+
 // Encryptor .
 type Encryptor interface {
 	Encrypt(key Key, attrs map[string]string, writer io.Writer) error
 	Decrypt(key Key, attrs map[string]string, reader io.Reader) error
 }
 
+// Splitter split the private key into shares each written to its own writer, enough shares restore the key
+type Splitter interface {
+	Split(key Key, attrs map[string]string, writers []io.Writer) error
+	Combine(key Key, attrs map[string]string, readers []io.Reader) error
+}
+
 // RegisterProvider register provider
 func RegisterProvider(provider Provider) {
 	injector.Register(provider.Name(), provider)
@@ -56,6 +113,11 @@ func RegisterEncryptor(name string, f Encryptor) {
 	injector.Register(name, f)
 }
 
+// RegisterSplitter register key splitter
+func RegisterSplitter(name string, f Splitter) {
+	injector.Register(name, f)
+}
+
 // New create key
 func New(driver string) (Key, error) {
 	var provider Provider
@@ -79,6 +141,104 @@ func From(driver string, key Key) (Key, error) {
 	return toKey, nil
 }
 
+// NewPublicKey create watch-only public key from public key bytes
+func NewPublicKey(driver string, pubkey []byte) (PublicKey, error) {
+	var provider Provider
+	if !injector.Get(driver, &provider) {
+		return nil, xerrors.Wrapf(ErrDriver, "unknown driver %s", driver)
+	}
+
+	return provider.PublicKey(pubkey)
+}
+
+// getHDProvider get the secp256k1 based provider which derive keys by bip32, the providers with
+// their own derivation scheme and the providers of other curves, e.g. slip10 ed25519, are rejected
+func getHDProvider(driver string) (HDProvider, error) {
+	var provider Provider
+	if !injector.Get(driver, &provider) {
+		return nil, xerrors.Wrapf(ErrDriver, "unknown driver %s", driver)
+	}
+
+	if _, ok := provider.(DeriveProvider); ok {
+		return nil, xerrors.Wrapf(ErrHD, "driver %s not derive keys by bip32", driver)
+	}
+
+	hdProvider, ok := provider.(HDProvider)
+
+	if !ok {
+		return nil, xerrors.Wrapf(ErrHD, "driver %s not derive keys by bip32", driver)
+	}
+
+	return hdProvider, nil
+}
+
+// FromHD create key from bip32 extended private key,
+// the driver must be a secp256k1 based provider, e.g. eth or did
+func FromHD(driver string, extendedKey *hd.ExtendedKey) (Key, error) {
+	provider, err := getHDProvider(driver)
+
+	if err != nil {
+		return nil, err
+	}
+
+	priKey, err := extendedKey.PriKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	toKey, err := provider.New()
+
+	if err != nil {
+		return nil, err
+	}
+
+	toKey.SetBytes(priKey)
+
+	return toKey, nil
+}
+
+// DefaultPath get driver default derivation path for address index
+func DefaultPath(driver string, index uint32) (string, error) {
+	var provider HDProvider
+	if !injector.Get(driver, &provider) {
+		return "", xerrors.Wrapf(ErrHD, "unknown hd driver %s", driver)
+	}
+
+	return provider.DefaultPath(index), nil
+}
+
+// Derive create key from seed by derivation path, e.g. m/44'/60'/0'/0/0, the driver must derive
+// keys by its own scheme or be a secp256k1 based hd provider
+func Derive(driver string, seed []byte, path string) (Key, error) {
+	var provider Provider
+	if !injector.Get(driver, &provider) {
+		return nil, xerrors.Wrapf(ErrDriver, "unknown driver %s", driver)
+	}
+
+	if deriveProvider, ok := provider.(DeriveProvider); ok {
+		return deriveProvider.Derive(seed, path)
+	}
+
+	if _, err := getHDProvider(driver); err != nil {
+		return nil, err
+	}
+
+	master, err := hd.NewMaster(seed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	extendedKey, err := master.DerivePath(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return FromHD(driver, extendedKey)
+}
+
 // ValidAddress .
 func ValidAddress(driver string, address string) (bool, error) {
 	var provider Provider
@@ -89,6 +249,80 @@ func ValidAddress(driver string, address string) (bool, error) {
 	return provider.ValidAddress(address), nil
 }
 
+// CheckAddress check address and return the reason if the address is invalid
+func CheckAddress(driver string, address string) error {
+	var provider Provider
+	if !injector.Get(driver, &provider) {
+		return xerrors.Wrapf(ErrDriver, "unknown driver %s", driver)
+	}
+
+	if addressProvider, ok := provider.(AddressProvider); ok {
+		return addressProvider.CheckAddress(address)
+	}
+
+	if !provider.ValidAddress(address) {
+		return xerrors.Wrapf(ErrAddress, "invalid %s address %s", driver, address)
+	}
+
+	return nil
+}
+
+// NormalizeAddress get the canonical address display string
+func NormalizeAddress(driver string, address string) (string, error) {
+	var provider Provider
+	if !injector.Get(driver, &provider) {
+		return "", xerrors.Wrapf(ErrDriver, "unknown driver %s", driver)
+	}
+
+	if addressProvider, ok := provider.(AddressProvider); ok {
+		return addressProvider.NormalizeAddress(address)
+	}
+
+	if !provider.ValidAddress(address) {
+		return "", xerrors.Wrapf(ErrAddress, "invalid %s address %s", driver, address)
+	}
+
+	return address, nil
+}
+
+// HashMessage hash message with the driver canonical message hash
+func HashMessage(driver string, message []byte) ([]byte, error) {
+	var provider Provider
+	if !injector.Get(driver, &provider) {
+		return nil, xerrors.Wrapf(ErrDriver, "unknown driver %s", driver)
+	}
+
+	messageProvider, ok := provider.(MessageProvider)
+
+	if !ok {
+		return nil, xerrors.Wrapf(ErrMessage, "driver %s", driver)
+	}
+
+	return messageProvider.HashMessage(message), nil
+}
+
+// SignMessage hash message with the key provider canonical message hash then sign it
+func SignMessage(key Key, message []byte) ([]byte, error) {
+	provider, ok := key.Provider().(MessageProvider)
+
+	if !ok {
+		return nil, xerrors.Wrapf(ErrMessage, "driver %s", key.Provider().Name())
+	}
+
+	return key.Sign(provider.HashMessage(message))
+}
+
+// VerifyMessage verify the signature of message signed by SignMessage
+func VerifyMessage(driver string, pubkey []byte, sig []byte, message []byte) (bool, error) {
+	hash, err := HashMessage(driver, message)
+
+	if err != nil {
+		return false, err
+	}
+
+	return Verify(driver, pubkey, sig, hash)
+}
+
 // Recover recover public key from sig and hash
 func Recover(driver string, sig []byte, hash []byte) ([]byte, error) {
 	var provider RecoverableProvider
@@ -155,3 +389,40 @@ func Decrypt(encryptor string, key Key, attrs map[string]string, reader io.Reade
 
 	return nil
 }
+
+func getSplitter(name string) (Splitter, error) {
+	var splitter Splitter
+	if !injector.Get(name, &splitter) {
+		return nil, xerrors.Wrapf(ErrDriver, "unknown splitter %s", name)
+	}
+
+	return splitter, nil
+}
+
+// Split split the private key into shares, one per writer
+func Split(splitter string, key Key, attrs map[string]string, writers []io.Writer) error {
+	sp, err := getSplitter(splitter)
+
+	if err != nil {
+		return err
+	}
+
+	return sp.Split(key, attrs, writers)
+}
+
+// Combine restore the private key from shares, one per reader
+func Combine(splitter string, key Key, attrs map[string]string, readers []io.Reader) error {
+	sp, err := getSplitter(splitter)
+
+	if err != nil {
+		return err
+	}
+
+	err = sp.Combine(key, attrs, readers)
+
+	if err != nil {
+		return xerrors.Wrapf(err, "combine with splitter %s failed", splitter)
+	}
+
+	return nil
+}
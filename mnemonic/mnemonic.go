@@ -0,0 +1,202 @@
+// Package mnemonic bip39 mnemonic code implement
+package mnemonic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/dynamicgo/xerrors"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/laplacenetwork/key"
+)
+
+// Errors
+var (
+	ErrEntropy  = errors.New("invalid entropy length")
+	ErrWords    = errors.New("invalid mnemonic words count")
+	ErrWord     = errors.New("unknown mnemonic word")
+	ErrChecksum = errors.New("mnemonic checksum mismatch")
+)
+
+const (
+	seedIterations = 2048
+	seedLen        = 64
+	wordBits       = 11
+)
+
+var wordIndex = make(map[string]int, len(English))
+
+func init() {
+	if len(English) != 1<<wordBits {
+		panic("bip39 english wordlist length error")
+	}
+
+	for i, word := range English {
+		wordIndex[word] = i
+	}
+}
+
+func checkEntropyBits(bits int) error {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return xerrors.Wrapf(ErrEntropy, "entropy bits %d must be multiple of 32 in range [128,256]", bits)
+	}
+
+	return nil
+}
+
+// NewEntropy generate random entropy, bits must be multiple of 32 in range [128,256]
+func NewEntropy(bits int) ([]byte, error) {
+	if err := checkEntropyBits(bits); err != nil {
+		return nil, err
+	}
+
+	entropy := make([]byte, bits/8)
+
+	if _, err := io.ReadFull(rand.Reader, entropy); err != nil {
+		return nil, xerrors.Wrapf(err, "read entropy from crypto/rand error")
+	}
+
+	return entropy, nil
+}
+
+// New generate random mnemonic with 12, 15, 18, 21 or 24 words
+func New(words int) (string, error) {
+	if words%3 != 0 {
+		return "", xerrors.Wrapf(ErrWords, "words count %d", words)
+	}
+
+	entropy, err := NewEntropy(words / 3 * 32)
+
+	if err != nil {
+		return "", xerrors.Wrapf(ErrWords, "words count %d", words)
+	}
+
+	return FromEntropy(entropy)
+}
+
+// FromEntropy encode entropy to mnemonic
+func FromEntropy(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+
+	if err := checkEntropyBits(bits); err != nil {
+		return "", err
+	}
+
+	checksumBits := bits / 32
+
+	hash := sha256.Sum256(entropy)
+
+	data := new(big.Int).SetBytes(entropy)
+
+	data.Lsh(data, uint(checksumBits))
+	data.Or(data, big.NewInt(int64(hash[0]>>uint(8-checksumBits))))
+
+	count := (bits + checksumBits) / wordBits
+
+	words := make([]string, count)
+
+	mask := big.NewInt(1<<wordBits - 1)
+
+	index := new(big.Int)
+
+	for i := count - 1; i >= 0; i-- {
+		index.And(data, mask)
+		data.Rsh(data, wordBits)
+
+		words[i] = English[index.Int64()]
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// ToEntropy decode mnemonic to entropy and verify the checksum
+func ToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+
+	count := len(words)
+
+	if count < 12 || count > 24 || count%3 != 0 {
+		return nil, xerrors.Wrapf(ErrWords, "words count %d", count)
+	}
+
+	data := new(big.Int)
+
+	for _, word := range words {
+		index, ok := wordIndex[word]
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrWord, "word %s", word)
+		}
+
+		data.Lsh(data, wordBits)
+		data.Or(data, big.NewInt(int64(index)))
+	}
+
+	checksumBits := count * wordBits / 33
+
+	checksum := new(big.Int).And(data, big.NewInt(1<<uint(checksumBits)-1))
+
+	data.Rsh(data, uint(checksumBits))
+
+	entropy := make([]byte, checksumBits*4)
+
+	dataBytes := data.Bytes()
+
+	copy(entropy[len(entropy)-len(dataBytes):], dataBytes)
+
+	hash := sha256.Sum256(entropy)
+
+	if checksum.Int64() != int64(hash[0]>>uint(8-checksumBits)) {
+		return nil, ErrChecksum
+	}
+
+	return entropy, nil
+}
+
+// Valid check mnemonic words and checksum
+func Valid(mnemonic string) bool {
+	_, err := ToEntropy(mnemonic)
+
+	return err == nil
+}
+
+// Seed convert mnemonic to 64 bytes seed with optional passphrase, the mnemonic checksum is verified
+func Seed(mnemonic string, passphrase string) ([]byte, error) {
+	if _, err := ToEntropy(mnemonic); err != nil {
+		return nil, err
+	}
+
+	password := norm.NFKD.String(strings.Join(strings.Fields(mnemonic), " "))
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+
+	return pbkdf2.Key([]byte(password), []byte(salt), seedIterations, seedLen, sha512.New), nil
+}
+
+// NewKey create driver key from mnemonic with the driver default derivation path of index 0
+func NewKey(driver string, mnemonic string, passphrase string) (key.Key, error) {
+	path, err := key.DefaultPath(driver, 0)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Derive(driver, mnemonic, passphrase, path)
+}
+
+// Derive create driver key from mnemonic by derivation path
+func Derive(driver string, mnemonic string, passphrase string, path string) (key.Key, error) {
+	seed, err := Seed(mnemonic, passphrase)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Derive(driver, seed, path)
+}
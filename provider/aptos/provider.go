@@ -0,0 +1,45 @@
+// Package aptos aptos ed25519 key provider, the address is sha3-256(pubkey || 0x00) authentication key
+package aptos
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/internal/ed25519x"
+)
+
+// single ed25519 signature scheme identifier
+const ed25519Scheme = byte(0x00)
+
+type addresserImpl struct {
+}
+
+func (addresser *addresserImpl) Name() string {
+	return "aptos"
+}
+
+func (addresser *addresserImpl) PublicKeyToAddress(pub ed25519.PublicKey) string {
+	authKey := sha3.Sum256(append(append([]byte(nil), pub...), ed25519Scheme))
+
+	return "0x" + hex.EncodeToString(authKey[:])
+}
+
+func (addresser *addresserImpl) ValidAddress(address string) bool {
+	address = strings.TrimPrefix(address, "0x")
+
+	if len(address) == 0 || len(address) > 64 {
+		return false
+	}
+
+	_, err := hex.DecodeString(strings.Repeat("0", len(address)%2) + address)
+
+	return err == nil
+}
+
+func init() {
+	key.RegisterProvider(ed25519x.NewProvider(&addresserImpl{}))
+}
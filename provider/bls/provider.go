@@ -0,0 +1,255 @@
+// Package bls ethereum consensus bls12-381 validator key provider, the public key is the 48 bytes
+// compressed G1 point and the signature is the 96 bytes compressed G2 point of the proof of
+// possession ciphersuite, keys derive from seed by eip2333 and eip2334 paths
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/internal/bls12381"
+	"github.com/laplacenetwork/key/sign/bls"
+)
+
+// Key bls key
+type Key interface {
+	key.Key
+	PopProve() []byte // proof of possession of the secret key
+}
+
+// PopVerify verify the proof of possession of the public key
+func PopVerify(pubkey []byte, proof []byte) bool {
+	return bls.PopVerify(pubkey, proof)
+}
+
+// pubKeyToAddress the validator public key is the identity, display as 0x prefixed hex
+func pubKeyToAddress(pubkey []byte) string {
+	return "0x" + hex.EncodeToString(pubkey)
+}
+
+// parsePubKey decode the compressed or uncompressed public key to the compressed public key
+func parsePubKey(pubkey []byte) ([]byte, error) {
+	p, err := bls12381.NewG1(pubkey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if p.IsIdentity() {
+		return nil, xerrors.Wrapf(bls.ErrPublicKey, "public key is the identity")
+	}
+
+	return p.Bytes(), nil
+}
+
+type keyImpl struct {
+	provider *providerIml
+	key      *big.Int
+	pubkey   []byte // compressed public key
+	address  string // address
+}
+
+func newKey(provider *providerIml, sk *big.Int) *keyImpl {
+	pubkey := bls.PublicKey(sk)
+
+	return &keyImpl{
+		provider: provider,
+		key:      sk,
+		pubkey:   pubkey,
+		address:  pubKeyToAddress(pubkey),
+	}
+}
+
+func (key *keyImpl) Address() string {
+	return key.address
+}
+
+func (key *keyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+// PriKey the 32 bytes big endian secret key
+func (key *keyImpl) PriKey() []byte {
+	return bls.SecretKeyBytes(key.key)
+}
+
+func (key *keyImpl) PubKey() []byte {
+	return key.pubkey
+}
+
+func (key *keyImpl) PopProve() []byte {
+	return bls.PopProve(key.key)
+}
+
+func (key *keyImpl) PublicKey() key.PublicKey {
+	return &publicKeyImpl{
+		provider: key.provider,
+		pubkey:   key.pubkey,
+		address:  key.address,
+	}
+}
+
+// SetBytes set the big endian secret key, which is reduced mod r
+func (key *keyImpl) SetBytes(priKey []byte) {
+	sk := new(big.Int).SetBytes(priKey)
+
+	*key = *newKey(key.provider, sk.Mod(sk, bls12381.Order))
+}
+
+// Sign sign the hashed message, e.g. the signing root of the consensus object
+func (key *keyImpl) Sign(hashed []byte) ([]byte, error) {
+	if key.key.Sign() == 0 {
+		return nil, xerrors.Wrapf(bls.ErrSecretKey, "secret key is zero")
+	}
+
+	return bls.Sign(key.key, hashed), nil
+}
+
+type publicKeyImpl struct {
+	provider *providerIml
+	pubkey   []byte // compressed public key
+	address  string // address
+}
+
+func (key *publicKeyImpl) Address() string {
+	return key.address
+}
+
+func (key *publicKeyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *publicKeyImpl) Bytes() []byte {
+	return key.pubkey
+}
+
+func (key *publicKeyImpl) Compressed() []byte {
+	return key.pubkey
+}
+
+func (key *publicKeyImpl) Uncompressed() []byte {
+	p, err := bls12381.NewG1(key.pubkey)
+
+	if err != nil {
+		return nil
+	}
+
+	return p.Uncompressed()
+}
+
+func (key *publicKeyImpl) Verify(sig []byte, hash []byte) bool {
+	return bls.Verify(key.pubkey, hash, sig)
+}
+
+type providerIml struct {
+}
+
+func (provider *providerIml) Name() string {
+	return "bls"
+}
+
+// CoinType eip2334 coin type of ethereum consensus keys
+func (provider *providerIml) CoinType() uint32 {
+	return 3600
+}
+
+// DefaultPath eip2334 signing key path m/12381/3600/index/0/0
+func (provider *providerIml) DefaultPath(index uint32) string {
+	return bls.SigningKeyPath(index)
+}
+
+// Derive eip2333 key derivation, which replaces bip32 for bls keys
+func (provider *providerIml) Derive(seed []byte, path string) (key.Key, error) {
+	sk, err := bls.DerivePath(seed, path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newKey(provider, sk), nil
+}
+
+func (provider *providerIml) New() (key.Key, error) {
+	ikm := make([]byte, 32)
+
+	if _, err := rand.Read(ikm); err != nil {
+		return nil, xerrors.Wrapf(err, "read random error")
+	}
+
+	sk, err := bls.KeyGen(ikm, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newKey(provider, sk), nil
+}
+
+func (provider *providerIml) PublicKey(pubkey []byte) (key.PublicKey, error) {
+	compressed, err := parsePubKey(pubkey)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(key.ErrPublicKey, "decode public key error: %s", err)
+	}
+
+	return &publicKeyImpl{
+		provider: provider,
+		pubkey:   compressed,
+		address:  pubKeyToAddress(compressed),
+	}, nil
+}
+
+func (provider *providerIml) Verify(pubkey []byte, sig []byte, hash []byte) bool {
+	compressed, err := parsePubKey(pubkey)
+
+	if err != nil {
+		return false
+	}
+
+	return bls.Verify(compressed, hash, sig)
+}
+
+func (provider *providerIml) Aggregate(sigs [][]byte) ([]byte, error) {
+	return bls.Aggregate(sigs)
+}
+
+func (provider *providerIml) FastAggregateVerify(pubkeys [][]byte, sig []byte, hash []byte) bool {
+	return bls.FastAggregateVerify(pubkeys, hash, sig)
+}
+
+func (provider *providerIml) AggregateVerify(pubkeys [][]byte, sig []byte, hashes [][]byte) bool {
+	return bls.AggregateVerify(pubkeys, hashes, sig)
+}
+
+func (provider *providerIml) PublicKeyToAddress(pubkey []byte) (string, error) {
+	compressed, err := parsePubKey(pubkey)
+
+	if err != nil {
+		return "", xerrors.Wrapf(key.ErrPublicKey, "decode public key error: %s", err)
+	}
+
+	return pubKeyToAddress(compressed), nil
+}
+
+func (provider *providerIml) ValidAddress(address string) bool {
+	if !strings.HasPrefix(address, "0x") {
+		return false
+	}
+
+	pubkey, err := hex.DecodeString(address[2:])
+
+	if err != nil || len(pubkey) != bls.PublicKeySize {
+		return false
+	}
+
+	return bls.KeyValidate(pubkey)
+}
+
+func init() {
+	key.RegisterProvider(&providerIml{})
+}
@@ -0,0 +1,110 @@
+package btc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/laplacenetwork/key/internal/secp256k1"
+)
+
+func TestWIF(t *testing.T) {
+	k, err := FromWIF(MainNet, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+
+	require.NoError(t, err)
+
+	require.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", k.Address())
+
+	for addressType, expected := range map[AddressType]string{
+		P2PKH:      "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+		P2SHP2WPKH: "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN",
+		P2WPKH:     "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+	} {
+		address, err := k.AddressOf(addressType)
+
+		require.NoError(t, err)
+
+		require.Equal(t, expected, address)
+
+		require.True(t, k.Provider().ValidAddress(address))
+	}
+
+	require.Equal(t, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn", k.WIF())
+
+	uncompressed, err := FromWIF(MainNet, "5HpHagT65TZzG1PH3CSu63k8DbpvD8s5ip4nEB3kEsreAnchuDf")
+
+	require.NoError(t, err)
+
+	require.Equal(t, "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm", uncompressed.Address())
+
+	_, err = uncompressed.AddressOf(P2WPKH)
+
+	require.Error(t, err)
+
+	_, err = FromWIF(TestNet, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+
+	require.Error(t, err)
+}
+
+func TestValidAddress(t *testing.T) {
+	mainnet := &providerIml{params: MainNet}
+	testnet := &providerIml{params: TestNet}
+
+	require.True(t, mainnet.ValidAddress("bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3"))
+	require.True(t, testnet.ValidAddress("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"))
+	require.False(t, mainnet.ValidAddress("tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"))
+	require.False(t, mainnet.ValidAddress("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5"))
+	require.False(t, mainnet.ValidAddress("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMJ"))
+	require.False(t, testnet.ValidAddress("1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"))
+}
+
+func TestVerifyStrict(t *testing.T) {
+	provider := &providerIml{params: MainNet}
+
+	k, err := FromWIF(MainNet, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+
+	require.NoError(t, err)
+
+	hash := make([]byte, 32)
+
+	sig, err := k.Sign(hash)
+
+	require.NoError(t, err)
+
+	require.True(t, provider.Verify(k.PubKey(), sig, hash))
+
+	require.True(t, provider.Verify(nil, sig, hash))
+
+	n := secp256k1.SECP256K1().Params().N
+
+	// the malleable high s form is rejected
+	high := append([]byte{}, sig...)
+
+	new(big.Int).Sub(n, new(big.Int).SetBytes(sig[32:64])).FillBytes(high[32:64])
+
+	high[64] ^= 1
+
+	require.False(t, provider.Verify(k.PubKey(), high, hash))
+
+	require.False(t, provider.Verify(nil, high, hash))
+
+	require.False(t, k.PublicKey().Verify(high, hash))
+
+	// r out of range must not panic the recovery
+	for _, r := range []*big.Int{big.NewInt(0), n} {
+		bad := append([]byte{}, sig...)
+
+		r.FillBytes(bad[:32])
+
+		for _, v := range []byte{27, 28, 31, 32} {
+			bad[64] = v
+
+			require.False(t, provider.Verify(nil, bad, hash))
+
+			_, err = provider.Recover(bad, hash)
+
+			require.Error(t, err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package btc
+
+// Params bitcoin network parameters
+type Params struct {
+	Name             string // driver name
+	PubKeyHashAddrID byte   // p2pkh address version
+	ScriptHashAddrID byte   // p2sh address version
+	PrivateKeyID     byte   // wif version
+	Bech32HRP        string // segwit address human readable part
+	CoinType         uint32 // slip44 coin type
+}
+
+// Networks
+var (
+	MainNet = &Params{
+		Name:             "btc",
+		PubKeyHashAddrID: 0x00,
+		ScriptHashAddrID: 0x05,
+		PrivateKeyID:     0x80,
+		Bech32HRP:        "bc",
+		CoinType:         0,
+	}
+
+	TestNet = &Params{
+		Name:             "btc.testnet",
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+		Bech32HRP:        "tb",
+		CoinType:         1,
+	}
+
+	RegTest = &Params{
+		Name:             "btc.regtest",
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+		Bech32HRP:        "bcrt",
+		CoinType:         1,
+	}
+)
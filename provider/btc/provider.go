@@ -0,0 +1,408 @@
+// Package btc bitcoin p2pkh, p2sh-p2wpkh and p2wpkh address provider
+package btc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/internal/bech32"
+	"github.com/laplacenetwork/key/internal/ecdsax"
+	"github.com/laplacenetwork/key/internal/hash160"
+	"github.com/laplacenetwork/key/internal/secp256k1"
+	"github.com/laplacenetwork/key/sign"
+	"github.com/laplacenetwork/key/sign/recoverable"
+)
+
+// AddressType bitcoin address format
+type AddressType int
+
+// Address types
+const (
+	P2WPKH     AddressType = iota // native segwit bech32 address
+	P2SHP2WPKH                    // nested segwit address
+	P2PKH                         // legacy address
+)
+
+// Errors
+var (
+	ErrAddressType = errors.New("unsupported address type")
+	ErrWIF         = errors.New("invalid wif private key")
+)
+
+// Key bitcoin key with multiple address formats
+type Key interface {
+	key.Key
+	AddressOf(addressType AddressType) (string, error) // address of the given format
+	WIF() string                                       // wallet import format private key
+}
+
+// PublicKey bitcoin watch-only public key with multiple address formats
+type PublicKey interface {
+	key.PublicKey
+	AddressOf(addressType AddressType) (string, error) // address of the given format
+}
+
+func pubKeyBytes(pub *ecdsa.PublicKey, compressed bool) []byte {
+	if compressed {
+		return secp256k1.CompressPubkey(pub.X, pub.Y)
+	}
+
+	return ecdsax.PublicKeyBytes(pub)
+}
+
+func pubKeyToAddress(params *Params, pub *ecdsa.PublicKey, compressed bool, addressType AddressType) (string, error) {
+	if addressType != P2PKH && !compressed {
+		return "", xerrors.Wrapf(ErrAddressType, "segwit address require compressed public key")
+	}
+
+	pubKeyHash := hash160.Hash160(pubKeyBytes(pub, compressed))
+
+	switch addressType {
+	case P2PKH:
+		return base58.CheckEncode(pubKeyHash, params.PubKeyHashAddrID), nil
+	case P2SHP2WPKH:
+		redeemScript := append([]byte{0x00, 0x14}, pubKeyHash...)
+
+		return base58.CheckEncode(hash160.Hash160(redeemScript), params.ScriptHashAddrID), nil
+	case P2WPKH:
+		return bech32.EncodeSegwit(params.Bech32HRP, 0, pubKeyHash)
+	}
+
+	return "", xerrors.Wrapf(ErrAddressType, "address type %d", addressType)
+}
+
+func defaultAddress(params *Params, pub *ecdsa.PublicKey, compressed bool) string {
+	addressType := P2WPKH
+
+	if !compressed {
+		addressType = P2PKH
+	}
+
+	address, _ := pubKeyToAddress(params, pub, compressed, addressType)
+
+	return address
+}
+
+type keyImpl struct {
+	provider   *providerIml
+	key        *ecdsa.PrivateKey
+	compressed bool
+	address    string // address
+}
+
+func newKey(provider *providerIml, privateKey *ecdsa.PrivateKey, compressed bool) *keyImpl {
+	return &keyImpl{
+		provider:   provider,
+		key:        privateKey,
+		compressed: compressed,
+		address:    defaultAddress(provider.params, &privateKey.PublicKey, compressed),
+	}
+}
+
+func (key *keyImpl) Address() string {
+	return key.address
+}
+
+func (key *keyImpl) AddressOf(addressType AddressType) (string, error) {
+	return pubKeyToAddress(key.provider.params, &key.key.PublicKey, key.compressed, addressType)
+}
+
+func (key *keyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *keyImpl) PriKey() []byte {
+	return ecdsax.PrivateKeyBytes(key.key)
+}
+
+func (key *keyImpl) PubKey() []byte {
+	return pubKeyBytes(&key.key.PublicKey, key.compressed)
+}
+
+// CryptoPrivateKey the standard library private key
+func (key *keyImpl) CryptoPrivateKey() crypto.PrivateKey {
+	return key.key
+}
+
+func (key *keyImpl) PublicKey() key.PublicKey {
+	return &publicKeyImpl{
+		provider:   key.provider,
+		key:        &key.key.PublicKey,
+		compressed: key.compressed,
+		address:    key.address,
+	}
+}
+
+func (key *keyImpl) SetBytes(priKey []byte) {
+	key.key = ecdsax.BytesToPrivateKey(priKey, secp256k1.SECP256K1())
+	key.compressed = true
+	key.address = defaultAddress(key.provider.params, &key.key.PublicKey, key.compressed)
+}
+
+// SetCompressed switch PubKey between compressed and uncompressed encoding, the default address
+// is recomputed because bitcoin addresses commit to the public key encoding
+func (key *keyImpl) SetCompressed(compressed bool) {
+	key.compressed = compressed
+	key.address = defaultAddress(key.provider.params, &key.key.PublicKey, key.compressed)
+}
+
+func (key *keyImpl) WIF() string {
+	buff := key.PriKey()
+
+	if key.compressed {
+		buff = append(buff, 0x01)
+	}
+
+	return base58.CheckEncode(buff, key.provider.params.PrivateKeyID)
+}
+
+func (key *keyImpl) Sign(hashed []byte) ([]byte, error) {
+
+	if err := sign.CheckHash(key.key.Curve, hashed); err != nil {
+		return nil, err
+	}
+
+	sig, err := recoverable.Sign(key.key, hashed, key.compressed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sign.EncodeRecoverable(sig, key.key.Curve.Params().BitSize/8, sign.VLegacy)
+}
+
+type publicKeyImpl struct {
+	provider   *providerIml
+	key        *ecdsa.PublicKey
+	compressed bool
+	address    string // address
+}
+
+func (key *publicKeyImpl) Address() string {
+	return key.address
+}
+
+func (key *publicKeyImpl) AddressOf(addressType AddressType) (string, error) {
+	return pubKeyToAddress(key.provider.params, key.key, key.compressed, addressType)
+}
+
+func (key *publicKeyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *publicKeyImpl) Bytes() []byte {
+	return pubKeyBytes(key.key, key.compressed)
+}
+
+func (key *publicKeyImpl) Compressed() []byte {
+	return pubKeyBytes(key.key, true)
+}
+
+func (key *publicKeyImpl) Uncompressed() []byte {
+	return pubKeyBytes(key.key, false)
+}
+
+// CryptoPublicKey the standard library public key
+func (key *publicKeyImpl) CryptoPublicKey() crypto.PublicKey {
+	return key.key
+}
+
+func (key *publicKeyImpl) Verify(sig []byte, hash []byte) bool {
+	if sign.CheckHash(key.key.Curve, hash) != nil {
+		return false
+	}
+
+	signature, err := sign.Decode(sig)
+
+	if err != nil {
+		return false
+	}
+
+	return signature.VerifyStrict(key.key, hash)
+}
+
+type providerIml struct {
+	params *Params
+}
+
+func (provider *providerIml) Name() string {
+	return provider.params.Name
+}
+
+// HashMessage double sha256 message hash
+func (provider *providerIml) HashMessage(message []byte) []byte {
+	return hash160.DoubleHash(message)
+}
+
+func (provider *providerIml) CoinType() uint32 {
+	return provider.params.CoinType
+}
+
+func (provider *providerIml) DefaultPath(index uint32) string {
+	return fmt.Sprintf("m/84'/%d'/0'/0/%d", provider.params.CoinType, index)
+}
+
+func (provider *providerIml) New() (key.Key, error) {
+
+	privateKey, err := ecdsa.GenerateKey(secp256k1.SECP256K1(), rand.Reader)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "ecdsa GenerateKey(SECP256K1) error")
+	}
+
+	return newKey(provider, privateKey, true), nil
+}
+
+func (provider *providerIml) PublicKey(pubkey []byte) (key.PublicKey, error) {
+	publicKey, err := secp256k1.ParsePubkey(pubkey)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(key.ErrPublicKey, "decode public key error: %s", err)
+	}
+
+	compressed := len(pubkey) == 33
+
+	return &publicKeyImpl{
+		provider:   provider,
+		key:        publicKey,
+		compressed: compressed,
+		address:    defaultAddress(provider.params, publicKey, compressed),
+	}, nil
+}
+
+// Verify verify 65 bytes recoverable, 64 bytes compact or strict der signature, if pubkey is nil the public
+// key is recovered, out of range r, s and malleable high s are rejected as bitcoin standardness rules do
+func (provider *providerIml) Verify(pubkey []byte, sig []byte, hash []byte) bool {
+
+	if sign.CheckHash(secp256k1.SECP256K1(), hash) != nil {
+		return false
+	}
+
+	signature, err := sign.Decode(sig)
+
+	if err != nil {
+		return false
+	}
+
+	if signature.Check(secp256k1.SECP256K1()) != nil {
+		return false
+	}
+
+	if pubkey != nil {
+		publicKey, err := secp256k1.ParsePubkey(pubkey)
+
+		if err != nil {
+			return false
+		}
+
+		return signature.VerifyStrict(publicKey, hash)
+	}
+
+	if signature.V == nil {
+		return false
+	}
+
+	publicKey, _, err := recoverable.Recover(secp256k1.SECP256K1(), signature, hash)
+
+	if err != nil {
+		return false
+	}
+
+	return signature.VerifyStrict(publicKey, hash)
+}
+
+func (provider *providerIml) PublicKeyToAddress(pubkey []byte) (string, error) {
+	publicKey, err := secp256k1.ParsePubkey(pubkey)
+
+	if err != nil {
+		return "", xerrors.Wrapf(key.ErrPublicKey, "decode public key error: %s", err)
+	}
+
+	return defaultAddress(provider.params, publicKey, len(pubkey) == 33), nil
+}
+
+// Recover recover the public key, the 33 bytes compressed public key is returned if v has the compressed flag
+func (provider *providerIml) Recover(sig []byte, hash []byte) (pubkey []byte, err error) {
+	signature, err := sign.Decode(sig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if signature.V == nil {
+		return nil, xerrors.Wrapf(sign.ErrRecovery, "der signature is not recoverable")
+	}
+
+	if err := signature.Check(secp256k1.SECP256K1()); err != nil {
+		return nil, xerrors.Wrapf(err, "strict signature check")
+	}
+
+	publicKey, compressed, err := recoverable.Recover(secp256k1.SECP256K1(), signature, hash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return pubKeyBytes(publicKey, compressed), nil
+}
+
+func (provider *providerIml) ValidAddress(address string) bool {
+	if _, _, err := bech32.DecodeSegwit(provider.params.Bech32HRP, address); err == nil {
+		return true
+	}
+
+	decoded, version, err := base58.CheckDecode(address)
+
+	if err != nil || len(decoded) != 20 {
+		return false
+	}
+
+	return version == provider.params.PubKeyHashAddrID || version == provider.params.ScriptHashAddrID
+}
+
+// FromWIF import wallet import format private key of the network
+func FromWIF(params *Params, wif string) (Key, error) {
+	decoded, version, err := base58.CheckDecode(wif)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(ErrWIF, "decode wif error: %s", err)
+	}
+
+	if version != params.PrivateKeyID {
+		return nil, xerrors.Wrapf(ErrWIF, "wif version %x mismatch network %s", version, params.Name)
+	}
+
+	compressed := false
+
+	switch {
+	case len(decoded) == 33 && decoded[32] == 0x01:
+		compressed = true
+		decoded = decoded[:32]
+	case len(decoded) != 32:
+		return nil, xerrors.Wrapf(ErrWIF, "wif private key length %d error", len(decoded))
+	}
+
+	d := new(big.Int).SetBytes(decoded)
+
+	if d.Sign() == 0 || d.Cmp(secp256k1.SECP256K1().Params().N) >= 0 {
+		return nil, xerrors.Wrapf(ErrWIF, "private key out of range")
+	}
+
+	privateKey := ecdsax.BytesToPrivateKey(decoded, secp256k1.SECP256K1())
+
+	return newKey(&providerIml{params: params}, privateKey, compressed), nil
+}
+
+func init() {
+	key.RegisterProvider(&providerIml{params: MainNet})
+	key.RegisterProvider(&providerIml{params: TestNet})
+	key.RegisterProvider(&providerIml{params: RegTest})
+}
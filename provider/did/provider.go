@@ -2,10 +2,10 @@
 package did
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/sha256"
-	"math/big"
 	"strings"
 
 	"github.com/btcsuite/btcutil/base58"
@@ -15,6 +15,7 @@ import (
 
 	"github.com/dynamicgo/xerrors"
 	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/hd"
 	"github.com/laplacenetwork/key/internal/ecdsax"
 	"github.com/laplacenetwork/key/internal/secp256k1"
 )
@@ -56,9 +57,10 @@ func pubKeyToAddress(pub *ecdsa.PublicKey) string {
 }
 
 type didImpl struct {
-	provider key.Provider
-	key      *ecdsa.PrivateKey
-	address  string // address
+	provider   key.Provider
+	key        *ecdsa.PrivateKey
+	compressed bool   // PubKey use compressed encoding
+	address    string // address
 }
 
 func (key *didImpl) Address() string {
@@ -74,7 +76,27 @@ func (key *didImpl) PriKey() []byte {
 }
 
 func (key *didImpl) PubKey() []byte {
-	return ecdsax.PublicKeyBytes(&key.key.PublicKey)
+	return pubKeyBytes(&key.key.PublicKey, key.compressed)
+}
+
+// SetCompressed switch PubKey between 33 bytes compressed and 65 bytes uncompressed encoding,
+// the address is always derived from the uncompressed public key
+func (key *didImpl) SetCompressed(compressed bool) {
+	key.compressed = compressed
+}
+
+// CryptoPrivateKey the standard library private key
+func (key *didImpl) CryptoPrivateKey() crypto.PrivateKey {
+	return key.key
+}
+
+func (key *didImpl) PublicKey() key.PublicKey {
+	return &publicKeyImpl{
+		provider:   key.provider,
+		key:        &key.key.PublicKey,
+		compressed: key.compressed,
+		address:    key.address,
+	}
 }
 
 func (key *didImpl) SetBytes(priKey []byte) {
@@ -86,21 +108,71 @@ func (key *didImpl) SetBytes(priKey []byte) {
 
 func (key *didImpl) Sign(hashed []byte) ([]byte, error) {
 
-	sig, err := recoverable.Sign(key.key, hashed, false)
+	if err := sign.CheckHash(key.key.Curve, hashed); err != nil {
+		return nil, err
+	}
+
+	sig, err := recoverable.Sign(key.key, hashed, key.compressed)
 
 	if err != nil {
 		return nil, err
 	}
 
-	size := key.key.Curve.Params().BitSize / 8
+	return sign.EncodeRecoverable(sig, key.key.Curve.Params().BitSize/8, sign.VLegacy)
+}
+
+func pubKeyBytes(pub *ecdsa.PublicKey, compressed bool) []byte {
+	if compressed {
+		return ecdsax.CompressedPublicKeyBytes(pub)
+	}
+
+	return ecdsax.PublicKeyBytes(pub)
+}
+
+type publicKeyImpl struct {
+	provider   key.Provider
+	key        *ecdsa.PublicKey
+	compressed bool   // Bytes use compressed encoding
+	address    string // address
+}
+
+func (key *publicKeyImpl) Address() string {
+	return key.address
+}
+
+func (key *publicKeyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *publicKeyImpl) Bytes() []byte {
+	return pubKeyBytes(key.key, key.compressed)
+}
+
+func (key *publicKeyImpl) Compressed() []byte {
+	return secp256k1.CompressPubkey(key.key.X, key.key.Y)
+}
+
+func (key *publicKeyImpl) Uncompressed() []byte {
+	return ecdsax.PublicKeyBytes(key.key)
+}
+
+// CryptoPublicKey the standard library public key
+func (key *publicKeyImpl) CryptoPublicKey() crypto.PublicKey {
+	return key.key
+}
 
-	buff := make([]byte, 2*size+1)
+func (key *publicKeyImpl) Verify(sig []byte, hash []byte) bool {
+	if sign.CheckHash(key.key.Curve, hash) != nil {
+		return false
+	}
 
-	copy(buff, sig.R.Bytes()[:size])
-	copy(buff[size:], sig.S.Bytes()[:size])
-	buff[2*size] = sig.V.Bytes()[0]
+	signature, err := sign.Decode(sig)
+
+	if err != nil {
+		return false
+	}
 
-	return buff, nil
+	return signature.VerifyStrict(key.key, hash)
 }
 
 type providerIml struct {
@@ -110,6 +182,19 @@ func (provider *providerIml) Name() string {
 	return "did"
 }
 
+// HashMessage double sha256 message hash
+func (provider *providerIml) HashMessage(message []byte) []byte {
+	return hash160.DoubleHash(message)
+}
+
+func (provider *providerIml) CoinType() uint32 {
+	return 1024
+}
+
+func (provider *providerIml) DefaultPath(index uint32) string {
+	return hd.BIP44Path(provider.CoinType(), 0, 0, index)
+}
+
 func (provider *providerIml) New() (key.Key, error) {
 
 	privateKey, err := ecdsa.GenerateKey(secp256k1.SECP256K1(), rand.Reader)
@@ -135,29 +220,61 @@ func (provider *providerIml) FromBytes(buff []byte) key.Key {
 	}
 }
 
+// Verify verify the signature with pubkey, if pubkey is nil only check the signature is well formed and recoverable,
+// the signature can be 65 bytes recoverable, 64 bytes eip-2098 compact or strict der encoded,
+// malleable high s signatures are rejected, use NormalizeSignature to convert them first
 func (provider *providerIml) Verify(pubkey []byte, sig []byte, hash []byte) bool {
 
-	curve := secp256k1.SECP256K1()
+	if sign.CheckHash(secp256k1.SECP256K1(), hash) != nil {
+		return false
+	}
+
+	signature, err := sign.Decode(sig)
 
-	size := curve.Params().BitSize / 8
+	if err != nil {
+		return false
+	}
 
-	if len(sig) != 2*size+1 {
+	if signature.Check(secp256k1.SECP256K1()) != nil {
 		return false
 	}
 
-	signature := &sign.Signature{
-		R: new(big.Int).SetBytes(sig[:size]),
-		S: new(big.Int).SetBytes(sig[size : 2*size]),
-		V: new(big.Int).SetBytes(sig[2*size:]),
+	if pubkey != nil {
+		publicKey, err := secp256k1.ParsePubkey(pubkey)
+
+		if err != nil {
+			return false
+		}
+
+		return signature.VerifyStrict(publicKey, hash)
+	}
+
+	if signature.V == nil {
+		return false
 	}
 
-	publicKey, _, err := recoverable.Recover(curve, signature, hash)
+	publicKey, _, err := recoverable.Recover(secp256k1.SECP256K1(), signature, hash)
 
 	if err != nil {
 		return false
 	}
 
-	return signature.Verfiy(publicKey, hash)
+	return signature.VerifyStrict(publicKey, hash)
+}
+
+func (provider *providerIml) PublicKey(pubkey []byte) (key.PublicKey, error) {
+	publicKey, err := secp256k1.ParsePubkey(pubkey)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(key.ErrPublicKey, "decode public key error: %s", err)
+	}
+
+	return &publicKeyImpl{
+		provider:   provider,
+		key:        publicKey,
+		compressed: len(pubkey) == 33,
+		address:    pubKeyToAddress(publicKey),
+	}, nil
 }
 
 func (provider *providerIml) PublicKeyToAddress(pubkey []byte) (string, error) {
@@ -171,24 +288,29 @@ func (provider *providerIml) PublicKeyToAddress(pubkey []byte) (string, error) {
 	return pubKeyToAddress(publicKey), nil
 }
 
+// Recover recover the public key, the 33 bytes compressed public key is returned if v has the compressed flag
 func (provider *providerIml) Recover(sig []byte, hash []byte) (pubkey []byte, err error) {
-	curve := secp256k1.SECP256K1()
+	signature, err := sign.Decode(sig)
 
-	size := curve.Params().BitSize / 8
+	if err != nil {
+		return nil, err
+	}
 
-	if len(sig) != 2*size+1 {
-		return nil, xerrors.Wrapf(key.ErrPublicKey, " public key length error")
+	if signature.V == nil {
+		return nil, xerrors.Wrapf(sign.ErrRecovery, "der signature is not recoverable")
 	}
 
-	signature := &sign.Signature{
-		R: new(big.Int).SetBytes(sig[:size]),
-		S: new(big.Int).SetBytes(sig[size : 2*size]),
-		V: new(big.Int).SetBytes(sig[2*size:]),
+	if err := signature.Check(secp256k1.SECP256K1()); err != nil {
+		return nil, xerrors.Wrapf(err, "strict signature check")
 	}
 
-	publicKey, _, err := recoverable.Recover(curve, signature, hash)
+	publicKey, compressed, err := recoverable.Recover(secp256k1.SECP256K1(), signature, hash)
+
+	if err != nil {
+		return nil, err
+	}
 
-	return ecdsax.PublicKeyBytes(publicKey), nil
+	return pubKeyBytes(publicKey, compressed), nil
 }
 
 func (provider *providerIml) ValidAddress(address string) bool {
@@ -211,6 +333,27 @@ func (provider *providerIml) ValidAddress(address string) bool {
 	return v == version
 }
 
+// NormalizeSignature convert high s signature to the lower s form with flipped recovery id,
+// the normalized signature keeps the encoding of the input signature
+func NormalizeSignature(sig []byte) ([]byte, error) {
+	return sign.NormalizeEncoded(secp256k1.SECP256K1(), sig)
+}
+
+// FromPrivateKey wrap secp256k1 ecdsa private key as key without copying through raw bytes
+func FromPrivateKey(privateKey *ecdsa.PrivateKey) (key.Key, error) {
+	if privateKey == nil || privateKey.Curve != secp256k1.SECP256K1() || privateKey.D == nil || privateKey.X == nil || privateKey.Y == nil {
+		return nil, xerrors.Wrapf(key.ErrPrivateKey, "private key must be a complete secp256k1 key")
+	}
+
+	return &didImpl{
+		provider: registered,
+		key:      privateKey,
+		address:  pubKeyToAddress(&privateKey.PublicKey),
+	}, nil
+}
+
+var registered = &providerIml{}
+
 func init() {
-	key.RegisterProvider(&providerIml{})
+	key.RegisterProvider(registered)
 }
@@ -0,0 +1,101 @@
+package eth
+
+import (
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Address errors
+var (
+	ErrAddressPrefix   = errors.New("address missing 0x prefix")
+	ErrAddressLength   = errors.New("address must be 20 bytes")
+	ErrAddressHex      = errors.New("address is not hex encoded")
+	ErrAddressChecksum = errors.New("address checksum mismatch")
+)
+
+// checksum apply eip-55 checksum to the lower case hex address, the eip-1191 chain specific
+// checksum is used if the chainID is not zero
+func checksum(unchecksummed string, chainID uint64) string {
+	hashInput := unchecksummed
+
+	if chainID != 0 {
+		hashInput = strconv.FormatUint(chainID, 10) + "0x" + unchecksummed
+	}
+
+	hash := keccak256([]byte(hashInput))
+
+	result := []byte(unchecksummed)
+
+	for i := 0; i < len(result); i++ {
+		hashByte := hash[i/2]
+		if i%2 == 0 {
+			hashByte = hashByte >> 4
+		} else {
+			hashByte &= 0xf
+		}
+		if result[i] > '9' && hashByte > 7 {
+			result[i] -= 32
+		}
+	}
+
+	return "0x" + string(result)
+}
+
+func checkAddress(address string, chainID uint64, strict bool) (string, error) {
+	hexAddress := address
+
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		hexAddress = address[2:]
+	} else if strict {
+		return "", xerrors.Wrapf(ErrAddressPrefix, "address %s", address)
+	}
+
+	if len(hexAddress) != 40 {
+		return "", xerrors.Wrapf(ErrAddressLength, "address %s hex length %d", address, len(hexAddress))
+	}
+
+	if _, err := hex.DecodeString(hexAddress); err != nil {
+		return "", xerrors.Wrapf(ErrAddressHex, "address %s", address)
+	}
+
+	checksummed := checksum(strings.ToLower(hexAddress), chainID)
+
+	if !strict && (hexAddress == strings.ToLower(hexAddress) || hexAddress == strings.ToUpper(hexAddress)) {
+		return checksummed, nil
+	}
+
+	if "0x"+hexAddress != checksummed {
+		return "", xerrors.Wrapf(ErrAddressChecksum, "address %s expect %s", address, checksummed)
+	}
+
+	return checksummed, nil
+}
+
+// CheckAddress check eip-55 address, all lower or all upper case address without checksum
+// is accepted unless strict, the strict mode requires 0x prefix and valid checksum
+func CheckAddress(address string, strict bool) error {
+	_, err := checkAddress(address, 0, strict)
+
+	return err
+}
+
+// CheckChainAddress check eip-1191 chain specific checksum address, e.g. rsk mainnet chain id 30
+func CheckChainAddress(address string, chainID uint64, strict bool) error {
+	_, err := checkAddress(address, chainID, strict)
+
+	return err
+}
+
+// NormalizeAddress get the eip-55 checksum address
+func NormalizeAddress(address string) (string, error) {
+	return checkAddress(address, 0, false)
+}
+
+// NormalizeChainAddress get the eip-1191 chain specific checksum address
+func NormalizeChainAddress(address string, chainID uint64) (string, error) {
+	return checkAddress(address, chainID, false)
+}
@@ -0,0 +1,68 @@
+package eth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEIP55(t *testing.T) {
+	for _, address := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		require.NoError(t, CheckAddress(address, true))
+
+		normalized, err := NormalizeAddress(strings.ToLower(address))
+
+		require.NoError(t, err)
+
+		require.Equal(t, address, normalized)
+
+		normalized, err = NormalizeAddress("0x" + strings.ToUpper(address[2:]))
+
+		require.NoError(t, err)
+
+		require.Equal(t, address, normalized)
+
+		require.NoError(t, CheckAddress(strings.ToLower(address), false))
+		require.ErrorIs(t, CheckAddress(strings.ToLower(address), true), ErrAddressChecksum)
+		require.ErrorIs(t, CheckAddress(address[2:], true), ErrAddressPrefix)
+	}
+
+	require.ErrorIs(t, CheckAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", false), ErrAddressChecksum)
+	require.ErrorIs(t, CheckAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", false), ErrAddressLength)
+	require.ErrorIs(t, CheckAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", false), ErrAddressHex)
+}
+
+func TestEIP1191(t *testing.T) {
+	for chainID, addresses := range map[uint64][]string{
+		30: {
+			"0x5aaEB6053f3e94c9b9a09f33669435E7ef1bEAeD",
+			"0xFb6916095cA1Df60bb79ce92cE3EA74c37c5d359",
+			"0xDBF03B407c01E7CD3cBea99509D93F8Dddc8C6FB",
+			"0xD1220A0Cf47c7B9BE7a2e6ba89F429762E7B9adB",
+		},
+		31: {
+			"0x5aAeb6053F3e94c9b9A09F33669435E7EF1BEaEd",
+			"0xFb6916095CA1dF60bb79CE92ce3Ea74C37c5D359",
+			"0xdbF03B407C01E7cd3cbEa99509D93f8dDDc8C6fB",
+			"0xd1220a0CF47c7B9Be7A2E6Ba89f429762E7b9adB",
+		},
+	} {
+		for _, address := range addresses {
+			require.NoError(t, CheckChainAddress(address, chainID, true))
+
+			normalized, err := NormalizeChainAddress(strings.ToLower(address), chainID)
+
+			require.NoError(t, err)
+
+			require.Equal(t, address, normalized)
+
+			require.Error(t, CheckAddress(address, true))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package eth
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/secp256k1"
+	"github.com/laplacenetwork/key/sign"
+	"github.com/laplacenetwork/key/sign/recoverable"
+)
+
+const personalMessagePrefix = "\x19Ethereum Signed Message:\n"
+
+// HashPersonalMessage eip-191 personal_sign hash keccak256("\x19Ethereum Signed Message:\n" || len(message) || message)
+func HashPersonalMessage(message []byte) []byte {
+	return keccak256([]byte(personalMessagePrefix+strconv.Itoa(len(message))), message)
+}
+
+// RecoverPersonalMessage recover the signer address of personal_sign signature
+func RecoverPersonalMessage(message []byte, sig []byte) (string, error) {
+	return RecoverAddress(HashPersonalMessage(message), sig)
+}
+
+// RecoverAddress recover the signer address from hash and r || s || v signature, v is 0/1 or 27/28,
+// high s signatures are rejected
+func RecoverAddress(hash []byte, sig []byte) (string, error) {
+	r, s, recid, err := splitSignature(sig)
+
+	if err != nil {
+		return "", err
+	}
+
+	signature := &sign.Signature{
+		R: r,
+		S: s,
+		V: big.NewInt(int64(27 + recid)),
+	}
+
+	if err := signature.Check(secp256k1.SECP256K1()); err != nil {
+		return "", xerrors.Wrapf(ErrSignature, "%s", err)
+	}
+
+	publicKey, _, err := recoverable.Recover(secp256k1.SECP256K1(), signature, hash)
+
+	if err != nil {
+		return "", xerrors.Wrapf(ErrSignature, "recover public key error: %s", err)
+	}
+
+	return pubKeyToAddress(publicKey), nil
+}
+
+// SignPersonalMessage sign message with eip-191 personal_sign prefix
+func (key *keyImpl) SignPersonalMessage(message []byte) ([]byte, error) {
+	return key.Sign(HashPersonalMessage(message))
+}
+
+// SignTypedData sign eip-712 typed data digest
+func (key *keyImpl) SignTypedData(data *TypedData) ([]byte, error) {
+	digest, err := data.Digest()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Sign(digest)
+}
@@ -1,16 +1,16 @@
 package eth
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/hex"
-	"math/big"
-	"strings"
 
 	"github.com/dynamicgo/xerrors"
 	"github.com/openzknetwork/sha3"
 
 	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/hd"
 	"github.com/laplacenetwork/key/internal/ecdsax"
 	"github.com/laplacenetwork/key/internal/secp256k1"
 	"github.com/laplacenetwork/key/sign"
@@ -34,35 +34,22 @@ func pubKeyToAddress(pub *ecdsa.PublicKey) string {
 
 	copy(address[20-len(pubBytes):], pubBytes)
 
-	unchecksummed := hex.EncodeToString(address)
-
-	sha := sha3.NewKeccak256()
-
-	sha.Write([]byte(unchecksummed))
-
-	hash := sha.Sum(nil)
-
-	result := []byte(unchecksummed)
-
-	for i := 0; i < len(result); i++ {
-		hashByte := hash[i/2]
-		if i%2 == 0 {
-			hashByte = hashByte >> 4
-		} else {
-			hashByte &= 0xf
-		}
-		if result[i] > '9' && hashByte > 7 {
-			result[i] -= 32
-		}
-	}
+	return checksum(hex.EncodeToString(address), 0)
+}
 
-	return "0x" + string(result)
+// Key ethereum key
+type Key interface {
+	key.Key
+	SignTx(tx Transaction) ([]byte, error)              // sign transaction and return the raw signed transaction
+	SignPersonalMessage(message []byte) ([]byte, error) // eip-191 personal_sign
+	SignTypedData(data *TypedData) ([]byte, error)      // eip-712 eth_signTypedData_v4
 }
 
 type keyImpl struct {
-	provider key.Provider
-	key      *ecdsa.PrivateKey
-	address  string // address
+	provider   key.Provider
+	key        *ecdsa.PrivateKey
+	compressed bool   // PubKey use compressed encoding
+	address    string // address
 }
 
 func (key *keyImpl) Address() string {
@@ -78,7 +65,30 @@ func (key *keyImpl) PriKey() []byte {
 }
 
 func (key *keyImpl) PubKey() []byte {
-	return ecdsax.PublicKeyBytes(&key.key.PublicKey)
+	return pubKeyBytes(&key.key.PublicKey, key.compressed)
+}
+
+// SetCompressed switch PubKey between 33 bytes compressed and 65 bytes uncompressed encoding,
+// the address is always derived from the uncompressed public key. Unlike did and btc, ethereum
+// signatures never carry the compressed flag because ecrecover and transactions only accept v 27/28,
+// v 31/32 is rejected and Recover always returns the 65 bytes uncompressed public key, compare it
+// with PublicKey().Uncompressed()
+func (key *keyImpl) SetCompressed(compressed bool) {
+	key.compressed = compressed
+}
+
+// CryptoPrivateKey the standard library private key
+func (key *keyImpl) CryptoPrivateKey() crypto.PrivateKey {
+	return key.key
+}
+
+func (key *keyImpl) PublicKey() key.PublicKey {
+	return &publicKeyImpl{
+		provider:   key.provider,
+		key:        &key.key.PublicKey,
+		compressed: key.compressed,
+		address:    key.address,
+	}
 }
 
 func (key *keyImpl) SetBytes(priKey []byte) {
@@ -87,23 +97,107 @@ func (key *keyImpl) SetBytes(priKey []byte) {
 	key.address = pubKeyToAddress(&key.key.PublicKey)
 }
 
+// Sign sign the hashed message with v 27/28, the compressed flag is never set, see SetCompressed
 func (key *keyImpl) Sign(hashed []byte) ([]byte, error) {
 
+	if err := sign.CheckHash(key.key.Curve, hashed); err != nil {
+		return nil, err
+	}
+
 	sig, err := recoverable.Sign(key.key, hashed, false)
 
 	if err != nil {
 		return nil, err
 	}
 
-	size := key.key.Curve.Params().BitSize / 8
+	return sign.EncodeRecoverable(sig, key.key.Curve.Params().BitSize/8, sign.VLegacy)
+}
+
+// SignTx sign the transaction and return the raw signed transaction
+func (key *keyImpl) SignTx(tx Transaction) ([]byte, error) {
+	hash, err := tx.SigningHash()
+
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := key.Sign(hash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.Encode(sig)
+}
+
+// decodeSignature decode the signature by sign.Decode, ethereum v is only 27/28 so the compressed
+// public key flag of v 31/32 is rejected
+func decodeSignature(sig []byte) (*sign.Signature, error) {
+	signature, err := sign.Decode(sig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if signature.Compressed() {
+		return nil, xerrors.Wrapf(sign.ErrRecovery, "ethereum v has no compressed flag")
+	}
+
+	return signature, nil
+}
+
+func pubKeyBytes(pub *ecdsa.PublicKey, compressed bool) []byte {
+	if compressed {
+		return ecdsax.CompressedPublicKeyBytes(pub)
+	}
+
+	return ecdsax.PublicKeyBytes(pub)
+}
+
+type publicKeyImpl struct {
+	provider   key.Provider
+	key        *ecdsa.PublicKey
+	compressed bool   // Bytes use compressed encoding
+	address    string // address
+}
+
+func (key *publicKeyImpl) Address() string {
+	return key.address
+}
+
+func (key *publicKeyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *publicKeyImpl) Bytes() []byte {
+	return pubKeyBytes(key.key, key.compressed)
+}
+
+func (key *publicKeyImpl) Compressed() []byte {
+	return secp256k1.CompressPubkey(key.key.X, key.key.Y)
+}
+
+func (key *publicKeyImpl) Uncompressed() []byte {
+	return ecdsax.PublicKeyBytes(key.key)
+}
+
+// CryptoPublicKey the standard library public key
+func (key *publicKeyImpl) CryptoPublicKey() crypto.PublicKey {
+	return key.key
+}
+
+func (key *publicKeyImpl) Verify(sig []byte, hash []byte) bool {
+	if sign.CheckHash(key.key.Curve, hash) != nil {
+		return false
+	}
 
-	buff := make([]byte, 2*size+1)
+	signature, err := decodeSignature(sig)
 
-	copy(buff, sig.R.Bytes()[:size])
-	copy(buff[size:], sig.S.Bytes()[:size])
-	buff[2*size] = sig.V.Bytes()[0]
+	if err != nil {
+		return false
+	}
 
-	return buff, nil
+	return signature.VerifyStrict(key.key, hash)
 }
 
 type providerIml struct {
@@ -113,29 +207,59 @@ func (provider *providerIml) Name() string {
 	return "eth"
 }
 
+// HashMessage keccak256 message hash
+func (provider *providerIml) HashMessage(message []byte) []byte {
+	return keccak256(message)
+}
+
+func (provider *providerIml) CoinType() uint32 {
+	return 60
+}
+
+func (provider *providerIml) DefaultPath(index uint32) string {
+	return hd.BIP44Path(provider.CoinType(), 0, 0, index)
+}
+
+// Verify verify the signature with pubkey, if pubkey is nil only check the signature is well formed and recoverable,
+// the signature can be 65 bytes recoverable, 64 bytes eip-2098 compact or strict der encoded,
+// malleable high s signatures are rejected, use NormalizeSignature to convert them first
 func (provider *providerIml) Verify(pubkey []byte, sig []byte, hash []byte) bool {
 
-	curve := secp256k1.SECP256K1()
+	if sign.CheckHash(secp256k1.SECP256K1(), hash) != nil {
+		return false
+	}
+
+	signature, err := decodeSignature(sig)
 
-	size := curve.Params().BitSize / 8
+	if err != nil {
+		return false
+	}
 
-	if len(sig) != 2*size+1 {
+	if signature.Check(secp256k1.SECP256K1()) != nil {
 		return false
 	}
 
-	signature := &sign.Signature{
-		R: new(big.Int).SetBytes(sig[:size]),
-		S: new(big.Int).SetBytes(sig[size : 2*size]),
-		V: new(big.Int).SetBytes(sig[2*size:]),
+	if pubkey != nil {
+		publicKey, err := secp256k1.ParsePubkey(pubkey)
+
+		if err != nil {
+			return false
+		}
+
+		return signature.VerifyStrict(publicKey, hash)
+	}
+
+	if signature.V == nil {
+		return false
 	}
 
-	publicKey, _, err := recoverable.Recover(curve, signature, hash)
+	publicKey, _, err := recoverable.Recover(secp256k1.SECP256K1(), signature, hash)
 
 	if err != nil {
 		return false
 	}
 
-	return signature.Verfiy(publicKey, hash)
+	return signature.VerifyStrict(publicKey, hash)
 }
 
 func (provider *providerIml) New() (key.Key, error) {
@@ -163,53 +287,90 @@ func (provider *providerIml) FromBytes(buff []byte) key.Key {
 	}
 }
 
+func (provider *providerIml) PublicKey(pubkey []byte) (key.PublicKey, error) {
+	publicKey, err := secp256k1.ParsePubkey(pubkey)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(key.ErrPublicKey, "decode public key error: %s", err)
+	}
+
+	return &publicKeyImpl{
+		provider:   provider,
+		key:        publicKey,
+		compressed: len(pubkey) == 33,
+		address:    pubKeyToAddress(publicKey),
+	}, nil
+}
+
 func (provider *providerIml) PublicKeyToAddress(pubkey []byte) (string, error) {
 	publicKey := ecdsax.BytesToPublicKey(secp256k1.SECP256K1(), pubkey)
 
-	if nil == pubkey {
+	if nil == publicKey {
 		return "", xerrors.Wrapf(key.ErrPublicKey, "decode public key error")
 	}
 
 	return pubKeyToAddress(publicKey), nil
 }
 
+// Recover recover the 65 bytes uncompressed public key, v with the compressed flag is rejected
 func (provider *providerIml) Recover(sig []byte, hash []byte) (pubkey []byte, err error) {
-	curve := secp256k1.SECP256K1()
+	signature, err := decodeSignature(sig)
 
-	size := curve.Params().BitSize / 8
+	if err != nil {
+		return nil, err
+	}
 
-	if len(sig) != 2*size+1 {
-		return nil, xerrors.Wrapf(key.ErrPublicKey, " public key length error")
+	if signature.V == nil {
+		return nil, xerrors.Wrapf(sign.ErrRecovery, "der signature is not recoverable")
 	}
 
-	signature := &sign.Signature{
-		R: new(big.Int).SetBytes(sig[:size]),
-		S: new(big.Int).SetBytes(sig[size : 2*size]),
-		V: new(big.Int).SetBytes(sig[2*size:]),
+	if err := signature.Check(secp256k1.SECP256K1()); err != nil {
+		return nil, xerrors.Wrapf(err, "strict signature check")
 	}
 
-	publicKey, _, err := recoverable.Recover(curve, signature, hash)
+	publicKey, _, err := recoverable.Recover(secp256k1.SECP256K1(), signature, hash)
+
+	if err != nil {
+		return nil, err
+	}
 
 	return ecdsax.PublicKeyBytes(publicKey), nil
 }
 
+// ValidAddress check eip-55 address, all lower or all upper case address without checksum is valid
 func (provider *providerIml) ValidAddress(address string) bool {
+	return CheckAddress(address, false) == nil
+}
 
-	address = strings.TrimPrefix(address, "0x")
+func (provider *providerIml) CheckAddress(address string) error {
+	return CheckAddress(address, false)
+}
 
-	if len(address) != 40 {
-		return false
-	}
+func (provider *providerIml) NormalizeAddress(address string) (string, error) {
+	return NormalizeAddress(address)
+}
 
-	_, err := hex.DecodeString(address)
+// NormalizeSignature convert high s signature to the lower s form with flipped recovery id,
+// the normalized signature keeps the encoding of the input signature
+func NormalizeSignature(sig []byte) ([]byte, error) {
+	return sign.NormalizeEncoded(secp256k1.SECP256K1(), sig)
+}
 
-	if err != nil {
-		return false
+// FromPrivateKey wrap secp256k1 ecdsa private key as key without copying through raw bytes
+func FromPrivateKey(privateKey *ecdsa.PrivateKey) (Key, error) {
+	if privateKey == nil || privateKey.Curve != secp256k1.SECP256K1() || privateKey.D == nil || privateKey.X == nil || privateKey.Y == nil {
+		return nil, xerrors.Wrapf(key.ErrPrivateKey, "private key must be a complete secp256k1 key")
 	}
 
-	return true
+	return &keyImpl{
+		provider: registered,
+		key:      privateKey,
+		address:  pubKeyToAddress(&privateKey.PublicKey),
+	}, nil
 }
 
+var registered = &providerIml{}
+
 func init() {
-	key.RegisterProvider(&providerIml{})
+	key.RegisterProvider(registered)
 }
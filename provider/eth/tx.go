@@ -0,0 +1,528 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+	"github.com/openzknetwork/sha3"
+
+	"github.com/laplacenetwork/key/internal/rlp"
+	"github.com/laplacenetwork/key/sign"
+)
+
+// Errors
+var (
+	ErrTxType    = errors.New("unsupported transaction type")
+	ErrTx        = errors.New("invalid transaction")
+	ErrSignature = errors.New("invalid signature")
+)
+
+// Transaction types
+const (
+	LegacyTxType     = 0x00
+	AccessListTxType = 0x01 // eip-2930
+	DynamicFeeTxType = 0x02 // eip-1559
+)
+
+// Transaction ethereum transaction
+type Transaction interface {
+	Type() byte
+	SigningHash() ([]byte, error)      // keccak256 hash to be signed
+	Encode(sig []byte) ([]byte, error) // raw signed transaction with r || s || v signature, v is 0/1 or 27/28
+}
+
+// AccessTuple eip-2930 access list entry
+type AccessTuple struct {
+	Address     []byte   // 20 bytes address
+	StorageKeys [][]byte // 32 bytes storage keys
+}
+
+// AccessList eip-2930 access list
+type AccessList []AccessTuple
+
+func (list AccessList) rlpList() rlp.List {
+	result := rlp.List{}
+
+	for _, tuple := range list {
+		result = append(result, rlp.List{tuple.Address, tuple.StorageKeys})
+	}
+
+	return result
+}
+
+// LegacyTx legacy transaction, signed with eip-155 replay protection if ChainID is not nil or zero
+type LegacyTx struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	To       []byte // nil for contract creation
+	Value    *big.Int
+	Data     []byte
+	ChainID  *big.Int
+}
+
+// Type .
+func (tx *LegacyTx) Type() byte {
+	return LegacyTxType
+}
+
+func (tx *LegacyTx) fields() rlp.List {
+	return rlp.List{tx.Nonce, tx.GasPrice, tx.Gas, tx.To, tx.Value, tx.Data}
+}
+
+func (tx *LegacyTx) eip155() bool {
+	return tx.ChainID != nil && tx.ChainID.Sign() > 0
+}
+
+// SigningHash .
+func (tx *LegacyTx) SigningHash() ([]byte, error) {
+	fields := tx.fields()
+
+	if tx.eip155() {
+		fields = append(fields, tx.ChainID, uint64(0), uint64(0))
+	}
+
+	return rlpHash(fields)
+}
+
+// Encode .
+func (tx *LegacyTx) Encode(sig []byte) ([]byte, error) {
+	r, s, recid, err := splitSignature(sig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	v := big.NewInt(int64(27 + recid))
+
+	if tx.eip155() {
+		v.Mul(tx.ChainID, big.NewInt(2))
+		v.Add(v, big.NewInt(int64(35+recid)))
+	}
+
+	return rlp.Encode(append(tx.fields(), v, r, s))
+}
+
+// AccessListTx eip-2930 access list transaction
+type AccessListTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasPrice   *big.Int
+	Gas        uint64
+	To         []byte // nil for contract creation
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+}
+
+// Type .
+func (tx *AccessListTx) Type() byte {
+	return AccessListTxType
+}
+
+func (tx *AccessListTx) fields() rlp.List {
+	return rlp.List{tx.ChainID, tx.Nonce, tx.GasPrice, tx.Gas, tx.To, tx.Value, tx.Data, tx.AccessList.rlpList()}
+}
+
+// SigningHash .
+func (tx *AccessListTx) SigningHash() ([]byte, error) {
+	return typedHash(tx.Type(), tx.fields())
+}
+
+// Encode .
+func (tx *AccessListTx) Encode(sig []byte) ([]byte, error) {
+	return typedEncode(tx.Type(), tx.fields(), sig)
+}
+
+// DynamicFeeTx eip-1559 dynamic fee transaction
+type DynamicFeeTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int // max priority fee per gas
+	GasFeeCap  *big.Int // max fee per gas
+	Gas        uint64
+	To         []byte // nil for contract creation
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+}
+
+// Type .
+func (tx *DynamicFeeTx) Type() byte {
+	return DynamicFeeTxType
+}
+
+func (tx *DynamicFeeTx) fields() rlp.List {
+	return rlp.List{tx.ChainID, tx.Nonce, tx.GasTipCap, tx.GasFeeCap, tx.Gas, tx.To, tx.Value, tx.Data, tx.AccessList.rlpList()}
+}
+
+// SigningHash .
+func (tx *DynamicFeeTx) SigningHash() ([]byte, error) {
+	return typedHash(tx.Type(), tx.fields())
+}
+
+// Encode .
+func (tx *DynamicFeeTx) Encode(sig []byte) ([]byte, error) {
+	return typedEncode(tx.Type(), tx.fields(), sig)
+}
+
+func keccak256(data ...[]byte) []byte {
+	hasher := sha3.NewKeccak256()
+
+	for _, buff := range data {
+		hasher.Write(buff)
+	}
+
+	return hasher.Sum(nil)
+}
+
+func rlpHash(fields rlp.List) ([]byte, error) {
+	buff, err := rlp.Encode(fields)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak256(buff), nil
+}
+
+func typedHash(txType byte, fields rlp.List) ([]byte, error) {
+	buff, err := rlp.Encode(fields)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak256([]byte{txType}, buff), nil
+}
+
+func typedEncode(txType byte, fields rlp.List, sig []byte) ([]byte, error) {
+	r, s, recid, err := splitSignature(sig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buff, err := rlp.Encode(append(fields, uint64(recid), r, s))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{txType}, buff...), nil
+}
+
+func splitSignature(sig []byte) (*big.Int, *big.Int, byte, error) {
+	if len(sig) != 65 {
+		return nil, nil, 0, xerrors.Wrapf(ErrSignature, "signature length %d error", len(sig))
+	}
+
+	// transactions and RecoverAddress take both the raw 0/1 recovery id and v 27/28
+	offset := sign.VLegacy
+
+	if sig[64] < sign.VLegacy {
+		offset = sign.VRaw
+	}
+
+	signature, err := sign.DecodeRecoverableOffset(sig, offset)
+
+	if err != nil {
+		return nil, nil, 0, xerrors.Wrapf(ErrSignature, "%s", err)
+	}
+
+	if signature.Compressed() {
+		return nil, nil, 0, xerrors.Wrapf(ErrSignature, "ethereum v has no compressed flag")
+	}
+
+	recid, err := signature.RecoveryID()
+
+	if err != nil {
+		return nil, nil, 0, xerrors.Wrapf(ErrSignature, "%s", err)
+	}
+
+	return signature.R, signature.S, recid, nil
+}
+
+func joinSignature(r, s *big.Int, recid byte) []byte {
+	buff, _ := sign.EncodeRecoverable(&sign.Signature{R: r, S: s, V: big.NewInt(int64(recid))}, 32, sign.VLegacy)
+
+	return buff
+}
+
+// TxHash the transaction hash of raw signed transaction
+func TxHash(raw []byte) []byte {
+	return keccak256(raw)
+}
+
+// DecodeTransaction decode raw signed transaction, return the transaction and r || s || v signature with v 27/28
+func DecodeTransaction(raw []byte) (Transaction, []byte, error) {
+	if len(raw) == 0 {
+		return nil, nil, xerrors.Wrapf(ErrTx, "empty transaction")
+	}
+
+	if raw[0] >= 0xc0 {
+		return decodeLegacyTx(raw)
+	}
+
+	item, err := rlp.Decode(raw[1:])
+
+	if err != nil {
+		return nil, nil, xerrors.Wrapf(ErrTx, "decode rlp error: %s", err)
+	}
+
+	fields, err := rlp.ToList(item)
+
+	if err != nil {
+		return nil, nil, xerrors.Wrapf(ErrTx, "%s", err)
+	}
+
+	var d decoder
+
+	switch raw[0] {
+	case AccessListTxType:
+		if len(fields) != 11 {
+			return nil, nil, xerrors.Wrapf(ErrTx, "access list transaction expect 11 fields got %d", len(fields))
+		}
+
+		tx := &AccessListTx{
+			ChainID:    d.bigInt(fields[0]),
+			Nonce:      d.uint64(fields[1]),
+			GasPrice:   d.bigInt(fields[2]),
+			Gas:        d.uint64(fields[3]),
+			To:         d.address(fields[4]),
+			Value:      d.bigInt(fields[5]),
+			Data:       d.bytes(fields[6]),
+			AccessList: d.accessList(fields[7]),
+		}
+
+		sig := d.signature(fields[8:], nil)
+
+		if d.err != nil {
+			return nil, nil, d.err
+		}
+
+		return tx, sig, nil
+	case DynamicFeeTxType:
+		if len(fields) != 12 {
+			return nil, nil, xerrors.Wrapf(ErrTx, "dynamic fee transaction expect 12 fields got %d", len(fields))
+		}
+
+		tx := &DynamicFeeTx{
+			ChainID:    d.bigInt(fields[0]),
+			Nonce:      d.uint64(fields[1]),
+			GasTipCap:  d.bigInt(fields[2]),
+			GasFeeCap:  d.bigInt(fields[3]),
+			Gas:        d.uint64(fields[4]),
+			To:         d.address(fields[5]),
+			Value:      d.bigInt(fields[6]),
+			Data:       d.bytes(fields[7]),
+			AccessList: d.accessList(fields[8]),
+		}
+
+		sig := d.signature(fields[9:], nil)
+
+		if d.err != nil {
+			return nil, nil, d.err
+		}
+
+		return tx, sig, nil
+	default:
+		return nil, nil, xerrors.Wrapf(ErrTxType, "type %d", raw[0])
+	}
+}
+
+func decodeLegacyTx(raw []byte) (Transaction, []byte, error) {
+	item, err := rlp.Decode(raw)
+
+	if err != nil {
+		return nil, nil, xerrors.Wrapf(ErrTx, "decode rlp error: %s", err)
+	}
+
+	fields, err := rlp.ToList(item)
+
+	if err != nil {
+		return nil, nil, xerrors.Wrapf(ErrTx, "%s", err)
+	}
+
+	if len(fields) != 9 {
+		return nil, nil, xerrors.Wrapf(ErrTx, "legacy transaction expect 9 fields got %d", len(fields))
+	}
+
+	var d decoder
+
+	tx := &LegacyTx{
+		Nonce:    d.uint64(fields[0]),
+		GasPrice: d.bigInt(fields[1]),
+		Gas:      d.uint64(fields[2]),
+		To:       d.address(fields[3]),
+		Value:    d.bigInt(fields[4]),
+		Data:     d.bytes(fields[5]),
+	}
+
+	sig := d.signature(fields[6:], tx)
+
+	if d.err != nil {
+		return nil, nil, d.err
+	}
+
+	return tx, sig, nil
+}
+
+// decoder keep the first field decode error
+type decoder struct {
+	err error
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = xerrors.Wrapf(ErrTx, "%s", err)
+	}
+}
+
+func (d *decoder) bytes(item interface{}) []byte {
+	buff, err := rlp.Bytes(item)
+
+	if err != nil {
+		d.fail(err)
+	}
+
+	return buff
+}
+
+func (d *decoder) bigInt(item interface{}) *big.Int {
+	v, err := rlp.BigInt(item)
+
+	if err != nil {
+		d.fail(err)
+	}
+
+	return v
+}
+
+func (d *decoder) uint64(item interface{}) uint64 {
+	v, err := rlp.Uint64(item)
+
+	if err != nil {
+		d.fail(err)
+	}
+
+	return v
+}
+
+func (d *decoder) address(item interface{}) []byte {
+	buff := d.bytes(item)
+
+	if len(buff) == 0 {
+		return nil
+	}
+
+	if len(buff) != 20 {
+		d.fail(xerrors.Errorf("address length %d error", len(buff)))
+	}
+
+	return buff
+}
+
+func (d *decoder) accessList(item interface{}) AccessList {
+	list, err := rlp.ToList(item)
+
+	if err != nil {
+		d.fail(err)
+		return nil
+	}
+
+	result := AccessList{}
+
+	for _, entry := range list {
+		tuple, err := rlp.ToList(entry)
+
+		if err != nil || len(tuple) != 2 {
+			d.fail(xerrors.Errorf("invalid access list tuple"))
+			return nil
+		}
+
+		keys, err := rlp.ToList(tuple[1])
+
+		if err != nil {
+			d.fail(err)
+			return nil
+		}
+
+		storageKeys := [][]byte{}
+
+		for _, key := range keys {
+			storageKey := d.bytes(key)
+
+			if len(storageKey) != 32 {
+				d.fail(xerrors.Errorf("storage key length %d error", len(storageKey)))
+			}
+
+			storageKeys = append(storageKeys, storageKey)
+		}
+
+		result = append(result, AccessTuple{Address: d.address(tuple[0]), StorageKeys: storageKeys})
+	}
+
+	return result
+}
+
+// signature decode v, r, s fields, the chain id of legacy transaction is restored from v
+func (d *decoder) signature(fields rlp.List, legacy *LegacyTx) []byte {
+	v := d.bigInt(fields[0])
+	r := d.bigInt(fields[1])
+	s := d.bigInt(fields[2])
+
+	if d.err != nil {
+		return nil
+	}
+
+	if r.BitLen() > 256 || s.BitLen() > 256 {
+		d.fail(xerrors.Errorf("signature r or s overflow"))
+		return nil
+	}
+
+	if legacy == nil {
+		if !v.IsUint64() || v.Uint64() > 1 {
+			d.fail(xerrors.Errorf("invalid y parity %s", v))
+			return nil
+		}
+
+		return joinSignature(r, s, byte(v.Uint64()))
+	}
+
+	if v.Cmp(big.NewInt(27)) == 0 || v.Cmp(big.NewInt(28)) == 0 {
+		return joinSignature(r, s, byte(v.Uint64()-27))
+	}
+
+	if v.Cmp(big.NewInt(35)) < 0 {
+		d.fail(xerrors.Errorf("invalid v %s", v))
+		return nil
+	}
+
+	// v = chainID * 2 + 35 + recid
+	chainID := new(big.Int).Sub(v, big.NewInt(35))
+
+	recid := chainID.Bit(0)
+
+	legacy.ChainID = chainID.Rsh(chainID, 1)
+
+	return joinSignature(r, s, byte(recid))
+}
+
+// Sender recover the sender address of raw signed transaction, high s signatures are rejected
+func Sender(raw []byte) (string, error) {
+	tx, sig, err := DecodeTransaction(raw)
+
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := tx.SigningHash()
+
+	if err != nil {
+		return "", err
+	}
+
+	return RecoverAddress(hash, sig)
+}
@@ -0,0 +1,121 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testKey() Key {
+	k := &keyImpl{provider: &providerIml{}}
+
+	k.SetBytes(bytes.Repeat([]byte{0x46}, 32))
+
+	return k
+}
+
+func TestEIP155(t *testing.T) {
+	k := testKey()
+
+	require.Equal(t, "0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F", k.Address())
+
+	tx := &LegacyTx{
+		Nonce:    9,
+		GasPrice: big.NewInt(20000000000),
+		Gas:      21000,
+		To:       bytes.Repeat([]byte{0x35}, 20),
+		Value:    big.NewInt(1000000000000000000),
+		Data:     []byte{},
+		ChainID:  big.NewInt(1),
+	}
+
+	hash, err := tx.SigningHash()
+
+	require.NoError(t, err)
+
+	require.Equal(t, "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53", hex.EncodeToString(hash))
+
+	raw, err := k.SignTx(tx)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83", hex.EncodeToString(raw))
+
+	decoded, _, err := DecodeTransaction(raw)
+
+	require.NoError(t, err)
+
+	require.Equal(t, tx, decoded)
+
+	sender, err := Sender(raw)
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), sender)
+}
+
+func TestTypedTx(t *testing.T) {
+	k := testKey()
+
+	accessList := AccessList{
+		{Address: bytes.Repeat([]byte{0x01}, 20), StorageKeys: [][]byte{make([]byte, 32)}},
+	}
+
+	for _, tx := range []Transaction{
+		&LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 53000, Value: big.NewInt(0), Data: []byte{0x60, 0x80}},
+		&AccessListTx{
+			ChainID:    big.NewInt(5),
+			Nonce:      2,
+			GasPrice:   big.NewInt(30000000000),
+			Gas:        30000,
+			To:         bytes.Repeat([]byte{0x35}, 20),
+			Value:      big.NewInt(1),
+			Data:       []byte{},
+			AccessList: accessList,
+		},
+		&DynamicFeeTx{
+			ChainID:    big.NewInt(1),
+			Nonce:      3,
+			GasTipCap:  big.NewInt(2000000000),
+			GasFeeCap:  big.NewInt(100000000000),
+			Gas:        21000,
+			To:         bytes.Repeat([]byte{0x35}, 20),
+			Value:      big.NewInt(1000000000000000000),
+			Data:       []byte{},
+			AccessList: AccessList{},
+		},
+	} {
+		raw, err := k.SignTx(tx)
+
+		require.NoError(t, err)
+
+		if tx.Type() != LegacyTxType {
+			require.Equal(t, tx.Type(), raw[0])
+		}
+
+		decoded, sig, err := DecodeTransaction(raw)
+
+		require.NoError(t, err)
+
+		require.Equal(t, tx.Type(), decoded.Type())
+
+		reencoded, err := decoded.Encode(sig)
+
+		require.NoError(t, err)
+
+		require.Equal(t, raw, reencoded)
+
+		sender, err := Sender(raw)
+
+		require.NoError(t, err)
+
+		require.Equal(t, k.Address(), sender)
+	}
+
+	_, _, err := DecodeTransaction([]byte{0x03, 0xc0})
+
+	require.Error(t, err)
+}
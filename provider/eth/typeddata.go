@@ -0,0 +1,437 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// ErrTypedData .
+var ErrTypedData = errors.New("invalid eip-712 typed data")
+
+const domainType = "EIP712Domain"
+
+// TypedDataField eip-712 struct member
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Types eip-712 struct type definitions
+type Types map[string][]TypedDataField
+
+// TypedData eip-712 typed structured data, the json format is eth_signTypedData_v4 payload
+type TypedData struct {
+	Types       Types                  `json:"types"`
+	PrimaryType string                 `json:"primaryType"`
+	Domain      map[string]interface{} `json:"domain"`
+	Message     map[string]interface{} `json:"message"`
+}
+
+// ParseTypedData parse eth_signTypedData_v4 json payload
+func ParseTypedData(data []byte) (*TypedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	decoder.UseNumber()
+
+	var typedData TypedData
+
+	if err := decoder.Decode(&typedData); err != nil {
+		return nil, xerrors.Wrapf(ErrTypedData, "decode json error: %s", err)
+	}
+
+	return &typedData, nil
+}
+
+// domain fields in the eip-712 specified order
+var domainFields = []TypedDataField{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+	{Name: "salt", Type: "bytes32"},
+}
+
+func (data *TypedData) types() Types {
+	if _, ok := data.Types[domainType]; ok {
+		return data.Types
+	}
+
+	types := Types{}
+
+	for name, fields := range data.Types {
+		types[name] = fields
+	}
+
+	var fields []TypedDataField
+
+	for _, field := range domainFields {
+		if _, ok := data.Domain[field.Name]; ok {
+			fields = append(fields, field)
+		}
+	}
+
+	types[domainType] = fields
+
+	return types
+}
+
+// DomainSeparator hashStruct(eip712Domain)
+func (data *TypedData) DomainSeparator() ([]byte, error) {
+	return data.types().HashStruct(domainType, data.Domain)
+}
+
+// Digest keccak256("\x19\x01" || domainSeparator || hashStruct(message))
+func (data *TypedData) Digest() ([]byte, error) {
+	domainSeparator, err := data.DomainSeparator()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data.PrimaryType == domainType {
+		return keccak256([]byte{0x19, 0x01}, domainSeparator), nil
+	}
+
+	messageHash, err := data.types().HashStruct(data.PrimaryType, data.Message)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak256([]byte{0x19, 0x01}, domainSeparator, messageHash), nil
+}
+
+// RecoverTypedData recover the signer address of eip-712 typed data signature
+func RecoverTypedData(data *TypedData, sig []byte) (string, error) {
+	digest, err := data.Digest()
+
+	if err != nil {
+		return "", err
+	}
+
+	return RecoverAddress(digest, sig)
+}
+
+var arrayType = regexp.MustCompile(`^(.+)\[(\d*)\]$`)
+
+// baseType strip all array suffixes of type name
+func baseType(name string) string {
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		return name[:i]
+	}
+
+	return name
+}
+
+func (types Types) dependencies(primaryType string, found map[string]bool) {
+	primaryType = baseType(primaryType)
+
+	if found[primaryType] {
+		return
+	}
+
+	fields, ok := types[primaryType]
+
+	if !ok {
+		return
+	}
+
+	found[primaryType] = true
+
+	for _, field := range fields {
+		types.dependencies(field.Type, found)
+	}
+}
+
+// EncodeType encode struct type as name(type member,...) followed by sorted referenced struct types
+func (types Types) EncodeType(primaryType string) (string, error) {
+	if _, ok := types[primaryType]; !ok {
+		return "", xerrors.Wrapf(ErrTypedData, "unknown type %s", primaryType)
+	}
+
+	found := map[string]bool{}
+
+	types.dependencies(primaryType, found)
+
+	delete(found, primaryType)
+
+	deps := make([]string, 0, len(found))
+
+	for dep := range found {
+		deps = append(deps, dep)
+	}
+
+	sort.Strings(deps)
+
+	var builder strings.Builder
+
+	for _, name := range append([]string{primaryType}, deps...) {
+		builder.WriteString(name)
+		builder.WriteByte('(')
+
+		for i, field := range types[name] {
+			if i > 0 {
+				builder.WriteByte(',')
+			}
+
+			builder.WriteString(field.Type)
+			builder.WriteByte(' ')
+			builder.WriteString(field.Name)
+		}
+
+		builder.WriteByte(')')
+	}
+
+	return builder.String(), nil
+}
+
+// TypeHash keccak256(encodeType(primaryType))
+func (types Types) TypeHash(primaryType string) ([]byte, error) {
+	encoded, err := types.EncodeType(primaryType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return keccak256([]byte(encoded)), nil
+}
+
+// HashStruct keccak256(typeHash || encodeData(value))
+func (types Types) HashStruct(primaryType string, value map[string]interface{}) ([]byte, error) {
+	typeHash, err := types.TypeHash(primaryType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buff := typeHash
+
+	for _, field := range types[primaryType] {
+		fieldValue, ok := value[field.Name]
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrTypedData, "%s missing field %s", primaryType, field.Name)
+		}
+
+		encoded, err := types.encodeValue(field.Type, fieldValue)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "encode %s.%s error", primaryType, field.Name)
+		}
+
+		buff = append(buff, encoded...)
+	}
+
+	return keccak256(buff), nil
+}
+
+// encodeValue encode value as 32 bytes word
+func (types Types) encodeValue(typeName string, value interface{}) ([]byte, error) {
+	if match := arrayType.FindStringSubmatch(typeName); match != nil {
+		items, ok := value.([]interface{})
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrTypedData, "%s value is not array", typeName)
+		}
+
+		if match[2] != "" {
+			length, err := strconv.Atoi(match[2])
+
+			if err != nil || length != len(items) {
+				return nil, xerrors.Wrapf(ErrTypedData, "%s array length %d mismatch", typeName, len(items))
+			}
+		}
+
+		var buff []byte
+
+		for _, item := range items {
+			encoded, err := types.encodeValue(match[1], item)
+
+			if err != nil {
+				return nil, err
+			}
+
+			buff = append(buff, encoded...)
+		}
+
+		return keccak256(buff), nil
+	}
+
+	if _, ok := types[typeName]; ok {
+		fields, ok := value.(map[string]interface{})
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrTypedData, "%s value is not struct", typeName)
+		}
+
+		return types.HashStruct(typeName, fields)
+	}
+
+	switch {
+	case typeName == "string":
+		str, ok := value.(string)
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrTypedData, "string value type %T error", value)
+		}
+
+		return keccak256([]byte(str)), nil
+	case typeName == "bytes":
+		buff, err := toBytes(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return keccak256(buff), nil
+	case typeName == "bool":
+		b, ok := value.(bool)
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrTypedData, "bool value type %T error", value)
+		}
+
+		word := make([]byte, 32)
+
+		if b {
+			word[31] = 1
+		}
+
+		return word, nil
+	case typeName == "address":
+		buff, err := toBytes(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(buff) != 20 {
+			return nil, xerrors.Wrapf(ErrTypedData, "address length %d error", len(buff))
+		}
+
+		return append(make([]byte, 12), buff...), nil
+	case strings.HasPrefix(typeName, "bytes"):
+		size, err := strconv.Atoi(strings.TrimPrefix(typeName, "bytes"))
+
+		if err != nil || size < 1 || size > 32 {
+			return nil, xerrors.Wrapf(ErrTypedData, "unknown type %s", typeName)
+		}
+
+		buff, err := toBytes(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(buff) > size {
+			return nil, xerrors.Wrapf(ErrTypedData, "%s value length %d overflow", typeName, len(buff))
+		}
+
+		word := make([]byte, 32)
+
+		copy(word, buff)
+
+		return word, nil
+	case strings.HasPrefix(typeName, "uint"), strings.HasPrefix(typeName, "int"):
+		return encodeInteger(typeName, value)
+	default:
+		return nil, xerrors.Wrapf(ErrTypedData, "unknown type %s", typeName)
+	}
+}
+
+func encodeInteger(typeName string, value interface{}) ([]byte, error) {
+	signed := strings.HasPrefix(typeName, "int")
+
+	bits, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(typeName, "u"), "int"))
+
+	if err != nil || bits < 8 || bits > 256 || bits%8 != 0 {
+		return nil, xerrors.Wrapf(ErrTypedData, "unknown type %s", typeName)
+	}
+
+	v, err := toBigInt(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if signed {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+
+		if v.Cmp(limit) >= 0 || v.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return nil, xerrors.Wrapf(ErrTypedData, "%s value %s overflow", typeName, v)
+		}
+
+		if v.Sign() < 0 {
+			v = new(big.Int).Add(v, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+	} else if v.Sign() < 0 || v.BitLen() > bits {
+		return nil, xerrors.Wrapf(ErrTypedData, "%s value %s overflow", typeName, v)
+	}
+
+	return v.FillBytes(make([]byte, 32)), nil
+}
+
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		if !strings.HasPrefix(v, "0x") && !strings.HasPrefix(v, "0X") {
+			return nil, xerrors.Wrapf(ErrTypedData, "hex value %s missing 0x prefix", v)
+		}
+
+		buff, err := hex.DecodeString(v[2:])
+
+		if err != nil {
+			return nil, xerrors.Wrapf(ErrTypedData, "decode hex value %s error", v)
+		}
+
+		return buff, nil
+	default:
+		return nil, xerrors.Wrapf(ErrTypedData, "bytes value type %T error", value)
+	}
+}
+
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case float64:
+		f := big.NewFloat(v)
+
+		if !f.IsInt() {
+			return nil, xerrors.Wrapf(ErrTypedData, "integer value %v is not integral", v)
+		}
+
+		i, _ := f.Int(nil)
+
+		return i, nil
+	case json.Number:
+		return toBigInt(string(v))
+	case string:
+		i, ok := new(big.Int).SetString(v, 0)
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrTypedData, "parse integer value %s error", v)
+		}
+
+		return i, nil
+	default:
+		return nil, xerrors.Wrapf(ErrTypedData, "integer value type %T error", value)
+	}
+}
@@ -0,0 +1,290 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/laplacenetwork/key/internal/secp256k1"
+	"github.com/laplacenetwork/key/sign"
+	"github.com/stretchr/testify/require"
+)
+
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedData(t *testing.T) {
+	data, err := ParseTypedData([]byte(mailTypedData))
+
+	require.NoError(t, err)
+
+	encoded, err := data.Types.EncodeType("Mail")
+
+	require.NoError(t, err)
+
+	require.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", encoded)
+
+	typeHash, err := data.Types.TypeHash("Mail")
+
+	require.NoError(t, err)
+
+	require.Equal(t, "a0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2", hex.EncodeToString(typeHash))
+
+	domainSeparator, err := data.DomainSeparator()
+
+	require.NoError(t, err)
+
+	require.Equal(t, "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f", hex.EncodeToString(domainSeparator))
+
+	messageHash, err := data.Types.HashStruct("Mail", data.Message)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e", hex.EncodeToString(messageHash))
+
+	digest, err := data.Digest()
+
+	require.NoError(t, err)
+
+	require.Equal(t, "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", hex.EncodeToString(digest))
+
+	k := &keyImpl{provider: &providerIml{}}
+
+	k.SetBytes(keccak256([]byte("cow")))
+
+	require.Equal(t, "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", k.Address())
+
+	sig, err := k.SignTypedData(data)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c", hex.EncodeToString(sig))
+
+	signer, err := RecoverTypedData(data, sig)
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), signer)
+
+	delete(data.Message, "contents")
+
+	_, err = data.Digest()
+
+	require.Error(t, err)
+}
+
+func TestTypedDataArrays(t *testing.T) {
+	data, err := ParseTypedData([]byte(`{
+		"types": {
+			"Group": [
+				{"name": "name", "type": "string"},
+				{"name": "members", "type": "Person[]"},
+				{"name": "scores", "type": "int8[2]"},
+				{"name": "tag", "type": "bytes4"}
+			],
+			"Person": [
+				{"name": "name", "type": "string"},
+				{"name": "wallet", "type": "address"}
+			]
+		},
+		"primaryType": "Group",
+		"domain": {"name": "Ether Mail", "chainId": "0x1"},
+		"message": {
+			"name": "group",
+			"members": [{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"}],
+			"scores": [-128, 127],
+			"tag": "0x01020304"
+		}
+	}`))
+
+	require.NoError(t, err)
+
+	encoded, err := data.Types.EncodeType("Group")
+
+	require.NoError(t, err)
+
+	require.Equal(t, "Group(string name,Person[] members,int8[2] scores,bytes4 tag)Person(string name,address wallet)", encoded)
+
+	k := testKey()
+
+	sig, err := k.SignTypedData(data)
+
+	require.NoError(t, err)
+
+	signer, err := RecoverTypedData(data, sig)
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), signer)
+
+	data.Message["scores"] = []interface{}{-129, 0}
+
+	_, err = data.Digest()
+
+	require.Error(t, err)
+
+	data.Message["scores"] = []interface{}{0}
+
+	_, err = data.Digest()
+
+	require.Error(t, err)
+}
+
+func TestPersonalMessage(t *testing.T) {
+	require.Equal(t, "a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2", hex.EncodeToString(HashPersonalMessage([]byte("Hello World"))))
+
+	k := testKey()
+
+	sig, err := k.SignPersonalMessage([]byte("hello"))
+
+	require.NoError(t, err)
+
+	signer, err := RecoverPersonalMessage([]byte("hello"), sig)
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), signer)
+
+	signer, err = RecoverPersonalMessage([]byte("hello!"), sig)
+
+	require.NoError(t, err)
+
+	require.NotEqual(t, k.Address(), signer)
+}
+
+func TestSignatureEncodings(t *testing.T) {
+	k := &keyImpl{provider: &providerIml{}}
+
+	k.SetBytes(bytes.Repeat([]byte{0x12, 0x34, 0x56, 0x78, 0x90}, 7)[:32])
+
+	sig, err := k.SignPersonalMessage([]byte("Hello World"))
+
+	require.NoError(t, err)
+
+	// eip-2098 example
+	require.Equal(t, "68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea520641b", hex.EncodeToString(sig))
+
+	signature, err := sign.DecodeRecoverable(sig)
+
+	require.NoError(t, err)
+
+	compact, err := sign.EncodeCompact(signature)
+
+	require.NoError(t, err)
+
+	der, err := sign.EncodeDER(signature)
+
+	require.NoError(t, err)
+
+	hash := HashPersonalMessage([]byte("Hello World"))
+
+	for _, encoded := range [][]byte{sig, compact, der} {
+		require.True(t, k.Provider().Verify(k.PubKey(), encoded, hash))
+
+		require.True(t, k.PublicKey().Verify(encoded, hash))
+	}
+
+	pubkey, err := k.Provider().(*providerIml).Recover(compact, hash)
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.PubKey(), pubkey)
+
+	_, err = k.Provider().(*providerIml).Recover(der, hash)
+
+	require.Error(t, err)
+}
+
+func TestMalleableSignature(t *testing.T) {
+	k := testKey()
+
+	hash := HashPersonalMessage([]byte("hello"))
+
+	sig, err := k.Sign(hash)
+
+	require.NoError(t, err)
+
+	signature, err := sign.DecodeRecoverable(sig)
+
+	require.NoError(t, err)
+
+	address, err := RecoverAddress(hash, sig)
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), address)
+
+	// the raw recovery id is taken, the compressed flag is not
+	raw := append(append([]byte(nil), sig[:64]...), sig[64]-27)
+
+	address, err = RecoverAddress(hash, raw)
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), address)
+
+	raw[64] += 31
+
+	_, err = RecoverAddress(hash, raw)
+
+	require.Error(t, err)
+
+	n := secp256k1.SECP256K1().Params().N
+
+	malleated, err := sign.EncodeRecoverable(&sign.Signature{
+		R: signature.R,
+		S: new(big.Int).Sub(n, signature.S),
+		V: big.NewInt(27 + 28 - signature.V.Int64()),
+	}, 32, sign.VLegacy)
+
+	require.NoError(t, err)
+
+	require.False(t, k.Provider().Verify(k.PubKey(), malleated, hash))
+	require.False(t, k.PublicKey().Verify(malleated, hash))
+
+	_, err = RecoverAddress(hash, malleated)
+
+	require.Error(t, err)
+
+	normalized, err := NormalizeSignature(malleated)
+
+	require.NoError(t, err)
+
+	require.Equal(t, sig, normalized)
+
+	normalized, err = NormalizeSignature(sig)
+
+	require.NoError(t, err)
+
+	require.Equal(t, sig, normalized)
+}
@@ -0,0 +1,39 @@
+// Package near near ed25519 key provider, the address is implicit account id
+package near
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/internal/ed25519x"
+)
+
+// named account id rules https://nomicon.io/DataStructures/Account
+var accountIDRegexp = regexp.MustCompile(`^(([a-z\d]+[\-_])*[a-z\d]+\.)*([a-z\d]+[\-_])*[a-z\d]+$`)
+
+type addresserImpl struct {
+}
+
+func (addresser *addresserImpl) Name() string {
+	return "near"
+}
+
+// PublicKeyToAddress implicit account id, the lowercase hex of public key
+func (addresser *addresserImpl) PublicKeyToAddress(pub ed25519.PublicKey) string {
+	return hex.EncodeToString(pub)
+}
+
+// ValidAddress check implicit or named account id
+func (addresser *addresserImpl) ValidAddress(address string) bool {
+	if len(address) < 2 || len(address) > 64 {
+		return false
+	}
+
+	return accountIDRegexp.MatchString(address)
+}
+
+func init() {
+	key.RegisterProvider(ed25519x.NewProvider(&addresserImpl{}))
+}
@@ -0,0 +1,355 @@
+// Package p256 nist p-256 (secp256r1) key provider, the address is did:key identifier
+package p256
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/internal/ecdsax"
+	"github.com/laplacenetwork/key/sign"
+	"github.com/laplacenetwork/key/sign/recoverable"
+)
+
+const (
+	didKeyPrefix = "did:key:z"
+	size         = 32
+)
+
+// p256-pub multicodec varint
+var multicodec = []byte{0x80, 0x24}
+
+// Key p-256 key
+type Key interface {
+	key.Key
+	SignDER(hashed []byte) ([]byte, error)         // sign and encode signature as asn.1 der
+	SignRecoverable(hashed []byte) ([]byte, error) // sign and encode signature as r || s || v
+}
+
+// ParsePublicKey parse 33 bytes compressed, 65 bytes uncompressed or pkix der encoded public key
+func ParsePublicKey(pubkey []byte) (*ecdsa.PublicKey, error) {
+	curve := elliptic.P256()
+
+	var x, y *big.Int
+
+	switch len(pubkey) {
+	case 33:
+		x, y = elliptic.UnmarshalCompressed(curve, pubkey)
+	case 65:
+		x, y = elliptic.Unmarshal(curve, pubkey)
+	default:
+		publicKey, err := x509.ParsePKIXPublicKey(pubkey)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(key.ErrPublicKey, "parse pkix public key error: %s", err)
+		}
+
+		ecdsaKey, ok := publicKey.(*ecdsa.PublicKey)
+
+		if !ok || ecdsaKey.Curve != curve {
+			return nil, xerrors.Wrapf(key.ErrPublicKey, "pkix public key is not p-256")
+		}
+
+		return ecdsaKey, nil
+	}
+
+	if x == nil {
+		return nil, xerrors.Wrapf(key.ErrPublicKey, "decode p-256 point error")
+	}
+
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func pubKeyToAddress(pub *ecdsa.PublicKey) string {
+	compressed := elliptic.MarshalCompressed(pub.Curve, pub.X, pub.Y)
+
+	return didKeyPrefix + base58.Encode(append(append([]byte(nil), multicodec...), compressed...))
+}
+
+// decodeSignature decode 64 bytes raw, 65 bytes recoverable or strict der signature
+func decodeSignature(sig []byte) (*sign.Signature, error) {
+	switch len(sig) {
+	case 2 * size:
+		return sign.DecodeRaw(sig)
+	case 2*size + 1:
+		return sign.DecodeRecoverable(sig)
+	default:
+		return sign.DecodeDER(sig)
+	}
+}
+
+func verify(pub *ecdsa.PublicKey, sig []byte, hash []byte) bool {
+	if sign.CheckHash(pub.Curve, hash) != nil {
+		return false
+	}
+
+	signature, err := decodeSignature(sig)
+
+	if err != nil {
+		return false
+	}
+
+	return signature.Verfiy(pub, hash)
+}
+
+// VerifyWebAuthn verify webauthn assertion signature over authenticatorData || sha256(clientDataJSON)
+func VerifyWebAuthn(pubkey []byte, authenticatorData []byte, clientDataJSON []byte, sig []byte) bool {
+	publicKey, err := ParsePublicKey(pubkey)
+
+	if err != nil {
+		return false
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+
+	hasher := sha256.New()
+
+	hasher.Write(authenticatorData)
+	hasher.Write(clientDataHash[:])
+
+	return verify(publicKey, sig, hasher.Sum(nil))
+}
+
+type keyImpl struct {
+	provider key.Provider
+	key      *ecdsa.PrivateKey
+	address  string // address
+}
+
+func (key *keyImpl) Address() string {
+	return key.address
+}
+
+func (key *keyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *keyImpl) PriKey() []byte {
+	return ecdsax.PrivateKeyBytes(key.key)
+}
+
+func (key *keyImpl) PubKey() []byte {
+	return ecdsax.PublicKeyBytes(&key.key.PublicKey)
+}
+
+// CryptoPrivateKey the standard library private key
+func (key *keyImpl) CryptoPrivateKey() crypto.PrivateKey {
+	return key.key
+}
+
+func (key *keyImpl) PublicKey() key.PublicKey {
+	return &publicKeyImpl{
+		provider: key.provider,
+		key:      &key.key.PublicKey,
+		address:  key.address,
+	}
+}
+
+func (key *keyImpl) SetBytes(priKey []byte) {
+	key.key = ecdsax.BytesToPrivateKey(priKey, elliptic.P256())
+
+	key.address = pubKeyToAddress(&key.key.PublicKey)
+}
+
+// Sign sign and encode signature as raw r || s
+func (key *keyImpl) Sign(hashed []byte) ([]byte, error) {
+	if err := sign.CheckHash(key.key.Curve, hashed); err != nil {
+		return nil, err
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, key.key, hashed)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "ecdsa sign error")
+	}
+
+	return sign.EncodeRaw(&sign.Signature{R: r, S: s}, size)
+}
+
+func (key *keyImpl) SignDER(hashed []byte) ([]byte, error) {
+	if err := sign.CheckHash(key.key.Curve, hashed); err != nil {
+		return nil, err
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, key.key, hashed)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "ecdsa sign error")
+	}
+
+	return sign.EncodeDER(&sign.Signature{R: r, S: s})
+}
+
+func (key *keyImpl) SignRecoverable(hashed []byte) ([]byte, error) {
+	if err := sign.CheckHash(key.key.Curve, hashed); err != nil {
+		return nil, err
+	}
+
+	sig, err := recoverable.Sign(key.key, hashed, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sign.EncodeRecoverable(sig, size, sign.VLegacy)
+}
+
+type publicKeyImpl struct {
+	provider key.Provider
+	key      *ecdsa.PublicKey
+	address  string // address
+}
+
+func (key *publicKeyImpl) Address() string {
+	return key.address
+}
+
+func (key *publicKeyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *publicKeyImpl) Bytes() []byte {
+	return ecdsax.PublicKeyBytes(key.key)
+}
+
+func (key *publicKeyImpl) Compressed() []byte {
+	return elliptic.MarshalCompressed(key.key.Curve, key.key.X, key.key.Y)
+}
+
+func (key *publicKeyImpl) Uncompressed() []byte {
+	return ecdsax.PublicKeyBytes(key.key)
+}
+
+// CryptoPublicKey the standard library public key
+func (key *publicKeyImpl) CryptoPublicKey() crypto.PublicKey {
+	return key.key
+}
+
+func (key *publicKeyImpl) Verify(sig []byte, hash []byte) bool {
+	return verify(key.key, sig, hash)
+}
+
+type providerIml struct {
+}
+
+func (provider *providerIml) Name() string {
+	return "p256"
+}
+
+// HashMessage sha256 message hash, as used by es256 and webauthn
+func (provider *providerIml) HashMessage(message []byte) []byte {
+	hash := sha256.Sum256(message)
+
+	return hash[:]
+}
+
+func (provider *providerIml) New() (key.Key, error) {
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "ecdsa GenerateKey(P256) error")
+	}
+
+	return &keyImpl{
+		provider: provider,
+		key:      privateKey,
+		address:  pubKeyToAddress(&privateKey.PublicKey),
+	}, nil
+}
+
+func (provider *providerIml) PublicKey(pubkey []byte) (key.PublicKey, error) {
+	publicKey, err := ParsePublicKey(pubkey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &publicKeyImpl{
+		provider: provider,
+		key:      publicKey,
+		address:  pubKeyToAddress(publicKey),
+	}, nil
+}
+
+// Verify verify raw r || s, recoverable r || s || v or der signature,
+// the pubkey can be nil only for recoverable signature
+func (provider *providerIml) Verify(pubkey []byte, sig []byte, hash []byte) bool {
+	if pubkey == nil {
+		recovered, err := provider.Recover(sig, hash)
+
+		if err != nil {
+			return false
+		}
+
+		pubkey = recovered
+	}
+
+	publicKey, err := ParsePublicKey(pubkey)
+
+	if err != nil {
+		return false
+	}
+
+	return verify(publicKey, sig, hash)
+}
+
+func (provider *providerIml) PublicKeyToAddress(pubkey []byte) (string, error) {
+	publicKey, err := ParsePublicKey(pubkey)
+
+	if err != nil {
+		return "", err
+	}
+
+	return pubKeyToAddress(publicKey), nil
+}
+
+// Recover recover public key from recoverable r || s || v signature
+func (provider *providerIml) Recover(sig []byte, hash []byte) ([]byte, error) {
+	if len(sig) != 2*size+1 {
+		return nil, xerrors.Wrapf(key.ErrPublicKey, "recoverable signature length %d error", len(sig))
+	}
+
+	signature, err := sign.DecodeRecoverable(sig)
+
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, _, err := recoverable.Recover(elliptic.P256(), signature, hash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ecdsax.PublicKeyBytes(publicKey), nil
+}
+
+func (provider *providerIml) ValidAddress(address string) bool {
+	if !strings.HasPrefix(address, didKeyPrefix) {
+		return false
+	}
+
+	buff := base58.Decode(strings.TrimPrefix(address, didKeyPrefix))
+
+	if len(buff) != len(multicodec)+33 || buff[0] != multicodec[0] || buff[1] != multicodec[1] {
+		return false
+	}
+
+	_, err := ParsePublicKey(buff[len(multicodec):])
+
+	return err == nil
+}
+
+func init() {
+	key.RegisterProvider(&providerIml{})
+}
@@ -1,6 +1,14 @@
 package providers
 
 import (
-	_ "github.com/laplacenetwork/key/provider/did" //
-	_ "github.com/laplacenetwork/key/provider/eth" //
+	_ "github.com/laplacenetwork/key/provider/aptos"   //
+	_ "github.com/laplacenetwork/key/provider/bls"     //
+	_ "github.com/laplacenetwork/key/provider/btc"     //
+	_ "github.com/laplacenetwork/key/provider/did"     //
+	_ "github.com/laplacenetwork/key/provider/eth"     //
+	_ "github.com/laplacenetwork/key/provider/near"    //
+	_ "github.com/laplacenetwork/key/provider/p256"    //
+	_ "github.com/laplacenetwork/key/provider/solana"  //
+	_ "github.com/laplacenetwork/key/provider/stellar" //
+	_ "github.com/laplacenetwork/key/provider/taproot" //
 )
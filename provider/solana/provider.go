@@ -0,0 +1,31 @@
+// Package solana solana ed25519 key provider, the address is base58 encoded public key
+package solana
+
+import (
+	"crypto/ed25519"
+
+	"github.com/btcsuite/btcutil/base58"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/internal/ed25519x"
+)
+
+type addresserImpl struct {
+}
+
+func (addresser *addresserImpl) Name() string {
+	return "solana"
+}
+
+func (addresser *addresserImpl) PublicKeyToAddress(pub ed25519.PublicKey) string {
+	return base58.Encode(pub)
+}
+
+// ValidAddress program derived addresses are off curve, so only the length is checked
+func (addresser *addresserImpl) ValidAddress(address string) bool {
+	return len(base58.Decode(address)) == ed25519.PublicKeySize
+}
+
+func init() {
+	key.RegisterProvider(ed25519x.NewProvider(&addresserImpl{}))
+}
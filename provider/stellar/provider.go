@@ -0,0 +1,77 @@
+// Package stellar stellar ed25519 key provider, the address is strkey G... account id
+package stellar
+
+import (
+	"crypto/ed25519"
+	"encoding/base32"
+	"encoding/binary"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/internal/ed25519x"
+)
+
+// strkey account id version byte, encoded as 'G'
+const accountIDVersion = byte(6 << 3)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// crc16 xmodem checksum
+func crc16(data []byte) uint16 {
+	crc := uint16(0)
+
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+type addresserImpl struct {
+}
+
+func (addresser *addresserImpl) Name() string {
+	return "stellar"
+}
+
+func (addresser *addresserImpl) PublicKeyToAddress(pub ed25519.PublicKey) string {
+	buff := append([]byte{accountIDVersion}, pub...)
+
+	checksum := make([]byte, 2)
+
+	binary.LittleEndian.PutUint16(checksum, crc16(buff))
+
+	return encoding.EncodeToString(append(buff, checksum...))
+}
+
+func (addresser *addresserImpl) ValidAddress(address string) bool {
+	buff, err := encoding.DecodeString(address)
+
+	if err != nil || len(buff) != ed25519.PublicKeySize+3 {
+		return false
+	}
+
+	if buff[0] != accountIDVersion {
+		return false
+	}
+
+	payload := buff[:len(buff)-2]
+
+	if binary.LittleEndian.Uint16(buff[len(buff)-2:]) != crc16(payload) {
+		return false
+	}
+
+	// reject non canonical encoding
+	return encoding.EncodeToString(buff) == address
+}
+
+func init() {
+	key.RegisterProvider(ed25519x.NewProvider(&addresserImpl{}))
+}
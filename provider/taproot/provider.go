@@ -0,0 +1,251 @@
+// Package taproot bip341 key path only p2tr address provider with bip340 schnorr signature
+package taproot
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/internal/bech32"
+	"github.com/laplacenetwork/key/internal/ecdsax"
+	"github.com/laplacenetwork/key/internal/secp256k1"
+	"github.com/laplacenetwork/key/provider/btc"
+	"github.com/laplacenetwork/key/sign/schnorr"
+)
+
+// Key taproot key
+type Key interface {
+	key.Key
+	OutputKey() []byte // bip341 x-only output key
+}
+
+func outputKey(pub *ecdsa.PublicKey) []byte {
+	output, _, err := schnorr.TweakPublicKey(schnorr.XOnly(pub), nil)
+
+	if err != nil {
+		return nil
+	}
+
+	return output
+}
+
+func pubKeyToAddress(params *btc.Params, pub *ecdsa.PublicKey) string {
+	address, _ := bech32.EncodeSegwit(params.Bech32HRP, 1, outputKey(pub))
+
+	return address
+}
+
+func parsePubKey(pubkey []byte) (*ecdsa.PublicKey, error) {
+	if len(pubkey) == schnorr.PublicKeySize {
+		return schnorr.LiftX(pubkey)
+	}
+
+	return secp256k1.ParsePubkey(pubkey)
+}
+
+type keyImpl struct {
+	provider *providerIml
+	key      *ecdsa.PrivateKey
+	address  string // address
+}
+
+func (key *keyImpl) Address() string {
+	return key.address
+}
+
+func (key *keyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *keyImpl) PriKey() []byte {
+	return ecdsax.PrivateKeyBytes(key.key)
+}
+
+func (key *keyImpl) PubKey() []byte {
+	return schnorr.XOnly(&key.key.PublicKey)
+}
+
+func (key *keyImpl) OutputKey() []byte {
+	return outputKey(&key.key.PublicKey)
+}
+
+func (key *keyImpl) PublicKey() key.PublicKey {
+	return &publicKeyImpl{
+		provider: key.provider,
+		key:      &key.key.PublicKey,
+		address:  key.address,
+	}
+}
+
+func (key *keyImpl) SetBytes(priKey []byte) {
+	key.key = ecdsax.BytesToPrivateKey(priKey, secp256k1.SECP256K1())
+
+	key.address = pubKeyToAddress(key.provider.params, &key.key.PublicKey)
+}
+
+func (key *keyImpl) Sign(hashed []byte) ([]byte, error) {
+	tweaked, err := schnorr.TweakPrivateKey(key.key, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schnorr.Sign(tweaked, hashed, nil)
+}
+
+type publicKeyImpl struct {
+	provider *providerIml
+	key      *ecdsa.PublicKey
+	address  string // address
+}
+
+func (key *publicKeyImpl) Address() string {
+	return key.address
+}
+
+func (key *publicKeyImpl) Provider() key.Provider {
+	return key.provider
+}
+
+func (key *publicKeyImpl) Bytes() []byte {
+	return schnorr.XOnly(key.key)
+}
+
+func (key *publicKeyImpl) Compressed() []byte {
+	return secp256k1.CompressPubkey(key.key.X, key.key.Y)
+}
+
+func (key *publicKeyImpl) Uncompressed() []byte {
+	return ecdsax.PublicKeyBytes(key.key)
+}
+
+func (key *publicKeyImpl) Verify(sig []byte, hash []byte) bool {
+	return schnorr.Verify(outputKey(key.key), hash, sig)
+}
+
+type providerIml struct {
+	params *btc.Params
+	name   string
+}
+
+func (provider *providerIml) Name() string {
+	return provider.name
+}
+
+// HashMessage bip322 tagged message hash
+func (provider *providerIml) HashMessage(message []byte) []byte {
+	return schnorr.TaggedHash("BIP0322-signed-message", message)
+}
+
+func (provider *providerIml) CoinType() uint32 {
+	return provider.params.CoinType
+}
+
+func (provider *providerIml) DefaultPath(index uint32) string {
+	return fmt.Sprintf("m/86'/%d'/0'/0/%d", provider.params.CoinType, index)
+}
+
+func (provider *providerIml) New() (key.Key, error) {
+
+	privateKey, err := ecdsa.GenerateKey(secp256k1.SECP256K1(), rand.Reader)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "ecdsa GenerateKey(SECP256K1) error")
+	}
+
+	return &keyImpl{
+		provider: provider,
+		key:      privateKey,
+		address:  pubKeyToAddress(provider.params, &privateKey.PublicKey),
+	}, nil
+}
+
+func (provider *providerIml) PublicKey(pubkey []byte) (key.PublicKey, error) {
+	publicKey, err := parsePubKey(pubkey)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(key.ErrPublicKey, "decode public key error: %s", err)
+	}
+
+	return &publicKeyImpl{
+		provider: provider,
+		key:      publicKey,
+		address:  pubKeyToAddress(provider.params, publicKey),
+	}, nil
+}
+
+// Verify verify the schnorr signature with the internal public key, the pubkey is required
+func (provider *providerIml) Verify(pubkey []byte, sig []byte, hash []byte) bool {
+	publicKey, err := parsePubKey(pubkey)
+
+	if err != nil {
+		return false
+	}
+
+	return schnorr.Verify(outputKey(publicKey), hash, sig)
+}
+
+// BatchVerify bip340 batch verification of the signatures, fall back to verify one by one
+// to find the invalid ones if the batch fails
+func (provider *providerIml) BatchVerify(items []*key.VerifyItem) []bool {
+	results := make([]bool, len(items))
+
+	var indexes []int
+	var xonlys, msgs, sigs [][]byte
+
+	for i, item := range items {
+		publicKey, err := parsePubKey(item.PubKey)
+
+		if err != nil {
+			continue
+		}
+
+		indexes = append(indexes, i)
+		xonlys = append(xonlys, outputKey(publicKey))
+		msgs = append(msgs, item.Hash)
+		sigs = append(sigs, item.Sig)
+	}
+
+	if schnorr.BatchVerify(xonlys, msgs, sigs) {
+		for _, i := range indexes {
+			results[i] = true
+		}
+
+		return results
+	}
+
+	for j, i := range indexes {
+		results[i] = schnorr.Verify(xonlys[j], msgs[j], sigs[j])
+	}
+
+	return results
+}
+
+func (provider *providerIml) PublicKeyToAddress(pubkey []byte) (string, error) {
+	publicKey, err := parsePubKey(pubkey)
+
+	if err != nil {
+		return "", xerrors.Wrapf(key.ErrPublicKey, "decode public key error: %s", err)
+	}
+
+	return pubKeyToAddress(provider.params, publicKey), nil
+}
+
+func (provider *providerIml) ValidAddress(address string) bool {
+	version, program, err := bech32.DecodeSegwit(provider.params.Bech32HRP, address)
+
+	if err != nil {
+		return false
+	}
+
+	return version == 1 && len(program) == 32
+}
+
+func init() {
+	key.RegisterProvider(&providerIml{params: btc.MainNet, name: "taproot"})
+	key.RegisterProvider(&providerIml{params: btc.TestNet, name: "taproot.testnet"})
+	key.RegisterProvider(&providerIml{params: btc.RegTest, name: "taproot.regtest"})
+}
@@ -0,0 +1,170 @@
+// Package shamir shamir secret sharing over GF(256), each byte of the secret is shared
+// with its own random polynomial
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+)
+
+// Errors
+var (
+	ErrThreshold = errors.New("invalid threshold")
+	ErrShares    = errors.New("invalid shares")
+)
+
+// MaxShares the x coordinate is a non-zero field element
+const MaxShares = 255
+
+// Share one share of the secret, Y[i] is the value of the polynomial of secret byte i at X
+type Share struct {
+	X byte
+	Y []byte
+}
+
+// mul multiplication in GF(2^8) with the rijndael polynomial x^8 + x^4 + x^3 + x + 1, constant time
+func mul(a, b byte) byte {
+	var r byte
+
+	for i := 0; i < 8; i++ {
+		r ^= a & -(b & 1)
+		b >>= 1
+		a = (a << 1) ^ (0x1b & -(a >> 7))
+	}
+
+	return r
+}
+
+// inverse a^254 = a^-1, constant time
+func inverse(a byte) byte {
+	r := byte(1)
+
+	for i := 0; i < 7; i++ {
+		a = mul(a, a)
+		r = mul(r, a)
+	}
+
+	return r
+}
+
+// Split split secret into count shares with x = 1...count, any threshold of them restore the secret
+func Split(secret []byte, threshold, count int) ([]*Share, error) {
+	if len(secret) == 0 {
+		return nil, xerrors.Wrapf(ErrShares, "empty secret")
+	}
+
+	if threshold < 1 || threshold > count || count > MaxShares {
+		return nil, xerrors.Wrapf(ErrThreshold, "threshold %d of %d shares", threshold, count)
+	}
+
+	// coefficients[i] the coefficients of degree 1...threshold-1 of the polynomial of secret byte i
+	coefficients := make([]byte, len(secret)*(threshold-1))
+
+	if _, err := io.ReadFull(rand.Reader, coefficients); err != nil {
+		return nil, xerrors.Wrapf(err, "read coefficients from crypto/rand error")
+	}
+
+	shares := make([]*Share, count)
+
+	for i := range shares {
+		x := byte(i + 1)
+
+		y := make([]byte, len(secret))
+
+		for j := range secret {
+			polynomial := coefficients[j*(threshold-1) : (j+1)*(threshold-1)]
+
+			// horner
+			var v byte
+
+			for k := len(polynomial) - 1; k >= 0; k-- {
+				v = mul(v, x) ^ polynomial[k]
+			}
+
+			y[j] = mul(v, x) ^ secret[j]
+		}
+
+		shares[i] = &Share{X: x, Y: y}
+	}
+
+	return shares, nil
+}
+
+// Interpolate evaluate at x the lagrange polynomial through the shares
+func Interpolate(shares []*Share, x byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, xerrors.Wrapf(ErrShares, "no shares")
+	}
+
+	length := len(shares[0].Y)
+
+	// basis[i] = prod (x - xj) / (xi - xj) for j != i, subtraction is xor in GF(2^8)
+	basis := make([]byte, len(shares))
+
+	for i, share := range shares {
+		if len(share.Y) != length {
+			return nil, xerrors.Wrapf(ErrShares, "share %d length %d, expect %d", share.X, len(share.Y), length)
+		}
+
+		numerator, denominator := byte(1), byte(1)
+
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+
+			if share.X == other.X {
+				return nil, xerrors.Wrapf(ErrShares, "duplicate share %d", share.X)
+			}
+
+			numerator = mul(numerator, x^other.X)
+			denominator = mul(denominator, share.X^other.X)
+		}
+
+		basis[i] = mul(numerator, inverse(denominator))
+	}
+
+	result := make([]byte, length)
+
+	for i, share := range shares {
+		for j, y := range share.Y {
+			result[j] ^= mul(basis[i], y)
+		}
+	}
+
+	return result, nil
+}
+
+// Combine restore the secret from at least threshold shares, fewer shares yield a wrong secret
+func Combine(shares []*Share) ([]byte, error) {
+	return Interpolate(shares, 0)
+}
+
+// SplitKey split the private key into count shares, any threshold of them restore the key
+func SplitKey(k key.Key, threshold, count int) ([]*Share, error) {
+	return Split(k.PriKey(), threshold, count)
+}
+
+// CombineKey restore the driver key from shares
+func CombineKey(driver string, shares []*Share) (key.Key, error) {
+	priKey, err := Combine(shares)
+
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := key.New(driver)
+
+	if err != nil {
+		return nil, err
+	}
+
+	k.SetBytes(priKey)
+
+	return k, nil
+}
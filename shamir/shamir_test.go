@@ -0,0 +1,66 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestField(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		require.Equal(t, byte(1), mul(byte(a), inverse(byte(a))))
+	}
+
+	// x * (x + 1) = x^2 + x
+	require.Equal(t, byte(0x06), mul(0x02, 0x03))
+
+	// fips 197 4.2 example
+	require.Equal(t, byte(0xc1), mul(0x57, 0x83))
+}
+
+func TestSplit(t *testing.T) {
+	secret := make([]byte, 32)
+
+	_, err := rand.Read(secret)
+
+	require.NoError(t, err)
+
+	shares, err := Split(secret, 3, 5)
+
+	require.NoError(t, err)
+
+	require.Len(t, shares, 5)
+
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var selected []*Share
+
+		for _, i := range subset {
+			selected = append(selected, shares[i])
+		}
+
+		restored, err := Combine(selected)
+
+		require.NoError(t, err)
+
+		require.Equal(t, secret, restored)
+	}
+
+	restored, err := Combine(shares[:2])
+
+	require.NoError(t, err)
+
+	require.NotEqual(t, secret, restored)
+
+	_, err = Combine([]*Share{shares[0], shares[0]})
+
+	require.Error(t, err)
+
+	_, err = Split(secret, 4, 3)
+
+	require.Error(t, err)
+
+	_, err = Split(secret, 0, 3)
+
+	require.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package slip39
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	baseIterations = 10000
+	rounds         = 4
+)
+
+// roundFunction pbkdf2-hmac-sha256 of passphrase with the round number, salted with the right half
+func roundFunction(i int, passphrase []byte, exponent int, salt []byte, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+
+	return pbkdf2.Key(password, append(append([]byte{}, salt...), r...), (baseIterations<<uint(exponent))/rounds, len(r), sha256.New)
+}
+
+func cipherSalt(id uint16, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+
+	return []byte{'s', 'h', 'a', 'm', 'i', 'r', byte(id >> 8), byte(id)}
+}
+
+// feistel four rounds feistel network, the rounds run backwards to decrypt
+func feistel(secret []byte, passphrase string, exponent int, id uint16, extendable bool, decrypt bool) []byte {
+	half := len(secret) / 2
+
+	l := append([]byte{}, secret[:half]...)
+	r := append([]byte{}, secret[half:]...)
+
+	salt := cipherSalt(id, extendable)
+
+	for j := 0; j < rounds; j++ {
+		i := j
+
+		if decrypt {
+			i = rounds - 1 - j
+		}
+
+		f := roundFunction(i, []byte(passphrase), exponent, salt, r)
+
+		for k := range l {
+			l[k] ^= f[k]
+		}
+
+		l, r = r, l
+	}
+
+	return append(r, l...)
+}
+
+func encrypt(masterSecret []byte, passphrase string, exponent int, id uint16, extendable bool) []byte {
+	return feistel(masterSecret, passphrase, exponent, id, extendable, false)
+}
+
+func decrypt(encrypted []byte, passphrase string, exponent int, id uint16, extendable bool) []byte {
+	return feistel(encrypted, passphrase, exponent, id, extendable, true)
+}
@@ -0,0 +1,216 @@
+package slip39
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/dynamicgo/xerrors"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	radixBits       = 10
+	idBits          = 15
+	checksumWords   = 3
+	metadataWords   = 7 // id, extendable flag and iteration exponent in 2 words, group and member in 2 words, checksum
+	minMnemonicSize = metadataWords + (minSecretBits+radixBits-1)/radixBits
+)
+
+var wordIndex = make(map[string]int, len(English))
+
+func init() {
+	if len(English) != 1<<radixBits {
+		panic("slip39 english wordlist length error")
+	}
+
+	for i, word := range English {
+		wordIndex[word] = i
+	}
+}
+
+// share one member share of a group share of the encrypted master secret
+type share struct {
+	id                uint16
+	extendable        bool
+	iterationExponent int
+	groupIndex        int
+	groupThreshold    int
+	groupCount        int
+	memberIndex       int
+	memberThreshold   int
+	value             []byte
+}
+
+var rs1024Generator = [10]uint32{
+	0xE0E040, 0x1C1C080, 0x3838100, 0x7070200, 0xE0E0009,
+	0x1C0C2412, 0x38086C24, 0x3090FC48, 0x21B1F890, 0x3F3F120,
+}
+
+// rs1024Polymod reed solomon code over GF(1024)
+func rs1024Polymod(values []int) uint32 {
+	chk := uint32(1)
+
+	for _, v := range values {
+		b := chk >> 20
+
+		chk = (chk&0xFFFFF)<<10 ^ uint32(v)
+
+		for i := uint(0); i < 10; i++ {
+			if (b>>i)&1 != 0 {
+				chk ^= rs1024Generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func customization(extendable bool) []int {
+	cs := "shamir"
+
+	if extendable {
+		cs = "shamir_extendable"
+	}
+
+	values := make([]int, len(cs))
+
+	for i := range cs {
+		values[i] = int(cs[i])
+	}
+
+	return values
+}
+
+func rs1024Checksum(extendable bool, data []int) []int {
+	values := append(customization(extendable), data...)
+
+	polymod := rs1024Polymod(append(values, make([]int, checksumWords)...)) ^ 1
+
+	checksum := make([]int, checksumWords)
+
+	for i := range checksum {
+		checksum[i] = int(polymod>>uint(radixBits*(checksumWords-1-i))) & (1<<radixBits - 1)
+	}
+
+	return checksum
+}
+
+func rs1024Verify(extendable bool, data []int) bool {
+	return rs1024Polymod(append(customization(extendable), data...)) == 1
+}
+
+// words split the big endian value into count words of 10 bits
+func words(value *big.Int, count int) []int {
+	result := make([]int, count)
+
+	mask := big.NewInt(1<<radixBits - 1)
+
+	v := new(big.Int).Set(value)
+
+	for i := count - 1; i >= 0; i-- {
+		result[i] = int(new(big.Int).And(v, mask).Int64())
+
+		v.Rsh(v, radixBits)
+	}
+
+	return result
+}
+
+func (s *share) mnemonic() string {
+	var extendable int64
+
+	if s.extendable {
+		extendable = 1
+	}
+
+	// id 15 bits, extendable 1 bit, iteration exponent 4 bits
+	prefix := int64(s.id)<<5 | extendable<<4 | int64(s.iterationExponent)
+
+	// group index, group threshold - 1, group count - 1, member index, member threshold - 1, 4 bits each
+	info := int64(s.groupIndex)<<16 | int64(s.groupThreshold-1)<<12 | int64(s.groupCount-1)<<8 |
+		int64(s.memberIndex)<<4 | int64(s.memberThreshold-1)
+
+	valueWords := (len(s.value)*8 + radixBits - 1) / radixBits
+
+	data := append(words(big.NewInt(prefix), 2), words(big.NewInt(info), 2)...)
+	data = append(data, words(new(big.Int).SetBytes(s.value), valueWords)...)
+	data = append(data, rs1024Checksum(s.extendable, data)...)
+
+	result := make([]string, len(data))
+
+	for i, index := range data {
+		result[i] = English[index]
+	}
+
+	return strings.Join(result, " ")
+}
+
+func parseShare(mnemonic string) (*share, error) {
+	fields := strings.Fields(norm.NFKD.String(strings.ToLower(mnemonic)))
+
+	if len(fields) < minMnemonicSize {
+		return nil, xerrors.Wrapf(ErrMnemonic, "words count %d less than %d", len(fields), minMnemonicSize)
+	}
+
+	data := make([]int, len(fields))
+
+	for i, word := range fields {
+		index, ok := wordIndex[word]
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrMnemonic, "unknown word %s", word)
+		}
+
+		data[i] = index
+	}
+
+	// the extendable flag is the lowest bit of the second word
+	extendable := data[1]>>4&1 == 1
+
+	if !rs1024Verify(extendable, data) {
+		return nil, xerrors.Wrapf(ErrChecksum, "mnemonic %s...", strings.Join(fields[:2], " "))
+	}
+
+	valueWords := len(data) - metadataWords
+
+	paddingBits := radixBits * valueWords % 16
+
+	if paddingBits > 8 {
+		return nil, xerrors.Wrapf(ErrMnemonic, "invalid mnemonic length %d", len(data))
+	}
+
+	prefix := data[0]<<10 | data[1]
+	info := data[2]<<10 | data[3]
+
+	s := &share{
+		id:                uint16(prefix >> 5),
+		extendable:        extendable,
+		iterationExponent: prefix & 0x0f,
+		groupIndex:        info >> 16,
+		groupThreshold:    info>>12&0x0f + 1,
+		groupCount:        info>>8&0x0f + 1,
+		memberIndex:       info >> 4 & 0x0f,
+		memberThreshold:   info&0x0f + 1,
+	}
+
+	if s.groupThreshold > s.groupCount {
+		return nil, xerrors.Wrapf(ErrMnemonic, "group threshold %d greater than group count %d", s.groupThreshold, s.groupCount)
+	}
+
+	value := new(big.Int)
+
+	for _, index := range data[4 : 4+valueWords] {
+		value.Lsh(value, radixBits)
+		value.Or(value, big.NewInt(int64(index)))
+	}
+
+	size := (radixBits*valueWords - paddingBits) / 8
+
+	if value.BitLen() > size*8 {
+		return nil, xerrors.Wrapf(ErrMnemonic, "invalid mnemonic padding")
+	}
+
+	s.value = value.FillBytes(make([]byte, size))
+
+	return s, nil
+}
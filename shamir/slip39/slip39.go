@@ -0,0 +1,335 @@
+// Package slip39 slip39 shamir's secret-sharing for mnemonic codes,
+// https://github.com/satoshilabs/slips/blob/master/slip-0039.md
+package slip39
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/shamir"
+)
+
+// Errors
+var (
+	ErrSecret     = errors.New("invalid master secret length")
+	ErrGroup      = errors.New("invalid group configuration")
+	ErrPassphrase = errors.New("passphrase must be printable ascii")
+	ErrMnemonic   = errors.New("invalid slip39 mnemonic")
+	ErrChecksum   = errors.New("slip39 mnemonic checksum mismatch")
+	ErrShares     = errors.New("mnemonics don't restore the secret")
+	ErrDigest     = errors.New("shared secret digest mismatch")
+)
+
+const (
+	minSecretBits = 128
+	maxShares     = 16
+	digestLength  = 4
+	digestIndex   = 254
+	secretIndex   = 255
+
+	// DefaultIterationExponent pbkdf2 runs 10000 * 2^e iterations in total
+	DefaultIterationExponent = 1
+)
+
+// Group member threshold of member count shares of one group
+type Group struct {
+	Threshold int
+	Count     int
+}
+
+func randomBytes(n int) ([]byte, error) {
+	buff := make([]byte, n)
+
+	if _, err := io.ReadFull(rand.Reader, buff); err != nil {
+		return nil, xerrors.Wrapf(err, "read random from crypto/rand error")
+	}
+
+	return buff, nil
+}
+
+func digest(randomPart []byte, secret []byte) []byte {
+	mac := hmac.New(sha256.New, randomPart)
+
+	mac.Write(secret)
+
+	return mac.Sum(nil)[:digestLength]
+}
+
+// splitSecret the shares x = 0...count-1 of polynomial through the random shares, the digest share
+// at 254 and the secret at 255
+func splitSecret(threshold, count int, secret []byte) ([]*shamir.Share, error) {
+	if threshold < 1 || threshold > count || count > maxShares {
+		return nil, xerrors.Wrapf(ErrGroup, "threshold %d of %d shares", threshold, count)
+	}
+
+	shares := make([]*shamir.Share, 0, count)
+
+	if threshold == 1 {
+		for i := 0; i < count; i++ {
+			shares = append(shares, &shamir.Share{X: byte(i), Y: secret})
+		}
+
+		return shares, nil
+	}
+
+	for i := 0; i < threshold-2; i++ {
+		y, err := randomBytes(len(secret))
+
+		if err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, &shamir.Share{X: byte(i), Y: y})
+	}
+
+	randomPart, err := randomBytes(len(secret) - digestLength)
+
+	if err != nil {
+		return nil, err
+	}
+
+	base := append(shares[:len(shares):len(shares)],
+		&shamir.Share{X: digestIndex, Y: append(digest(randomPart, secret), randomPart...)},
+		&shamir.Share{X: secretIndex, Y: secret})
+
+	for i := threshold - 2; i < count; i++ {
+		y, err := shamir.Interpolate(base, byte(i))
+
+		if err != nil {
+			return nil, err
+		}
+
+		shares = append(shares, &shamir.Share{X: byte(i), Y: y})
+	}
+
+	return shares, nil
+}
+
+func recoverSecret(threshold int, shares []*shamir.Share) ([]byte, error) {
+	if threshold == 1 {
+		return shares[0].Y, nil
+	}
+
+	secret, err := shamir.Interpolate(shares, secretIndex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	digestShare, err := shamir.Interpolate(shares, digestIndex)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !hmac.Equal(digestShare[:digestLength], digest(digestShare[digestLength:], secret)) {
+		return nil, ErrDigest
+	}
+
+	return secret, nil
+}
+
+func checkPassphrase(passphrase string) error {
+	for _, c := range []byte(passphrase) {
+		if c < 32 || c > 126 {
+			return ErrPassphrase
+		}
+	}
+
+	return nil
+}
+
+// Split split the master secret into groups of mnemonic shares, groupThreshold groups each with
+// its member threshold of mnemonics restore the secret, the shares are extendable
+func Split(masterSecret []byte, passphrase string, groupThreshold int, groups []Group) ([][]string, error) {
+	if len(masterSecret)*8 < minSecretBits || len(masterSecret)%2 != 0 {
+		return nil, xerrors.Wrapf(ErrSecret, "master secret length %d must be even and at least %d bytes", len(masterSecret), minSecretBits/8)
+	}
+
+	if err := checkPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	if groupThreshold < 1 || groupThreshold > len(groups) {
+		return nil, xerrors.Wrapf(ErrGroup, "group threshold %d of %d groups", groupThreshold, len(groups))
+	}
+
+	for _, group := range groups {
+		if group.Threshold == 1 && group.Count > 1 {
+			return nil, xerrors.Wrapf(ErrGroup, "member threshold 1 with %d members, use 1-of-1 instead", group.Count)
+		}
+	}
+
+	idBytes, err := randomBytes(2)
+
+	if err != nil {
+		return nil, err
+	}
+
+	id := binary.BigEndian.Uint16(idBytes) & (1<<idBits - 1)
+
+	encrypted := encrypt(masterSecret, passphrase, DefaultIterationExponent, id, true)
+
+	groupShares, err := splitSecret(groupThreshold, len(groups), encrypted)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]string, len(groups))
+
+	for i, groupShare := range groupShares {
+		memberShares, err := splitSecret(groups[i].Threshold, groups[i].Count, groupShare.Y)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, memberShare := range memberShares {
+			s := &share{
+				id:                id,
+				extendable:        true,
+				iterationExponent: DefaultIterationExponent,
+				groupIndex:        int(groupShare.X),
+				groupThreshold:    groupThreshold,
+				groupCount:        len(groups),
+				memberIndex:       int(memberShare.X),
+				memberThreshold:   groups[i].Threshold,
+				value:             memberShare.Y,
+			}
+
+			result[i] = append(result[i], s.mnemonic())
+		}
+	}
+
+	return result, nil
+}
+
+// Combine restore the master secret from exactly group threshold groups each with exactly its member
+// threshold of mnemonics
+func Combine(mnemonics []string, passphrase string) ([]byte, error) {
+	if len(mnemonics) == 0 {
+		return nil, xerrors.Wrapf(ErrShares, "no mnemonics")
+	}
+
+	if err := checkPassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	var first *share
+
+	groups := make(map[int][]*share)
+	var order []int
+
+	for _, mnemonic := range mnemonics {
+		s, err := parseShare(mnemonic)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if first == nil {
+			first = s
+		}
+
+		if s.id != first.id || s.extendable != first.extendable || s.iterationExponent != first.iterationExponent ||
+			s.groupThreshold != first.groupThreshold || s.groupCount != first.groupCount || len(s.value) != len(first.value) {
+			return nil, xerrors.Wrapf(ErrShares, "mnemonics belong to different secrets")
+		}
+
+		group, ok := groups[s.groupIndex]
+
+		if !ok {
+			order = append(order, s.groupIndex)
+		}
+
+		for _, member := range group {
+			if member.memberThreshold != s.memberThreshold {
+				return nil, xerrors.Wrapf(ErrShares, "group %d member thresholds mismatch", s.groupIndex)
+			}
+
+			if member.memberIndex == s.memberIndex {
+				if !bytes.Equal(member.value, s.value) {
+					return nil, xerrors.Wrapf(ErrShares, "group %d member %d conflicting shares", s.groupIndex, s.memberIndex)
+				}
+
+				s = nil
+
+				break
+			}
+		}
+
+		if s != nil {
+			groups[s.groupIndex] = append(group, s)
+		}
+	}
+
+	if len(groups) != first.groupThreshold {
+		return nil, xerrors.Wrapf(ErrShares, "%d groups, group threshold %d", len(groups), first.groupThreshold)
+	}
+
+	groupShares := make([]*shamir.Share, 0, len(groups))
+
+	for _, groupIndex := range order {
+		members := groups[groupIndex]
+
+		if len(members) != members[0].memberThreshold {
+			return nil, xerrors.Wrapf(ErrShares, "group %d has %d mnemonics, member threshold %d",
+				groupIndex, len(members), members[0].memberThreshold)
+		}
+
+		memberShares := make([]*shamir.Share, len(members))
+
+		for i, member := range members {
+			memberShares[i] = &shamir.Share{X: byte(member.memberIndex), Y: member.value}
+		}
+
+		groupSecret, err := recoverSecret(len(members), memberShares)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "group %d", groupIndex)
+		}
+
+		groupShares = append(groupShares, &shamir.Share{X: byte(groupIndex), Y: groupSecret})
+	}
+
+	encrypted, err := recoverSecret(first.groupThreshold, groupShares)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(encrypted, passphrase, first.iterationExponent, first.id, first.extendable), nil
+}
+
+// SplitKey split the private key into groups of mnemonic shares
+func SplitKey(k key.Key, passphrase string, groupThreshold int, groups []Group) ([][]string, error) {
+	return Split(k.PriKey(), passphrase, groupThreshold, groups)
+}
+
+// CombineKey restore the driver key from mnemonic shares
+func CombineKey(driver string, mnemonics []string, passphrase string) (key.Key, error) {
+	priKey, err := Combine(mnemonics, passphrase)
+
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := key.New(driver)
+
+	if err != nil {
+		return nil, err
+	}
+
+	k.SetBytes(priKey)
+
+	return k, nil
+}
@@ -0,0 +1,107 @@
+package slip39
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// official slip39 test vectors, passphrase TREZOR
+var testVectors = []struct {
+	mnemonics []string
+	secret    string
+}{
+	{
+		[]string{
+			"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard",
+		},
+		"bb54aac4b89dc868ba37d9cc21b2cece",
+	},
+	{
+		[]string{
+			"duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision kidney",
+		},
+		"",
+	},
+	{
+		[]string{
+			"shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed",
+			"shadow pistol academic acid actress prayer class unknown daughter sweater depict flip twice unkind craft early superior advocate guest smoking",
+		},
+		"b43ceb7e57a0ea8766221624d01b0864",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for _, vector := range testVectors {
+		secret, err := Combine(vector.mnemonics, "TREZOR")
+
+		if vector.secret == "" {
+			require.Error(t, err)
+			continue
+		}
+
+		require.NoError(t, err)
+
+		require.Equal(t, vector.secret, hex.EncodeToString(secret))
+	}
+
+	_, err := Combine(testVectors[2].mnemonics[:1], "TREZOR")
+
+	require.Error(t, err)
+}
+
+func TestSplit(t *testing.T) {
+	secret := make([]byte, 32)
+
+	_, err := rand.Read(secret)
+
+	require.NoError(t, err)
+
+	groups, err := Split(secret, "test", 2, []Group{{1, 1}, {2, 3}, {3, 5}})
+
+	require.NoError(t, err)
+
+	require.Len(t, groups, 3)
+
+	require.Len(t, groups[0], 1)
+	require.Len(t, groups[1], 3)
+	require.Len(t, groups[2], 5)
+
+	for _, mnemonics := range [][]string{
+		{groups[0][0], groups[1][0], groups[1][2]},
+		{groups[2][4], groups[1][1], groups[2][0], groups[1][0], groups[2][2]},
+	} {
+		restored, err := Combine(mnemonics, "test")
+
+		require.NoError(t, err)
+
+		require.Equal(t, secret, restored)
+
+		restored, err = Combine(mnemonics, "")
+
+		require.NoError(t, err)
+
+		require.NotEqual(t, secret, restored)
+	}
+
+	// not enough groups or members
+	for _, mnemonics := range [][]string{
+		{groups[1][0], groups[1][2]},
+		{groups[0][0], groups[2][0], groups[2][1]},
+	} {
+		_, err := Combine(mnemonics, "test")
+
+		require.Error(t, err)
+	}
+
+	_, err = Split(secret, "", 1, []Group{{1, 3}})
+
+	require.Error(t, err)
+
+	_, err = Split(secret[:15], "", 1, []Group{{1, 1}})
+
+	require.Error(t, err)
+}
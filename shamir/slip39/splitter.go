@@ -0,0 +1,89 @@
+package slip39
+
+import (
+	"io"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+)
+
+// splitterImpl write each mnemonic share of a single group, attrs threshold is the number of shares
+// required, all writers by default, attrs passphrase protects the key
+type splitterImpl struct {
+}
+
+func (splitter *splitterImpl) Split(k key.Key, attrs map[string]string, writers []io.Writer) error {
+	threshold := len(writers)
+
+	if value, ok := attrs["threshold"]; ok {
+		var err error
+
+		threshold, err = strconv.Atoi(value)
+
+		if err != nil {
+			return xerrors.Wrapf(ErrGroup, "threshold %s", value)
+		}
+	}
+
+	groupThreshold, groups := 1, []Group{{Threshold: threshold, Count: len(writers)}}
+
+	// slip39 forbids 1-of-n members, any single share of 1-of-1 groups restores the key instead
+	if threshold == 1 {
+		groups = make([]Group, len(writers))
+
+		for i := range groups {
+			groups[i] = Group{Threshold: 1, Count: 1}
+		}
+	}
+
+	mnemonics, err := SplitKey(k, attrs["passphrase"], groupThreshold, groups)
+
+	if err != nil {
+		return err
+	}
+
+	var i int
+
+	for _, group := range mnemonics {
+		for _, mnemonic := range group {
+			if _, err := io.WriteString(writers[i], mnemonic); err != nil {
+				return xerrors.Wrapf(err, "write mnemonic %d error", i)
+			}
+
+			i++
+		}
+	}
+
+	return nil
+}
+
+func (splitter *splitterImpl) Combine(k key.Key, attrs map[string]string, readers []io.Reader) error {
+	mnemonics := make([]string, len(readers))
+
+	for i, reader := range readers {
+		data, err := ioutil.ReadAll(reader)
+
+		if err != nil {
+			return xerrors.Wrapf(err, "read all data from reader err")
+		}
+
+		mnemonics[i] = string(data)
+	}
+
+	priKey, err := Combine(mnemonics, attrs["passphrase"])
+
+	if err != nil {
+		return err
+	}
+
+	k.SetBytes(priKey)
+
+	return nil
+}
+
+func init() {
+	key.RegisterSplitter("slip39", &splitterImpl{})
+}
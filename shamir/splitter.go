@@ -0,0 +1,133 @@
+package shamir
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strconv"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+)
+
+type shareJSON struct {
+	PublicKey string `json:"pubkey"`    // uncompressed public key to check the restored key
+	Threshold int    `json:"threshold"` // shares required to restore the key
+	X         byte   `json:"x"`
+	Y         string `json:"y"`
+}
+
+// splitterImpl write each share as json, attrs threshold is the number of shares required,
+// all writers by default
+type splitterImpl struct {
+}
+
+func (splitter *splitterImpl) Split(k key.Key, attrs map[string]string, writers []io.Writer) error {
+	threshold := len(writers)
+
+	if value, ok := attrs["threshold"]; ok {
+		var err error
+
+		threshold, err = strconv.Atoi(value)
+
+		if err != nil {
+			return xerrors.Wrapf(ErrThreshold, "threshold %s", value)
+		}
+	}
+
+	shares, err := SplitKey(k, threshold, len(writers))
+
+	if err != nil {
+		return err
+	}
+
+	pubkey := hex.EncodeToString(k.PublicKey().Uncompressed())
+
+	for i, share := range shares {
+		buff, err := json.Marshal(&shareJSON{
+			PublicKey: pubkey,
+			Threshold: threshold,
+			X:         share.X,
+			Y:         hex.EncodeToString(share.Y),
+		})
+
+		if err != nil {
+			return xerrors.Wrapf(err, "marshal share error")
+		}
+
+		if _, err := writers[i].Write(buff); err != nil {
+			return xerrors.Wrapf(err, "write share %d error", share.X)
+		}
+	}
+
+	return nil
+}
+
+func (splitter *splitterImpl) Combine(k key.Key, attrs map[string]string, readers []io.Reader) error {
+	var shares []*Share
+	var pubkey string
+	var threshold int
+
+	for _, reader := range readers {
+		data, err := ioutil.ReadAll(reader)
+
+		if err != nil {
+			return xerrors.Wrapf(err, "read all data from reader err")
+		}
+
+		share := new(shareJSON)
+
+		if err := json.Unmarshal(data, share); err != nil {
+			return xerrors.Wrapf(err, "unmarshal share error")
+		}
+
+		if pubkey != "" && (share.PublicKey != pubkey || share.Threshold != threshold) {
+			return xerrors.Wrapf(ErrShares, "share %d belongs to another key", share.X)
+		}
+
+		pubkey, threshold = share.PublicKey, share.Threshold
+
+		y, err := hex.DecodeString(share.Y)
+
+		if err != nil {
+			return xerrors.Wrapf(err, "decode share %d error", share.X)
+		}
+
+		shares = append(shares, &Share{X: share.X, Y: y})
+	}
+
+	if len(shares) < threshold {
+		return xerrors.Wrapf(ErrShares, "%d shares, threshold %d", len(shares), threshold)
+	}
+
+	priKey, err := Combine(shares)
+
+	if err != nil {
+		return err
+	}
+
+	// restore into a scratch key first, the caller's key is only set once the public key matches
+	restored, err := key.New(k.Provider().Name())
+
+	if err != nil {
+		return xerrors.Wrapf(err, "create %s key error", k.Provider().Name())
+	}
+
+	restored.SetBytes(priKey)
+
+	publicKey := restored.PublicKey()
+
+	if publicKey == nil || hex.EncodeToString(publicKey.Uncompressed()) != pubkey {
+		return xerrors.Wrapf(ErrShares, "restored key mismatch public key %s", pubkey)
+	}
+
+	k.SetBytes(priKey)
+
+	return nil
+}
+
+func init() {
+	key.RegisterSplitter("shamir", &splitterImpl{})
+}
@@ -0,0 +1,237 @@
+// Package bls the ethereum consensus bls signature, the BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_
+// ciphersuite of the ietf bls signature draft with the public keys in G1 and the signatures in G2,
+// the proof of possession scheme makes aggregation of the same message safe
+package bls
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/bls12381"
+)
+
+// Errors
+var (
+	ErrSecretKey = errors.New("invalid bls secret key")
+	ErrPublicKey = errors.New("invalid bls public key")
+	ErrSignature = errors.New("invalid bls signature")
+	ErrAggregate = errors.New("nothing to aggregate")
+	ErrSeed      = errors.New("bls key material must be at least 32 bytes")
+)
+
+// Sizes
+const (
+	SecretKeySize = 32
+	PublicKeySize = bls12381.G1CompressedSize
+	SignatureSize = bls12381.G2CompressedSize
+)
+
+// domain separation tags of the signature and the proof of possession
+var (
+	dstSignature = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+	dstPop       = []byte("BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+)
+
+// SecretKey parse the 32 bytes big endian secret key, 0 < sk < r
+func SecretKey(buff []byte) (*big.Int, error) {
+	if len(buff) != SecretKeySize {
+		return nil, xerrors.Wrapf(ErrSecretKey, "length %d error", len(buff))
+	}
+
+	sk := new(big.Int).SetBytes(buff)
+
+	if sk.Sign() == 0 || sk.Cmp(bls12381.Order) >= 0 {
+		return nil, xerrors.Wrapf(ErrSecretKey, "secret key out of range")
+	}
+
+	return sk, nil
+}
+
+// SecretKeyBytes the 32 bytes big endian secret key
+func SecretKeyBytes(sk *big.Int) []byte {
+	return sk.FillBytes(make([]byte, SecretKeySize))
+}
+
+// PublicKey the 48 bytes compressed G1 public key sk * G
+func PublicKey(sk *big.Int) []byte {
+	return bls12381.NewG1Generator().ScalarMult(sk).Bytes()
+}
+
+// publicKey decode and validate the public key, which must not be the identity
+func publicKey(pubkey []byte) (*bls12381.G1, error) {
+	p, err := bls12381.NewG1(pubkey)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(ErrPublicKey, "decode public key error: %s", err)
+	}
+
+	if p.IsIdentity() {
+		return nil, xerrors.Wrapf(ErrPublicKey, "public key is the identity")
+	}
+
+	return p, nil
+}
+
+func signature(sig []byte) (*bls12381.G2, error) {
+	p, err := bls12381.NewG2(sig)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(ErrSignature, "decode signature error: %s", err)
+	}
+
+	return p, nil
+}
+
+// KeyValidate check the public key is a valid non identity G1 point of the subgroup
+func KeyValidate(pubkey []byte) bool {
+	_, err := publicKey(pubkey)
+
+	return err == nil
+}
+
+func sign(sk *big.Int, msg, dst []byte) []byte {
+	return bls12381.HashToG2(msg, dst).ScalarMult(sk).Bytes()
+}
+
+// coreVerify e(P, H(msg)) = e(G, sig)
+func coreVerify(p *bls12381.G1, msg, sig, dst []byte) bool {
+	s, err := signature(sig)
+
+	if err != nil {
+		return false
+	}
+
+	return bls12381.PairingCheck(
+		[]*bls12381.G1{p, bls12381.NewG1Generator().Neg()},
+		[]*bls12381.G2{bls12381.HashToG2(msg, dst), s},
+	)
+}
+
+// Sign the 96 bytes compressed G2 signature sk * H(msg)
+func Sign(sk *big.Int, msg []byte) []byte {
+	return sign(sk, msg, dstSignature)
+}
+
+// Verify verify the signature of the message
+func Verify(pubkey, msg, sig []byte) bool {
+	p, err := publicKey(pubkey)
+
+	if err != nil {
+		return false
+	}
+
+	return coreVerify(p, msg, sig, dstSignature)
+}
+
+// Aggregate aggregate the signatures into one signature
+func Aggregate(sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, ErrAggregate
+	}
+
+	aggregated := bls12381.NewG2Identity()
+
+	for i, sig := range sigs {
+		s, err := signature(sig)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "signature %d", i)
+		}
+
+		aggregated = aggregated.Add(s)
+	}
+
+	return aggregated.Bytes(), nil
+}
+
+func aggregatePublicKeys(pubkeys [][]byte) (*bls12381.G1, error) {
+	if len(pubkeys) == 0 {
+		return nil, ErrAggregate
+	}
+
+	aggregated := bls12381.NewG1Identity()
+
+	for i, pubkey := range pubkeys {
+		p, err := publicKey(pubkey)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "public key %d", i)
+		}
+
+		aggregated = aggregated.Add(p)
+	}
+
+	return aggregated, nil
+}
+
+// AggregatePublicKeys aggregate the public keys into one public key, the keys must have proven
+// possession of their secret keys
+func AggregatePublicKeys(pubkeys [][]byte) ([]byte, error) {
+	aggregated, err := aggregatePublicKeys(pubkeys)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return aggregated.Bytes(), nil
+}
+
+// FastAggregateVerify verify the aggregated signature of the same message signed by all the public keys
+func FastAggregateVerify(pubkeys [][]byte, msg, sig []byte) bool {
+	aggregated, err := aggregatePublicKeys(pubkeys)
+
+	if err != nil {
+		return false
+	}
+
+	return coreVerify(aggregated, msg, sig, dstSignature)
+}
+
+// AggregateVerify verify the aggregated signature of the messages each signed by the public key
+// of the same index, e(P_1, H(msg_1)) * ... * e(P_n, H(msg_n)) = e(G, sig)
+func AggregateVerify(pubkeys [][]byte, msgs [][]byte, sig []byte) bool {
+	if len(pubkeys) == 0 || len(pubkeys) != len(msgs) {
+		return false
+	}
+
+	s, err := signature(sig)
+
+	if err != nil {
+		return false
+	}
+
+	g1s := []*bls12381.G1{bls12381.NewG1Generator().Neg()}
+	g2s := []*bls12381.G2{s}
+
+	for i, pubkey := range pubkeys {
+		p, err := publicKey(pubkey)
+
+		if err != nil {
+			return false
+		}
+
+		g1s = append(g1s, p)
+		g2s = append(g2s, bls12381.HashToG2(msgs[i], dstSignature))
+	}
+
+	return bls12381.PairingCheck(g1s, g2s)
+}
+
+// PopProve the proof of possession of the secret key, the signature of the public key with the
+// proof of possession tag
+func PopProve(sk *big.Int) []byte {
+	return sign(sk, PublicKey(sk), dstPop)
+}
+
+// PopVerify verify the proof of possession of the public key
+func PopVerify(pubkey, proof []byte) bool {
+	p, err := publicKey(pubkey)
+
+	if err != nil {
+		return false
+	}
+
+	return coreVerify(p, pubkey, proof, dstPop)
+}
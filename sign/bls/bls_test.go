@@ -0,0 +1,179 @@
+package bls
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	buff, err := hex.DecodeString(s)
+
+	require.NoError(t, err)
+
+	return buff
+}
+
+func decimal(t *testing.T, s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+
+	require.True(t, ok)
+
+	return v
+}
+
+// consensus spec sign test vector
+func TestSign(t *testing.T) {
+	sk, err := SecretKey(decodeHex(t, "263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3"))
+
+	require.NoError(t, err)
+
+	pubkey := PublicKey(sk)
+
+	require.Equal(t, "a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a", hex.EncodeToString(pubkey))
+
+	msg := make([]byte, 32)
+
+	sig := Sign(sk, msg)
+
+	require.Equal(t, "b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55", hex.EncodeToString(sig))
+
+	require.True(t, Verify(pubkey, msg, sig))
+
+	require.False(t, Verify(pubkey, append(msg, 0), sig))
+
+	_, err = SecretKey(make([]byte, 32))
+
+	require.Error(t, err)
+}
+
+func TestEIP2333(t *testing.T) {
+	vectors := []struct {
+		seed   string
+		master string
+		index  uint32
+		child  string
+	}{
+		{
+			"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+			"6083874454709270928345386274498605044986640685124978867557563392430687146096",
+			0,
+			"20397789859736650942317412262472558107875392172444076792671091975210932703118",
+		},
+		{
+			"3141592653589793238462643383279502884197169399375105820974944592",
+			"29757020647961307431480504535336562678282505419141012933316116377660817309383",
+			3141592653,
+			"25457201688850691947727629385191704516744796114925897962676248250929345014287",
+		},
+		{
+			"0099FF991111002299DD7744EE3355BBDD8844115566CC55663355668888CC00",
+			"27580842291869792442942448775674722299803720648445448686099262467207037398656",
+			4294967295,
+			"29358610794459428860402234341874281240803786294062035874021252734817515685787",
+		},
+		{
+			"d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+			"19022158461524446591288038168518313374041767046816487870552872741050760015818",
+			42,
+			"31372231650479070279774297061823572166496564838472787488249775572789064611981",
+		},
+	}
+
+	for _, v := range vectors {
+		master, err := DeriveMasterSK(decodeHex(t, v.seed))
+
+		require.NoError(t, err)
+
+		require.Equal(t, decimal(t, v.master), master)
+
+		require.Equal(t, decimal(t, v.child), DeriveChildSK(master, v.index))
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	seed := make([]byte, 32)
+
+	var sks []*big.Int
+
+	var pubkeys [][]byte
+
+	for i := 0; i < 3; i++ {
+		sk, err := DerivePath(seed, SigningKeyPath(uint32(i)))
+
+		require.NoError(t, err)
+
+		sks = append(sks, sk)
+
+		pubkeys = append(pubkeys, PublicKey(sk))
+
+		proof := PopProve(sk)
+
+		require.True(t, PopVerify(pubkeys[i], proof))
+
+		// the proof is not a signature of the public key
+		require.False(t, Verify(pubkeys[i], pubkeys[i], proof))
+	}
+
+	require.False(t, PopVerify(pubkeys[0], PopProve(sks[1])))
+
+	msg := []byte("attestation")
+
+	var sigs [][]byte
+
+	for _, sk := range sks {
+		sigs = append(sigs, Sign(sk, msg))
+	}
+
+	sig, err := Aggregate(sigs)
+
+	require.NoError(t, err)
+
+	require.True(t, FastAggregateVerify(pubkeys, msg, sig))
+
+	require.False(t, FastAggregateVerify(pubkeys[:2], msg, sig))
+
+	require.False(t, FastAggregateVerify(nil, msg, sig))
+
+	aggregated, err := AggregatePublicKeys(pubkeys)
+
+	require.NoError(t, err)
+
+	require.True(t, Verify(aggregated, msg, sig))
+
+	// distinct messages
+	msgs := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+
+	for i, sk := range sks {
+		sigs[i] = Sign(sk, msgs[i])
+	}
+
+	sig, err = Aggregate(sigs)
+
+	require.NoError(t, err)
+
+	require.True(t, AggregateVerify(pubkeys, msgs, sig))
+
+	require.False(t, AggregateVerify(pubkeys, [][]byte{msgs[1], msgs[0], msgs[2]}, sig))
+
+	require.False(t, AggregateVerify(pubkeys[:2], msgs, sig))
+
+	_, err = Aggregate(nil)
+
+	require.Equal(t, ErrAggregate, err)
+
+	// the identity public key is rejected
+	identity := make([]byte, PublicKeySize)
+
+	identity[0] = 0xc0
+
+	require.False(t, KeyValidate(identity))
+
+	require.False(t, FastAggregateVerify([][]byte{identity}, msg, sig))
+
+	_, err = DeriveMasterSK(seed[1:])
+
+	require.Equal(t, ErrSeed, err)
+}
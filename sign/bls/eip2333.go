@@ -0,0 +1,126 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/laplacenetwork/key/hd"
+	"github.com/laplacenetwork/key/internal/bls12381"
+)
+
+// lamportChunks the number of 32 bytes lamport secret key chunks
+const lamportChunks = 255
+
+// SigningKeyPath eip2334 validator signing key path m/12381/3600/index/0/0
+func SigningKeyPath(index uint32) string {
+	return fmt.Sprintf("m/12381/3600/%d/0/0", index)
+}
+
+// WithdrawalKeyPath eip2334 validator withdrawal key path m/12381/3600/index/0
+func WithdrawalKeyPath(index uint32) string {
+	return fmt.Sprintf("m/12381/3600/%d/0", index)
+}
+
+// hkdfModR the ietf KeyGen, hkdf of the key material until the secret key mod r is not zero
+func hkdfModR(ikm, keyInfo []byte) *big.Int {
+	const l = 48
+
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+
+	info := append(append([]byte{}, keyInfo...), 0, l)
+
+	okm := make([]byte, l)
+
+	for {
+		h := sha256.Sum256(salt)
+
+		salt = h[:]
+
+		prk := hkdf.Extract(sha256.New, append(append([]byte{}, ikm...), 0), salt)
+
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), okm); err != nil {
+			panic(err)
+		}
+
+		sk := new(big.Int).SetBytes(okm)
+
+		if sk.Mod(sk, bls12381.Order).Sign() != 0 {
+			return sk
+		}
+	}
+}
+
+// KeyGen the ietf KeyGen of the secret key from at least 32 bytes key material
+func KeyGen(ikm, keyInfo []byte) (*big.Int, error) {
+	if len(ikm) < 32 {
+		return nil, ErrSeed
+	}
+
+	return hkdfModR(ikm, keyInfo), nil
+}
+
+// lamportPublicKey the compressed lamport public key of the secret key and child index
+func lamportPublicKey(parent *big.Int, index uint32) []byte {
+	salt := []byte{byte(index >> 24), byte(index >> 16), byte(index >> 8), byte(index)}
+
+	ikm := SecretKeyBytes(parent)
+
+	notIKM := make([]byte, len(ikm))
+
+	for i := range ikm {
+		notIKM[i] = ^ikm[i]
+	}
+
+	hasher := sha256.New()
+
+	for _, material := range [][]byte{ikm, notIKM} {
+		okm := make([]byte, 32*lamportChunks)
+
+		if _, err := io.ReadFull(hkdf.New(sha256.New, material, salt, nil), okm); err != nil {
+			panic(err)
+		}
+
+		for i := 0; i < lamportChunks; i++ {
+			chunk := sha256.Sum256(okm[32*i : 32*(i+1)])
+
+			hasher.Write(chunk[:])
+		}
+	}
+
+	return hasher.Sum(nil)
+}
+
+// DeriveMasterSK eip2333 master secret key of the seed
+func DeriveMasterSK(seed []byte) (*big.Int, error) {
+	return KeyGen(seed, nil)
+}
+
+// DeriveChildSK eip2333 child secret key, all children are hardened
+func DeriveChildSK(parent *big.Int, index uint32) *big.Int {
+	return hkdfModR(lamportPublicKey(parent, index), nil)
+}
+
+// DerivePath eip2333 secret key of the seed by eip2334 path, e.g. m/12381/3600/0/0/0
+func DerivePath(seed []byte, path string) (*big.Int, error) {
+	indexes, err := hd.ParsePath(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sk, err := DeriveMasterSK(seed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index := range indexes {
+		sk = DeriveChildSK(sk, index)
+	}
+
+	return sk, nil
+}
@@ -0,0 +1,312 @@
+package sign
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrEncoding = errors.New("invalid signature encoding")
+	ErrDER      = errors.New("non-strict der signature")
+	ErrRecovery = errors.New("invalid signature recovery id")
+)
+
+// Recovery id offsets of the recoverable encoding v byte
+const (
+	VRaw    byte = 0  // v is the raw 0/1 recovery id
+	VLegacy byte = 27 // v is 27/28, or 31/32 with the compressed public key flag
+)
+
+// RecoveryID get the 0/1 recovery id from V, V is 0/1 or 27/28, and 31/32 with compressed flag
+func (sign *Signature) RecoveryID() (byte, error) {
+	if sign.V == nil || !sign.V.IsUint64() || sign.V.Uint64() > 34 {
+		return 0, xerrors.Wrapf(ErrRecovery, "invalid v")
+	}
+
+	v := byte(sign.V.Uint64())
+
+	if v >= 27 {
+		v = (v - 27) &^ 4
+	}
+
+	if v > 1 {
+		return 0, xerrors.Wrapf(ErrRecovery, "v %d", sign.V.Uint64())
+	}
+
+	return v, nil
+}
+
+// Compressed check the compressed public key flag of V
+func (sign *Signature) Compressed() bool {
+	return sign.V != nil && sign.V.IsUint64() && sign.V.Uint64() >= 31 && sign.V.Uint64() <= 34
+}
+
+func fixedBytes(v *big.Int, size int) ([]byte, error) {
+	if v == nil || v.Sign() < 0 || v.BitLen() > size*8 {
+		return nil, xerrors.Wrapf(ErrEncoding, "value overflow %d bytes", size)
+	}
+
+	return v.FillBytes(make([]byte, size)), nil
+}
+
+// EncodeRaw encode signature as size bytes r || size bytes s
+func EncodeRaw(sig *Signature, size int) ([]byte, error) {
+	r, err := fixedBytes(sig.R, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := fixedBytes(sig.S, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(r, s...), nil
+}
+
+// DecodeRaw decode r || s signature, r and s have the same length
+func DecodeRaw(buff []byte) (*Signature, error) {
+	if len(buff) == 0 || len(buff)%2 != 0 {
+		return nil, xerrors.Wrapf(ErrEncoding, "raw signature length %d error", len(buff))
+	}
+
+	size := len(buff) / 2
+
+	return &Signature{
+		R: new(big.Int).SetBytes(buff[:size]),
+		S: new(big.Int).SetBytes(buff[size:]),
+	}, nil
+}
+
+// EncodeRecoverable encode signature as r || s || v, v is recovery id plus offset,
+// the compressed flag is kept with VLegacy offset
+func EncodeRecoverable(sig *Signature, size int, offset byte) ([]byte, error) {
+	recid, err := sig.RecoveryID()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if offset == VLegacy && sig.Compressed() {
+		recid += 4
+	}
+
+	buff, err := EncodeRaw(sig, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return append(buff, recid+offset), nil
+}
+
+// DecodeRecoverable decode r || s || v signature, v must be 27/28 or 31/32 with the compressed flag,
+// the raw 0/1 recovery id is rejected unless decoded by DecodeRecoverableOffset with VRaw
+func DecodeRecoverable(buff []byte) (*Signature, error) {
+	return DecodeRecoverableOffset(buff, VLegacy)
+}
+
+// DecodeRecoverableOffset decode r || s || v signature with the v offset of the encoding, VRaw only accepts
+// the 0/1 recovery id, the decoded V is normalized to 27/28 (31/32 compressed)
+func DecodeRecoverableOffset(buff []byte, offset byte) (*Signature, error) {
+	if len(buff) < 3 || len(buff)%2 != 1 {
+		return nil, xerrors.Wrapf(ErrEncoding, "recoverable signature length %d error", len(buff))
+	}
+
+	sig, err := DecodeRaw(buff[:len(buff)-1])
+
+	if err != nil {
+		return nil, err
+	}
+
+	v := buff[len(buff)-1]
+
+	switch offset {
+	case VRaw:
+		if v > 1 {
+			return nil, xerrors.Wrapf(ErrRecovery, "raw v %d", v)
+		}
+
+		v += VLegacy
+	case VLegacy:
+		if v < VLegacy {
+			return nil, xerrors.Wrapf(ErrRecovery, "v %d without offset 27", v)
+		}
+	default:
+		return nil, xerrors.Wrapf(ErrRecovery, "unknown v offset %d", offset)
+	}
+
+	sig.V = big.NewInt(int64(v))
+
+	if _, err := sig.RecoveryID(); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// EncodeCompact encode 256 bits curve signature as eip-2098 compact r || (recid << 255 | s),
+// s must be in the lower half order
+func EncodeCompact(sig *Signature) ([]byte, error) {
+	recid, err := sig.RecoveryID()
+
+	if err != nil {
+		return nil, err
+	}
+
+	buff, err := EncodeRaw(sig, 32)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if buff[32]&0x80 != 0 {
+		return nil, xerrors.Wrapf(ErrEncoding, "eip-2098 requires low s")
+	}
+
+	buff[32] |= recid << 7
+
+	return buff, nil
+}
+
+// DecodeCompact decode eip-2098 compact signature, the decoded V is 27/28
+func DecodeCompact(buff []byte) (*Signature, error) {
+	if len(buff) != 64 {
+		return nil, xerrors.Wrapf(ErrEncoding, "compact signature length %d error", len(buff))
+	}
+
+	recid := buff[32] >> 7
+
+	s := append([]byte(nil), buff[32:]...)
+
+	s[0] &= 0x7f
+
+	return &Signature{
+		R: new(big.Int).SetBytes(buff[:32]),
+		S: new(big.Int).SetBytes(s),
+		V: big.NewInt(int64(27 + recid)),
+	}, nil
+}
+
+// Decode decode 65 bytes recoverable, 64 bytes eip-2098 compact or strict der signature of 256 bits curve
+func Decode(buff []byte) (*Signature, error) {
+	switch len(buff) {
+	case 65:
+		return DecodeRecoverable(buff)
+	case 64:
+		return DecodeCompact(buff)
+	default:
+		return DecodeDER(buff)
+	}
+}
+
+// derInteger encode positive integer as der integer content, with 0x00 padding if the high bit is set
+func derInteger(v *big.Int) []byte {
+	buff := v.Bytes()
+
+	if len(buff) == 0 {
+		return []byte{0x00}
+	}
+
+	if buff[0]&0x80 != 0 {
+		buff = append([]byte{0x00}, buff...)
+	}
+
+	return buff
+}
+
+// EncodeDER encode signature as asn.1 der sequence of r and s
+func EncodeDER(sig *Signature) ([]byte, error) {
+	if sig.R == nil || sig.S == nil || sig.R.Sign() <= 0 || sig.S.Sign() <= 0 {
+		return nil, xerrors.Wrapf(ErrEncoding, "r and s must be positive")
+	}
+
+	r := derInteger(sig.R)
+	s := derInteger(sig.S)
+
+	length := 4 + len(r) + len(s)
+
+	if length > 127 {
+		return nil, xerrors.Wrapf(ErrEncoding, "der signature too long")
+	}
+
+	buff := make([]byte, 0, length+2)
+
+	buff = append(buff, 0x30, byte(length))
+	buff = append(buff, 0x02, byte(len(r)))
+	buff = append(buff, r...)
+	buff = append(buff, 0x02, byte(len(s)))
+	buff = append(buff, s...)
+
+	return buff, nil
+}
+
+// DecodeDER strict decode der signature following bip-66 rules without the sighash byte:
+// short form lengths, no trailing data, minimal positive r and s
+func DecodeDER(buff []byte) (*Signature, error) {
+	// 0x30 [total-length] 0x02 [R-length] [R] 0x02 [S-length] [S]
+	if len(buff) < 8 || len(buff) > 129 {
+		return nil, xerrors.Wrapf(ErrDER, "length %d out of range", len(buff))
+	}
+
+	if buff[0] != 0x30 {
+		return nil, xerrors.Wrapf(ErrDER, "missing sequence tag")
+	}
+
+	if int(buff[1]) != len(buff)-2 {
+		return nil, xerrors.Wrapf(ErrDER, "sequence length mismatch")
+	}
+
+	rLen := int(buff[3])
+
+	if 5+rLen >= len(buff) {
+		return nil, xerrors.Wrapf(ErrDER, "r length out of range")
+	}
+
+	sLen := int(buff[5+rLen])
+
+	if rLen+sLen+6 != len(buff) {
+		return nil, xerrors.Wrapf(ErrDER, "r and s length mismatch")
+	}
+
+	r := buff[4 : 4+rLen]
+	s := buff[6+rLen:]
+
+	if err := checkDERInteger(buff[2], r); err != nil {
+		return nil, xerrors.Wrapf(err, "r")
+	}
+
+	if err := checkDERInteger(buff[4+rLen], s); err != nil {
+		return nil, xerrors.Wrapf(err, "s")
+	}
+
+	return &Signature{
+		R: new(big.Int).SetBytes(r),
+		S: new(big.Int).SetBytes(s),
+	}, nil
+}
+
+func checkDERInteger(tag byte, buff []byte) error {
+	if tag != 0x02 {
+		return xerrors.Wrapf(ErrDER, "missing integer tag")
+	}
+
+	if len(buff) == 0 {
+		return xerrors.Wrapf(ErrDER, "zero length integer")
+	}
+
+	if buff[0]&0x80 != 0 {
+		return xerrors.Wrapf(ErrDER, "negative integer")
+	}
+
+	if len(buff) > 1 && buff[0] == 0x00 && buff[1]&0x80 == 0 {
+		return xerrors.Wrapf(ErrDER, "excessive integer padding")
+	}
+
+	return nil
+}
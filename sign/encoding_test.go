@@ -0,0 +1,170 @@
+package sign
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fromHex(s string) *big.Int {
+	v, _ := new(big.Int).SetString(s, 16)
+
+	return v
+}
+
+func TestCompact(t *testing.T) {
+	// eip-2098 test vectors
+	for _, test := range []struct {
+		r, s     string
+		v        int64
+		expected string
+	}{
+		{
+			"68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b90",
+			"7e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064",
+			27,
+			"68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064",
+		},
+		{
+			"9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76",
+			"139c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793",
+			28,
+			"9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793",
+		},
+	} {
+		sig := &Signature{R: fromHex(test.r), S: fromHex(test.s), V: big.NewInt(test.v)}
+
+		compact, err := EncodeCompact(sig)
+
+		require.NoError(t, err)
+
+		require.Equal(t, test.expected, hex.EncodeToString(compact))
+
+		decoded, err := DecodeCompact(compact)
+
+		require.NoError(t, err)
+
+		require.Equal(t, sig, decoded)
+
+		recoverable, err := EncodeRecoverable(decoded, 32, VRaw)
+
+		require.NoError(t, err)
+
+		require.Equal(t, byte(test.v-27), recoverable[64])
+
+		_, err = DecodeRecoverable(recoverable)
+
+		require.Error(t, err)
+
+		decoded, err = DecodeRecoverableOffset(recoverable, VRaw)
+
+		require.NoError(t, err)
+
+		require.Equal(t, sig, decoded)
+
+		der, err := EncodeDER(decoded)
+
+		require.NoError(t, err)
+
+		decoded, err = DecodeDER(der)
+
+		require.NoError(t, err)
+
+		require.Equal(t, sig.R, decoded.R)
+		require.Equal(t, sig.S, decoded.S)
+	}
+
+	_, err := EncodeCompact(&Signature{R: big.NewInt(1), S: new(big.Int).Lsh(big.NewInt(1), 255), V: big.NewInt(27)})
+
+	require.Error(t, err)
+}
+
+func TestRecoverable(t *testing.T) {
+	sig := &Signature{R: big.NewInt(1), S: big.NewInt(2), V: big.NewInt(32)}
+
+	buff, err := EncodeRecoverable(sig, 32, VLegacy)
+
+	require.NoError(t, err)
+
+	require.Equal(t, byte(32), buff[64])
+
+	decoded, err := DecodeRecoverable(buff)
+
+	require.NoError(t, err)
+
+	require.True(t, decoded.Compressed())
+
+	id, err := decoded.RecoveryID()
+
+	require.NoError(t, err)
+
+	require.Equal(t, byte(1), id)
+
+	raw, err := EncodeRaw(sig, 32)
+
+	require.NoError(t, err)
+
+	require.Len(t, raw, 64)
+
+	// the raw recovery id needs the explicit VRaw offset
+	for _, v := range []byte{0, 1, 2, 4, 5, 26, 29, 30, 33, 35} {
+		_, err := DecodeRecoverable(append(raw, v))
+
+		require.Error(t, err, v)
+	}
+
+	for _, v := range []byte{2, 4, 5, 27, 28} {
+		_, err := DecodeRecoverableOffset(append(raw, v), VRaw)
+
+		require.Error(t, err, v)
+	}
+
+	decoded, err = DecodeRecoverableOffset(append(raw, 1), VRaw)
+
+	require.NoError(t, err)
+
+	require.Equal(t, big.NewInt(28), decoded.V)
+
+	_, err = EncodeRaw(&Signature{R: new(big.Int).Lsh(big.NewInt(1), 256), S: big.NewInt(1)}, 32)
+
+	require.Error(t, err)
+}
+
+func TestStrictDER(t *testing.T) {
+	sig := &Signature{
+		R: fromHex("8000000000000000000000000000000000000000000000000000000000000001"),
+		S: fromHex("01"),
+	}
+
+	der, err := EncodeDER(sig)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "30260221008000000000000000000000000000000000000000000000000000000000000001020101", hex.EncodeToString(der))
+
+	decoded, err := DecodeDER(der)
+
+	require.NoError(t, err)
+
+	require.Equal(t, sig.R, decoded.R)
+	require.Equal(t, sig.S, decoded.S)
+
+	for _, invalid := range []string{
+		"3025022100800000000000000000000000000000000000000000000000000000000000000102010001", // total length mismatch
+		"3026022100800000000000000000000000000000000000000000000000000000000000000102010100", // trailing data
+		"302502208000000000000000000000000000000000000000000000000000000000000001020101",     // negative r
+		"3007020200010201010000", // excessive r padding
+		"30060200020201",         // zero length r
+		"3106020101020101",       // not a sequence
+		"3006030101020101",       // r not an integer
+		"30060201010201ff",       // negative s
+	} {
+		buff, _ := hex.DecodeString(invalid)
+
+		_, err := DecodeDER(buff)
+
+		require.Error(t, err, invalid)
+	}
+}
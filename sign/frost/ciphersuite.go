@@ -0,0 +1,491 @@
+package frost
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/edwards25519"
+	"github.com/laplacenetwork/key/internal/secp256k1"
+	"github.com/laplacenetwork/key/sign/schnorr"
+)
+
+// element group element of a ciphersuite
+type element interface {
+	add(e element) element
+	mult(k *big.Int) element
+	equal(e element) bool
+	isIdentity() bool
+}
+
+// Ciphersuite rfc9591 ciphersuite, the prime order group with its encodings and the hash functions
+type Ciphersuite interface {
+	Name() string // context string
+
+	order() *big.Int
+	identity() element
+	baseMult(k *big.Int) element
+	encodeElement(e element) []byte
+	decodeElement(buff []byte) (element, error) // the identity is rejected
+	encodeScalar(k *big.Int) []byte
+	decodeScalar(buff []byte) (*big.Int, error)
+
+	h1(m []byte) *big.Int   // binding factor
+	h3(m []byte) *big.Int   // nonce
+	h4(m []byte) []byte     // message digest
+	h5(m []byte) []byte     // commitment list digest
+	hdkg(m []byte) *big.Int // dkg proof of knowledge challenge
+
+	challenge(r, pk element, msg []byte) *big.Int // H2 of the group commitment, the public key and the message
+	encodeSignature(r element, z *big.Int) []byte
+	decodeSignature(sig []byte) (element, *big.Int, error)
+}
+
+// xOnly bip340 ciphersuite, the signature verifies with an x only public key of even y, the nonces
+// are negated if the group commitment has odd y
+type xOnly interface {
+	evenY(e element) bool
+	tweak(pk element) (*big.Int, error) // bip341 tweak added to the even y group key
+}
+
+// Ed25519 FROST(Ed25519, SHA-512), the signature verifies with ed25519 Verify
+func Ed25519() Ciphersuite {
+	return ed25519Suite{}
+}
+
+// Secp256k1 FROST(secp256k1, SHA-256), the signature is the 33 bytes compressed R and 32 bytes z
+func Secp256k1() Ciphersuite {
+	return secp256k1Suite{}
+}
+
+// Taproot bip340 compatible FROST over secp256k1, the signature is made by the bip341 key path
+// output key of the group public key, it verifies with the taproot provider Verify of the group
+// public key
+func Taproot() Ciphersuite {
+	return taprootSuite{}
+}
+
+func reverse(buff []byte) []byte {
+	for i, j := 0, len(buff)-1; i < j; i, j = i+1, j-1 {
+		buff[i], buff[j] = buff[j], buff[i]
+	}
+
+	return buff
+}
+
+type ed25519Element struct {
+	p *edwards25519.Point
+}
+
+func (e ed25519Element) add(o element) element {
+	return ed25519Element{new(edwards25519.Point).Add(e.p, o.(ed25519Element).p)}
+}
+
+func (e ed25519Element) mult(k *big.Int) element {
+	return ed25519Element{new(edwards25519.Point).ScalarMult(ed25519Suite{}.encodeScalar(k), e.p)}
+}
+
+func (e ed25519Element) equal(o element) bool {
+	return e.p.Equal(o.(ed25519Element).p) == 1
+}
+
+func (e ed25519Element) isIdentity() bool {
+	return e.p.IsIdentity() == 1
+}
+
+type ed25519Suite struct{}
+
+const ed25519Context = "FROST-ED25519-SHA512-v1"
+
+func (suite ed25519Suite) Name() string {
+	return ed25519Context
+}
+
+func (suite ed25519Suite) order() *big.Int {
+	return edwards25519.L
+}
+
+func (suite ed25519Suite) identity() element {
+	return ed25519Element{edwards25519.NewIdentityPoint()}
+}
+
+func (suite ed25519Suite) baseMult(k *big.Int) element {
+	return ed25519Element{new(edwards25519.Point).ScalarBaseMult(suite.encodeScalar(k))}
+}
+
+func (suite ed25519Suite) encodeElement(e element) []byte {
+	return e.(ed25519Element).p.Bytes()
+}
+
+// decodeElement rfc8032 decoding, the element must be in the prime order subgroup
+func (suite ed25519Suite) decodeElement(buff []byte) (element, error) {
+	p, err := new(edwards25519.Point).SetBytes(buff)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(ErrEncoding, "decode ed25519 element error: %s", err)
+	}
+
+	if p.IsIdentity() == 1 || p.IsTorsionFree() != 1 {
+		return nil, xerrors.Wrapf(ErrEncoding, "ed25519 element is identity or not in the prime order subgroup")
+	}
+
+	return ed25519Element{p}, nil
+}
+
+// encodeScalar 32 bytes little endian
+func (suite ed25519Suite) encodeScalar(k *big.Int) []byte {
+	return reverse(new(big.Int).Mod(k, edwards25519.L).FillBytes(make([]byte, 32)))
+}
+
+func (suite ed25519Suite) decodeScalar(buff []byte) (*big.Int, error) {
+	if len(buff) != 32 {
+		return nil, xerrors.Wrapf(ErrEncoding, "ed25519 scalar length %d error", len(buff))
+	}
+
+	k := new(big.Int).SetBytes(reverse(append([]byte(nil), buff...)))
+
+	if k.Cmp(edwards25519.L) >= 0 {
+		return nil, xerrors.Wrapf(ErrEncoding, "ed25519 scalar out of range")
+	}
+
+	return k, nil
+}
+
+func (suite ed25519Suite) hash(tag string, m []byte) []byte {
+	hasher := sha512.New()
+
+	hasher.Write([]byte(ed25519Context))
+	hasher.Write([]byte(tag))
+	hasher.Write(m)
+
+	return hasher.Sum(nil)
+}
+
+// hashToScalar the 64 bytes digest as little endian integer mod L
+func (suite ed25519Suite) hashToScalar(digest []byte) *big.Int {
+	k := new(big.Int).SetBytes(reverse(digest))
+
+	return k.Mod(k, edwards25519.L)
+}
+
+func (suite ed25519Suite) h1(m []byte) *big.Int {
+	return suite.hashToScalar(suite.hash("rho", m))
+}
+
+func (suite ed25519Suite) h3(m []byte) *big.Int {
+	return suite.hashToScalar(suite.hash("nonce", m))
+}
+
+func (suite ed25519Suite) h4(m []byte) []byte {
+	return suite.hash("msg", m)
+}
+
+func (suite ed25519Suite) h5(m []byte) []byte {
+	return suite.hash("com", m)
+}
+
+func (suite ed25519Suite) hdkg(m []byte) *big.Int {
+	return suite.hashToScalar(suite.hash("dkg", m))
+}
+
+// challenge H2 is sha512 without the context string, the same challenge as rfc8032
+func (suite ed25519Suite) challenge(r, pk element, msg []byte) *big.Int {
+	hasher := sha512.New()
+
+	hasher.Write(suite.encodeElement(r))
+	hasher.Write(suite.encodeElement(pk))
+	hasher.Write(msg)
+
+	return suite.hashToScalar(hasher.Sum(nil))
+}
+
+func (suite ed25519Suite) encodeSignature(r element, z *big.Int) []byte {
+	return append(suite.encodeElement(r), suite.encodeScalar(z)...)
+}
+
+func (suite ed25519Suite) decodeSignature(sig []byte) (element, *big.Int, error) {
+	if len(sig) != 64 {
+		return nil, nil, xerrors.Wrapf(ErrEncoding, "ed25519 signature length %d error", len(sig))
+	}
+
+	r, err := suite.decodeElement(sig[:32])
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	z, err := suite.decodeScalar(sig[32:])
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r, z, nil
+}
+
+var secp256k1Curve = secp256k1.SECP256K1()
+
+// secp256k1Element affine point, the identity is (0, 0)
+type secp256k1Element struct {
+	x, y *big.Int
+}
+
+func (e secp256k1Element) add(o element) element {
+	x, y := secp256k1Curve.Add(e.x, e.y, o.(secp256k1Element).x, o.(secp256k1Element).y)
+
+	return secp256k1Element{x, y}
+}
+
+func (e secp256k1Element) mult(k *big.Int) element {
+	x, y := secp256k1Curve.ScalarMult(e.x, e.y, secp256k1Suite{}.encodeScalar(k))
+
+	return secp256k1Element{x, y}
+}
+
+func (e secp256k1Element) equal(o element) bool {
+	return e.x.Cmp(o.(secp256k1Element).x) == 0 && e.y.Cmp(o.(secp256k1Element).y) == 0
+}
+
+func (e secp256k1Element) isIdentity() bool {
+	return e.x.Sign() == 0 && e.y.Sign() == 0
+}
+
+type secp256k1Suite struct{}
+
+const secp256k1Context = "FROST-secp256k1-SHA256-v1"
+
+func (suite secp256k1Suite) Name() string {
+	return secp256k1Context
+}
+
+func (suite secp256k1Suite) order() *big.Int {
+	return secp256k1Curve.Params().N
+}
+
+func (suite secp256k1Suite) identity() element {
+	return secp256k1Element{new(big.Int), new(big.Int)}
+}
+
+func (suite secp256k1Suite) baseMult(k *big.Int) element {
+	x, y := secp256k1Curve.ScalarBaseMult(suite.encodeScalar(k))
+
+	return secp256k1Element{x, y}
+}
+
+// encodeElement sec1 compressed point
+func (suite secp256k1Suite) encodeElement(e element) []byte {
+	return secp256k1.CompressPubkey(e.(secp256k1Element).x, e.(secp256k1Element).y)
+}
+
+func (suite secp256k1Suite) decodeElement(buff []byte) (element, error) {
+	if len(buff) != 33 {
+		return nil, xerrors.Wrapf(ErrEncoding, "secp256k1 element length %d error", len(buff))
+	}
+
+	x, y, err := secp256k1.DecompressPubkey(buff)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(ErrEncoding, "decode secp256k1 element error: %s", err)
+	}
+
+	return secp256k1Element{x, y}, nil
+}
+
+// encodeScalar 32 bytes big endian
+func (suite secp256k1Suite) encodeScalar(k *big.Int) []byte {
+	return new(big.Int).Mod(k, suite.order()).FillBytes(make([]byte, 32))
+}
+
+func (suite secp256k1Suite) decodeScalar(buff []byte) (*big.Int, error) {
+	if len(buff) != 32 {
+		return nil, xerrors.Wrapf(ErrEncoding, "secp256k1 scalar length %d error", len(buff))
+	}
+
+	k := new(big.Int).SetBytes(buff)
+
+	if k.Cmp(suite.order()) >= 0 {
+		return nil, xerrors.Wrapf(ErrEncoding, "secp256k1 scalar out of range")
+	}
+
+	return k, nil
+}
+
+// hashToField rfc9380 hash_to_field of one scalar, expand_message_xmd with sha256 to 48 bytes
+func (suite secp256k1Suite) hashToField(context, tag string, m []byte) *big.Int {
+	dst := append([]byte(context+tag), byte(len(context+tag)))
+
+	hasher := sha256.New()
+
+	hasher.Write(make([]byte, sha256.BlockSize))
+	hasher.Write(m)
+	hasher.Write([]byte{0, 48, 0})
+	hasher.Write(dst)
+
+	b0 := hasher.Sum(nil)
+
+	hasher.Reset()
+	hasher.Write(b0)
+	hasher.Write([]byte{1})
+	hasher.Write(dst)
+
+	b1 := hasher.Sum(nil)
+
+	for i := range b0 {
+		b0[i] ^= b1[i]
+	}
+
+	hasher.Reset()
+	hasher.Write(b0)
+	hasher.Write([]byte{2})
+	hasher.Write(dst)
+
+	uniform := append(b1, hasher.Sum(nil)[:16]...)
+
+	k := new(big.Int).SetBytes(uniform)
+
+	return k.Mod(k, suite.order())
+}
+
+func (suite secp256k1Suite) hash(context, tag string, m []byte) []byte {
+	hasher := sha256.New()
+
+	hasher.Write([]byte(context))
+	hasher.Write([]byte(tag))
+	hasher.Write(m)
+
+	return hasher.Sum(nil)
+}
+
+func (suite secp256k1Suite) h1(m []byte) *big.Int {
+	return suite.hashToField(secp256k1Context, "rho", m)
+}
+
+func (suite secp256k1Suite) h3(m []byte) *big.Int {
+	return suite.hashToField(secp256k1Context, "nonce", m)
+}
+
+func (suite secp256k1Suite) h4(m []byte) []byte {
+	return suite.hash(secp256k1Context, "msg", m)
+}
+
+func (suite secp256k1Suite) h5(m []byte) []byte {
+	return suite.hash(secp256k1Context, "com", m)
+}
+
+func (suite secp256k1Suite) hdkg(m []byte) *big.Int {
+	return suite.hashToField(secp256k1Context, "dkg", m)
+}
+
+func (suite secp256k1Suite) challenge(r, pk element, msg []byte) *big.Int {
+	m := append(suite.encodeElement(r), suite.encodeElement(pk)...)
+
+	return suite.hashToField(secp256k1Context, "chal", append(m, msg...))
+}
+
+func (suite secp256k1Suite) encodeSignature(r element, z *big.Int) []byte {
+	return append(suite.encodeElement(r), suite.encodeScalar(z)...)
+}
+
+func (suite secp256k1Suite) decodeSignature(sig []byte) (element, *big.Int, error) {
+	if len(sig) != 65 {
+		return nil, nil, xerrors.Wrapf(ErrEncoding, "secp256k1 signature length %d error", len(sig))
+	}
+
+	r, err := suite.decodeElement(sig[:33])
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	z, err := suite.decodeScalar(sig[33:])
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r, z, nil
+}
+
+// taprootSuite secp256k1 group with bip340 challenge and signature encoding
+type taprootSuite struct {
+	secp256k1Suite
+}
+
+const taprootContext = "FROST-secp256k1-SHA256-TR-v1"
+
+func (suite taprootSuite) Name() string {
+	return taprootContext
+}
+
+func (suite taprootSuite) h1(m []byte) *big.Int {
+	return suite.hashToField(taprootContext, "rho", m)
+}
+
+func (suite taprootSuite) h3(m []byte) *big.Int {
+	return suite.hashToField(taprootContext, "nonce", m)
+}
+
+func (suite taprootSuite) h4(m []byte) []byte {
+	return suite.hash(taprootContext, "msg", m)
+}
+
+func (suite taprootSuite) h5(m []byte) []byte {
+	return suite.hash(taprootContext, "com", m)
+}
+
+func (suite taprootSuite) hdkg(m []byte) *big.Int {
+	return suite.hashToField(taprootContext, "dkg", m)
+}
+
+func (suite taprootSuite) xonly(e element) []byte {
+	return e.(secp256k1Element).x.FillBytes(make([]byte, 32))
+}
+
+// challenge bip340 tagged hash of the x only group commitment and public key
+func (suite taprootSuite) challenge(r, pk element, msg []byte) *big.Int {
+	e := new(big.Int).SetBytes(schnorr.TaggedHash("BIP0340/challenge", suite.xonly(r), suite.xonly(pk), msg))
+
+	return e.Mod(e, suite.order())
+}
+
+// encodeSignature 64 bytes bip340 signature x(R) || z
+func (suite taprootSuite) encodeSignature(r element, z *big.Int) []byte {
+	return append(suite.xonly(r), suite.encodeScalar(z)...)
+}
+
+func (suite taprootSuite) decodeSignature(sig []byte) (element, *big.Int, error) {
+	if len(sig) != schnorr.SignatureSize {
+		return nil, nil, xerrors.Wrapf(ErrEncoding, "bip340 signature length %d error", len(sig))
+	}
+
+	pub, err := schnorr.LiftX(sig[:32])
+
+	if err != nil {
+		return nil, nil, xerrors.Wrapf(ErrEncoding, "decode bip340 signature error: %s", err)
+	}
+
+	z, err := suite.decodeScalar(sig[32:])
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return secp256k1Element{pub.X, pub.Y}, z, nil
+}
+
+func (suite taprootSuite) evenY(e element) bool {
+	return e.(secp256k1Element).y.Bit(0) == 0
+}
+
+// tweak bip341 key path only tweak hash_TapTweak(x(P))
+func (suite taprootSuite) tweak(pk element) (*big.Int, error) {
+	t, err := schnorr.TapTweak(suite.xonly(pk), nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(t), nil
+}
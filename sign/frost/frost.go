@@ -0,0 +1,462 @@
+// Package frost rfc9591 two rounds FROST threshold schnorr signature over ed25519 and secp256k1,
+// the participants commit nonces in round one and sign the coordinator's signing package in round
+// two, the aggregated signature verifies as an ordinary single key signature of the group key
+package frost
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrEncoding     = errors.New("invalid element or scalar encoding")
+	ErrParticipants = errors.New("invalid participants or threshold")
+	ErrCommitment   = errors.New("invalid commitments")
+	ErrNonce        = errors.New("nonces not committed or already used")
+	ErrShare        = errors.New("invalid signature share")
+	ErrSignature    = errors.New("aggregated signature verify failed")
+)
+
+// Commitment round one message, the hiding and binding nonce commitments of a participant
+type Commitment struct {
+	ID      int    `json:"id"`
+	Hiding  []byte `json:"hiding"`
+	Binding []byte `json:"binding"`
+}
+
+// SigningPackage round two message from the coordinator, the message to sign and the commitments
+// of the signers
+type SigningPackage struct {
+	Message     []byte        `json:"message"`
+	Commitments []*Commitment `json:"commitments"`
+}
+
+// SignatureShare round two message to the coordinator
+type SignatureShare struct {
+	ID    int    `json:"id"`
+	Share []byte `json:"share"`
+}
+
+type commitment struct {
+	id      int
+	hiding  element
+	binding element
+}
+
+// session the view of one signing shared by the signers and the coordinator
+type session struct {
+	suite           Ciphersuite
+	message         []byte
+	signers         []int
+	commitments     map[int]*commitment
+	bindingFactors  map[int]*big.Int
+	groupCommitment element
+	challenge       *big.Int
+	scale           *big.Int // the secret shares sign as scale * share + tweak
+	tweak           *big.Int
+	negate          bool // bip340 nonces are negated for odd y group commitment
+}
+
+// signingKey the key the signature verifies with and the map scale * sk + tweak of the secret
+// shares to it, bip340 ciphersuites negate the odd y group key and add the tweak
+func signingKey(suite Ciphersuite, pk element) (element, *big.Int, *big.Int, error) {
+	scale, tweak := big.NewInt(1), new(big.Int)
+
+	x, ok := suite.(xOnly)
+
+	if !ok {
+		return pk, scale, tweak, nil
+	}
+
+	minusOne := new(big.Int).Sub(suite.order(), scale)
+
+	if !x.evenY(pk) {
+		pk = pk.mult(minusOne)
+		scale.Set(minusOne)
+	}
+
+	t, err := x.tweak(pk)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pk = pk.add(suite.baseMult(t))
+	tweak.Set(t)
+
+	if pk.isIdentity() {
+		return nil, nil, nil, xerrors.Wrapf(ErrEncoding, "tweaked group key is identity")
+	}
+
+	if !x.evenY(pk) {
+		pk = pk.mult(minusOne)
+		scale.Mod(scale.Neg(scale), suite.order())
+		tweak.Mod(tweak.Neg(tweak), suite.order())
+	}
+
+	return pk, scale, tweak, nil
+}
+
+// interpolate lagrange coefficient of id at 0 for the signers
+func interpolate(suite Ciphersuite, id int, signers []int) *big.Int {
+	q := suite.order()
+
+	numerator, denominator := big.NewInt(1), big.NewInt(1)
+
+	for _, j := range signers {
+		if j == id {
+			continue
+		}
+
+		numerator.Mul(numerator, big.NewInt(int64(j)))
+		denominator.Mul(denominator, big.NewInt(int64(j-id)))
+	}
+
+	denominator.Mod(denominator, q)
+
+	numerator.Mul(numerator, new(big.Int).ModInverse(denominator, q))
+
+	return numerator.Mod(numerator, q)
+}
+
+func newSession(suite Ciphersuite, key *GroupKey, pk element, pkg *SigningPackage) (*session, error) {
+	if pkg == nil || len(pkg.Commitments) < key.Threshold {
+		return nil, xerrors.Wrapf(ErrParticipants, "signing package requires %d commitments at least", key.Threshold)
+	}
+
+	s := &session{
+		suite:          suite,
+		message:        pkg.Message,
+		commitments:    make(map[int]*commitment),
+		bindingFactors: make(map[int]*big.Int),
+	}
+
+	for _, c := range pkg.Commitments {
+		if c == nil {
+			return nil, xerrors.Wrapf(ErrCommitment, "nil commitment")
+		}
+
+		if _, ok := key.Shares[c.ID]; !ok || s.commitments[c.ID] != nil {
+			return nil, xerrors.Wrapf(ErrCommitment, "unknown or duplicate participant %d", c.ID)
+		}
+
+		hiding, err := suite.decodeElement(c.Hiding)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "decode hiding commitment of %d error", c.ID)
+		}
+
+		binding, err := suite.decodeElement(c.Binding)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "decode binding commitment of %d error", c.ID)
+		}
+
+		s.signers = append(s.signers, c.ID)
+		s.commitments[c.ID] = &commitment{id: c.ID, hiding: hiding, binding: binding}
+	}
+
+	sort.Ints(s.signers)
+
+	var err error
+
+	pk, s.scale, s.tweak, err = signingKey(suite, pk)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// rho_i = H1(pk || H4(msg) || H5(encoded commitments) || i)
+	var encoded bytes.Buffer
+
+	for _, id := range s.signers {
+		encoded.Write(suite.encodeScalar(big.NewInt(int64(id))))
+		encoded.Write(suite.encodeElement(s.commitments[id].hiding))
+		encoded.Write(suite.encodeElement(s.commitments[id].binding))
+	}
+
+	prefix := append(suite.encodeElement(pk), suite.h4(pkg.Message)...)
+
+	prefix = append(prefix, suite.h5(encoded.Bytes())...)
+
+	s.groupCommitment = suite.identity()
+
+	for _, id := range s.signers {
+		rho := suite.h1(append(prefix[:len(prefix):len(prefix)], suite.encodeScalar(big.NewInt(int64(id)))...))
+
+		s.bindingFactors[id] = rho
+
+		c := s.commitments[id]
+
+		s.groupCommitment = s.groupCommitment.add(c.hiding).add(c.binding.mult(rho))
+	}
+
+	if s.groupCommitment.isIdentity() {
+		return nil, xerrors.Wrapf(ErrCommitment, "group commitment is identity")
+	}
+
+	if x, ok := suite.(xOnly); ok && !x.evenY(s.groupCommitment) {
+		s.negate = true
+		s.groupCommitment = s.groupCommitment.mult(new(big.Int).Sub(suite.order(), big.NewInt(1)))
+	}
+
+	s.challenge = suite.challenge(s.groupCommitment, pk, pkg.Message)
+
+	return s, nil
+}
+
+// commitmentShare hiding + rho * binding of the signer, negated with the nonces
+func (s *session) commitmentShare(id int) element {
+	c := s.commitments[id]
+
+	share := c.hiding.add(c.binding.mult(s.bindingFactors[id]))
+
+	if s.negate {
+		share = share.mult(new(big.Int).Sub(s.suite.order(), big.NewInt(1)))
+	}
+
+	return share
+}
+
+// Participant signer state machine, the nonces of Commit are used by the next Sign only
+type Participant struct {
+	suite      Ciphersuite
+	share      *KeyShare
+	secret     *big.Int
+	publicKey  element
+	hiding     *big.Int
+	binding    *big.Int
+	commitment *Commitment
+}
+
+// NewParticipant create signer of the key share
+func NewParticipant(suite Ciphersuite, share *KeyShare) (*Participant, error) {
+	secret, publicKey, err := share.decode(suite)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Participant{
+		suite:     suite,
+		share:     share,
+		secret:    secret,
+		publicKey: publicKey,
+	}, nil
+}
+
+// nonce H3(random || secret)
+func (p *Participant) nonce(random []byte) *big.Int {
+	return p.suite.h3(append(append([]byte(nil), random...), p.suite.encodeScalar(p.secret)...))
+}
+
+func (p *Participant) commit(hidingRandom, bindingRandom []byte) *Commitment {
+	p.hiding = p.nonce(hidingRandom)
+	p.binding = p.nonce(bindingRandom)
+
+	p.commitment = &Commitment{
+		ID:      p.share.ID,
+		Hiding:  p.suite.encodeElement(p.suite.baseMult(p.hiding)),
+		Binding: p.suite.encodeElement(p.suite.baseMult(p.binding)),
+	}
+
+	return p.commitment
+}
+
+// Commit round one, generate fresh nonces and return the commitments to them, the previous
+// uncommitted nonces are discarded
+func (p *Participant) Commit() (*Commitment, error) {
+	random := make([]byte, 64)
+
+	if _, err := rand.Read(random); err != nil {
+		return nil, xerrors.Wrapf(err, "read random error")
+	}
+
+	return p.commit(random[:32], random[32:]), nil
+}
+
+// Sign round two, sign the signing package with the committed nonces, the nonces are erased even
+// if the signing package is rejected
+func (p *Participant) Sign(pkg *SigningPackage) (*SignatureShare, error) {
+	if p.hiding == nil {
+		return nil, ErrNonce
+	}
+
+	hiding, binding, committed := p.hiding, p.binding, p.commitment
+
+	p.hiding, p.binding, p.commitment = nil, nil, nil
+
+	s, err := newSession(p.suite, &p.share.GroupKey, p.publicKey, pkg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	id := p.share.ID
+
+	own := s.commitments[id]
+
+	if own == nil || !bytes.Equal(p.suite.encodeElement(own.hiding), committed.Hiding) ||
+		!bytes.Equal(p.suite.encodeElement(own.binding), committed.Binding) {
+		return nil, xerrors.Wrapf(ErrCommitment, "signing package does not carry the commitment of %d", id)
+	}
+
+	q := p.suite.order()
+
+	// z_i = d_i + e_i * rho_i + lambda_i * sk_i * c
+	z := new(big.Int).Mul(binding, s.bindingFactors[id])
+
+	z.Add(z, hiding)
+
+	if s.negate {
+		z.Neg(z)
+	}
+
+	secret := new(big.Int).Mul(p.secret, s.scale)
+
+	secret.Add(secret, s.tweak)
+
+	secret.Mul(secret, interpolate(p.suite, id, s.signers))
+
+	z.Add(z, secret.Mul(secret, s.challenge))
+
+	return &SignatureShare{ID: id, Share: p.suite.encodeScalar(z.Mod(z, q))}, nil
+}
+
+// Coordinator signing coordinator state machine, it collects the commitments, sends the signing
+// package and aggregates the verified signature shares
+type Coordinator struct {
+	suite     Ciphersuite
+	key       *GroupKey
+	publicKey element
+	shares    map[int]element
+	session   *session
+	sigShares map[int]*big.Int
+}
+
+// NewCoordinator create coordinator of the group key
+func NewCoordinator(suite Ciphersuite, key *GroupKey) (*Coordinator, error) {
+	publicKey, shares, err := key.decode(suite)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Coordinator{
+		suite:     suite,
+		key:       key,
+		publicKey: publicKey,
+		shares:    shares,
+	}, nil
+}
+
+// SigningPackage start signing message with the round one commitments of the signers
+func (c *Coordinator) SigningPackage(message []byte, commitments []*Commitment) (*SigningPackage, error) {
+	pkg := &SigningPackage{
+		Message:     message,
+		Commitments: commitments,
+	}
+
+	s, err := newSession(c.suite, c.key, c.publicKey, pkg)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.session = s
+	c.sigShares = make(map[int]*big.Int)
+
+	return pkg, nil
+}
+
+// AddShare verify and add the signature share of a signer, the error names the cheating signer
+func (c *Coordinator) AddShare(share *SignatureShare) error {
+	s := c.session
+
+	if s == nil {
+		return xerrors.Wrapf(ErrShare, "signing not started")
+	}
+
+	if share == nil || s.commitments[share.ID] == nil {
+		return xerrors.Wrapf(ErrShare, "unexpected signature share")
+	}
+
+	z, err := c.suite.decodeScalar(share.Share)
+
+	if err != nil {
+		return xerrors.Wrapf(ErrShare, "decode signature share of %d error: %s", share.ID, err)
+	}
+
+	// z_i * G = R_i + (lambda_i * c) * (scale * PK_i + tweak * G)
+	pk := c.shares[share.ID].mult(s.scale).add(c.suite.baseMult(s.tweak))
+
+	k := new(big.Int).Mul(interpolate(c.suite, share.ID, s.signers), s.challenge)
+
+	if !c.suite.baseMult(z).equal(s.commitmentShare(share.ID).add(pk.mult(k))) {
+		return xerrors.Wrapf(ErrShare, "signature share of %d verify failed", share.ID)
+	}
+
+	c.sigShares[share.ID] = z
+
+	return nil
+}
+
+// Signature aggregate the signature shares of all the signers
+func (c *Coordinator) Signature() ([]byte, error) {
+	s := c.session
+
+	if s == nil {
+		return nil, xerrors.Wrapf(ErrShare, "signing not started")
+	}
+
+	z := new(big.Int)
+
+	for _, id := range s.signers {
+		share, ok := c.sigShares[id]
+
+		if !ok {
+			return nil, xerrors.Wrapf(ErrShare, "missing signature share of %d", id)
+		}
+
+		z.Add(z, share)
+	}
+
+	sig := c.suite.encodeSignature(s.groupCommitment, z.Mod(z, c.suite.order()))
+
+	if !Verify(c.suite, c.key.PublicKey, s.message, sig) {
+		return nil, ErrSignature
+	}
+
+	return sig, nil
+}
+
+// Verify verify signature of the group public key, z * G = R + c * PK
+func Verify(suite Ciphersuite, publicKey []byte, msg []byte, sig []byte) bool {
+	pk, err := suite.decodeElement(publicKey)
+
+	if err != nil {
+		return false
+	}
+
+	pk, _, _, err = signingKey(suite, pk)
+
+	if err != nil {
+		return false
+	}
+
+	r, z, err := suite.decodeSignature(sig)
+
+	if err != nil {
+		return false
+	}
+
+	c := suite.challenge(r, pk, msg)
+
+	return suite.baseMult(z).equal(r.add(pk.mult(c)))
+}
@@ -0,0 +1,438 @@
+package frost
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/laplacenetwork/key"
+	_ "github.com/laplacenetwork/key/provider"
+)
+
+type vectors struct {
+	Config struct {
+		MaxParticipants int `json:"MAX_PARTICIPANTS"`
+		MinParticipants int `json:"MIN_PARTICIPANTS"`
+	} `json:"config"`
+	Inputs struct {
+		ParticipantList   []int    `json:"participant_list"`
+		GroupSecretKey    string   `json:"group_secret_key"`
+		GroupPublicKey    string   `json:"group_public_key"`
+		Message           string   `json:"message"`
+		Coefficients      []string `json:"share_polynomial_coefficients"`
+		ParticipantShares []struct {
+			ID    int    `json:"identifier"`
+			Share string `json:"participant_share"`
+		} `json:"participant_shares"`
+	} `json:"inputs"`
+	RoundOne struct {
+		Outputs []struct {
+			ID                     int    `json:"identifier"`
+			HidingNonceRandomness  string `json:"hiding_nonce_randomness"`
+			BindingNonceRandomness string `json:"binding_nonce_randomness"`
+			HidingNonce            string `json:"hiding_nonce"`
+			BindingNonce           string `json:"binding_nonce"`
+			HidingNonceCommitment  string `json:"hiding_nonce_commitment"`
+			BindingNonceCommitment string `json:"binding_nonce_commitment"`
+			BindingFactor          string `json:"binding_factor"`
+		} `json:"outputs"`
+	} `json:"round_one_outputs"`
+	RoundTwo struct {
+		Outputs []struct {
+			ID       int    `json:"identifier"`
+			SigShare string `json:"sig_share"`
+		} `json:"outputs"`
+	} `json:"round_two_outputs"`
+	FinalOutput struct {
+		Sig string `json:"sig"`
+	} `json:"final_output"`
+}
+
+func unhex(t *testing.T, s string) []byte {
+	buff, err := hex.DecodeString(s)
+
+	require.NoError(t, err)
+
+	return buff
+}
+
+func testVectors(t *testing.T, suite Ciphersuite, file string) {
+	buff, err := ioutil.ReadFile(file)
+
+	require.NoError(t, err)
+
+	var v vectors
+
+	require.NoError(t, json.Unmarshal(buff, &v))
+
+	var coefficients []*big.Int
+
+	for _, c := range v.Inputs.Coefficients {
+		k, err := suite.decodeScalar(unhex(t, c))
+
+		require.NoError(t, err)
+
+		coefficients = append(coefficients, k)
+	}
+
+	shares := deal(suite, coefficients, v.Config.MinParticipants, v.Config.MaxParticipants)
+
+	require.Equal(t, v.Inputs.GroupPublicKey, hex.EncodeToString(shares[0].PublicKey))
+
+	for i, share := range v.Inputs.ParticipantShares {
+		require.Equal(t, share.ID, shares[i].ID)
+
+		require.Equal(t, share.Share, hex.EncodeToString(shares[i].Secret))
+	}
+
+	message := unhex(t, v.Inputs.Message)
+
+	participants := make(map[int]*Participant)
+
+	var commitments []*Commitment
+
+	for _, output := range v.RoundOne.Outputs {
+		participant, err := NewParticipant(suite, shares[output.ID-1])
+
+		require.NoError(t, err)
+
+		participants[output.ID] = participant
+
+		commitment := participant.commit(unhex(t, output.HidingNonceRandomness), unhex(t, output.BindingNonceRandomness))
+
+		require.Equal(t, output.HidingNonce, hex.EncodeToString(suite.encodeScalar(participant.hiding)))
+
+		require.Equal(t, output.BindingNonce, hex.EncodeToString(suite.encodeScalar(participant.binding)))
+
+		require.Equal(t, output.HidingNonceCommitment, hex.EncodeToString(commitment.Hiding))
+
+		require.Equal(t, output.BindingNonceCommitment, hex.EncodeToString(commitment.Binding))
+
+		commitments = append(commitments, commitment)
+	}
+
+	coordinator, err := NewCoordinator(suite, &shares[0].GroupKey)
+
+	require.NoError(t, err)
+
+	pkg, err := coordinator.SigningPackage(message, commitments)
+
+	require.NoError(t, err)
+
+	for _, output := range v.RoundOne.Outputs {
+		require.Equal(t, output.BindingFactor, hex.EncodeToString(suite.encodeScalar(coordinator.session.bindingFactors[output.ID])))
+	}
+
+	for _, output := range v.RoundTwo.Outputs {
+		share, err := participants[output.ID].Sign(pkg)
+
+		require.NoError(t, err)
+
+		require.Equal(t, output.SigShare, hex.EncodeToString(share.Share))
+
+		require.NoError(t, coordinator.AddShare(share))
+	}
+
+	sig, err := coordinator.Signature()
+
+	require.NoError(t, err)
+
+	require.Equal(t, v.FinalOutput.Sig, hex.EncodeToString(sig))
+
+	require.True(t, Verify(suite, shares[0].PublicKey, message, sig))
+}
+
+func TestVectors(t *testing.T) {
+	testVectors(t, Ed25519(), "testdata/vectors-ed25519.json")
+
+	testVectors(t, Secp256k1(), "testdata/vectors-secp256k1.json")
+}
+
+func testDKG(t *testing.T, suite Ciphersuite, threshold, count int) []*KeyShare {
+	dkgs := make([]*DKG, count)
+
+	var commitments []*DKGCommitment
+
+	for i := range dkgs {
+		dkg, err := NewDKG(suite, i+1, threshold, count)
+
+		require.NoError(t, err)
+
+		dkgs[i] = dkg
+
+		commitment, err := dkg.Round1()
+
+		require.NoError(t, err)
+
+		commitments = append(commitments, commitment)
+	}
+
+	received := make(map[int][]*DKGShare)
+
+	for i, dkg := range dkgs {
+		var others []*DKGCommitment
+
+		for j, commitment := range commitments {
+			if i != j {
+				others = append(others, commitment)
+			}
+		}
+
+		shares, err := dkg.Round2(others)
+
+		require.NoError(t, err)
+
+		for _, share := range shares {
+			received[share.To] = append(received[share.To], share)
+		}
+	}
+
+	keyShares := make([]*KeyShare, count)
+
+	for i, dkg := range dkgs {
+		share, err := dkg.Finish(received[i+1])
+
+		require.NoError(t, err)
+
+		keyShares[i] = share
+	}
+
+	for _, share := range keyShares {
+		require.Equal(t, keyShares[0].GroupKey, share.GroupKey)
+	}
+
+	return keyShares
+}
+
+func testSign(t *testing.T, suite Ciphersuite, shares []*KeyShare, signers []int, message []byte) []byte {
+	coordinator, err := NewCoordinator(suite, &shares[0].GroupKey)
+
+	require.NoError(t, err)
+
+	participants := make(map[int]*Participant)
+
+	var commitments []*Commitment
+
+	for _, id := range signers {
+		participant, err := NewParticipant(suite, shares[id-1])
+
+		require.NoError(t, err)
+
+		participants[id] = participant
+
+		commitment, err := participant.Commit()
+
+		require.NoError(t, err)
+
+		commitments = append(commitments, commitment)
+	}
+
+	pkg, err := coordinator.SigningPackage(message, commitments)
+
+	require.NoError(t, err)
+
+	// the signing package travels as json
+	buff, err := json.Marshal(pkg)
+
+	require.NoError(t, err)
+
+	for _, id := range signers {
+		received := new(SigningPackage)
+
+		require.NoError(t, json.Unmarshal(buff, received))
+
+		share, err := participants[id].Sign(received)
+
+		require.NoError(t, err)
+
+		require.NoError(t, coordinator.AddShare(share))
+
+		// the nonces are used once
+		_, err = participants[id].Sign(received)
+
+		require.Error(t, err)
+	}
+
+	sig, err := coordinator.Signature()
+
+	require.NoError(t, err)
+
+	require.True(t, Verify(suite, shares[0].PublicKey, message, sig))
+
+	require.False(t, Verify(suite, shares[0].PublicKey, append(message, 0), sig))
+
+	return sig
+}
+
+func TestDKGSign(t *testing.T) {
+	message := []byte("frost threshold signature")
+
+	for _, suite := range []Ciphersuite{Ed25519(), Secp256k1(), Taproot()} {
+		shares := testDKG(t, suite, 2, 3)
+
+		for _, signers := range [][]int{{1, 2}, {1, 3}, {2, 3}, {1, 2, 3}} {
+			sig := testSign(t, suite, shares, signers, message)
+
+			switch suite.(type) {
+			case ed25519Suite:
+				require.True(t, ed25519.Verify(shares[0].PublicKey, message, sig))
+
+				ok, err := key.Verify("solana", shares[0].PublicKey, sig, message)
+
+				require.NoError(t, err)
+
+				require.True(t, ok)
+			case taprootSuite:
+				ok, err := key.Verify("taproot", shares[0].PublicKey, sig, message)
+
+				require.NoError(t, err)
+
+				require.True(t, ok)
+			}
+		}
+
+		// key share persistence
+		buff, err := json.Marshal(shares[0])
+
+		require.NoError(t, err)
+
+		share := new(KeyShare)
+
+		require.NoError(t, json.Unmarshal(buff, share))
+
+		require.Equal(t, shares[0], share)
+	}
+}
+
+func TestTaproot(t *testing.T) {
+	suite := Taproot()
+
+	message := make([]byte, 32)
+
+	// both y parities of the group key and the output key
+	for i := 0; i < 8; i++ {
+		shares, err := TrustedDealerKeygen(suite, nil, 3, 5)
+
+		require.NoError(t, err)
+
+		sig := testSign(t, suite, shares, []int{5, 1, 3}, message)
+
+		ok, err := key.Verify("taproot", shares[0].PublicKey, sig, message)
+
+		require.NoError(t, err)
+
+		require.True(t, ok)
+	}
+}
+
+func TestMisbehaviour(t *testing.T) {
+	suite := Secp256k1()
+
+	shares, err := TrustedDealerKeygen(suite, nil, 2, 3)
+
+	require.NoError(t, err)
+
+	p1, err := NewParticipant(suite, shares[0])
+
+	require.NoError(t, err)
+
+	p2, err := NewParticipant(suite, shares[1])
+
+	require.NoError(t, err)
+
+	_, err = p1.Sign(&SigningPackage{})
+
+	require.Error(t, err)
+
+	c1, err := p1.Commit()
+
+	require.NoError(t, err)
+
+	c2, err := p2.Commit()
+
+	require.NoError(t, err)
+
+	coordinator, err := NewCoordinator(suite, &shares[0].GroupKey)
+
+	require.NoError(t, err)
+
+	// not enough signers
+	_, err = coordinator.SigningPackage([]byte("msg"), []*Commitment{c1})
+
+	require.Error(t, err)
+
+	pkg, err := coordinator.SigningPackage([]byte("msg"), []*Commitment{c1, c2})
+
+	require.NoError(t, err)
+
+	share1, err := p1.Sign(pkg)
+
+	require.NoError(t, err)
+
+	share2, err := p2.Sign(pkg)
+
+	require.NoError(t, err)
+
+	// the cheating signer is identified
+	share2.Share[31] ^= 1
+
+	err = coordinator.AddShare(share2)
+
+	require.Error(t, err)
+
+	require.Contains(t, err.Error(), "of 2 verify failed")
+
+	require.NoError(t, coordinator.AddShare(share1))
+
+	_, err = coordinator.Signature()
+
+	require.Error(t, err)
+
+	// the signing package must carry the signer's own commitment
+	_, err = p1.Commit()
+
+	require.NoError(t, err)
+
+	_, err = p1.Sign(pkg)
+
+	require.Error(t, err)
+
+	// wrong threshold
+	_, err = TrustedDealerKeygen(suite, nil, 4, 3)
+
+	require.Error(t, err)
+
+	// dkg proof of knowledge and shares are checked
+	dkgs := make([]*DKG, 2)
+
+	for i := range dkgs {
+		dkgs[i], err = NewDKG(suite, i+1, 2, 2)
+
+		require.NoError(t, err)
+	}
+
+	commitment, err := dkgs[1].Round1()
+
+	require.NoError(t, err)
+
+	forged := *commitment
+
+	forged.Mu = suite.encodeScalar(big.NewInt(1))
+
+	_, err = dkgs[0].Round2([]*DKGCommitment{&forged})
+
+	require.Error(t, err)
+
+	_, err = dkgs[0].Round2([]*DKGCommitment{commitment})
+
+	require.NoError(t, err)
+
+	_, err = dkgs[0].Finish([]*DKGShare{{From: 2, To: 1, Share: suite.encodeScalar(big.NewInt(1))}})
+
+	require.Error(t, err)
+}
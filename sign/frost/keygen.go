@@ -0,0 +1,439 @@
+package frost
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrProof = errors.New("invalid proof of knowledge")
+)
+
+// GroupKey the group public key and the public shares of the participants
+type GroupKey struct {
+	Threshold int            `json:"threshold"`
+	PublicKey []byte         `json:"publickey"`
+	Shares    map[int][]byte `json:"shares"` // secret share * G of each participant
+}
+
+// KeyShare participant share of the group key
+type KeyShare struct {
+	ID     int    `json:"id"`
+	Secret []byte `json:"secret"` // shamir share of the group secret key
+	GroupKey
+}
+
+func (key *GroupKey) decode(suite Ciphersuite) (element, map[int]element, error) {
+	if key.Threshold < 1 || key.Threshold > len(key.Shares) {
+		return nil, nil, xerrors.Wrapf(ErrParticipants, "threshold %d of %d participants", key.Threshold, len(key.Shares))
+	}
+
+	publicKey, err := suite.decodeElement(key.PublicKey)
+
+	if err != nil {
+		return nil, nil, xerrors.Wrapf(err, "decode group public key error")
+	}
+
+	shares := make(map[int]element, len(key.Shares))
+
+	for id, buff := range key.Shares {
+		if id < 1 {
+			return nil, nil, xerrors.Wrapf(ErrParticipants, "participant id %d must be positive", id)
+		}
+
+		if shares[id], err = suite.decodeElement(buff); err != nil {
+			return nil, nil, xerrors.Wrapf(err, "decode public share of %d error", id)
+		}
+	}
+
+	return publicKey, shares, nil
+}
+
+func (share *KeyShare) decode(suite Ciphersuite) (*big.Int, element, error) {
+	publicKey, shares, err := share.GroupKey.decode(suite)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secret, err := suite.decodeScalar(share.Secret)
+
+	if err != nil {
+		return nil, nil, xerrors.Wrapf(err, "decode secret share error")
+	}
+
+	if public, ok := shares[share.ID]; !ok || !suite.baseMult(secret).equal(public) {
+		return nil, nil, xerrors.Wrapf(ErrParticipants, "secret share of %d does not match its public share", share.ID)
+	}
+
+	return secret, publicKey, nil
+}
+
+func checkThreshold(threshold, count int) error {
+	if threshold < 1 || threshold > count {
+		return xerrors.Wrapf(ErrParticipants, "threshold %d of %d participants", threshold, count)
+	}
+
+	return nil
+}
+
+func randomScalar(suite Ciphersuite) (*big.Int, error) {
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(suite.order(), big.NewInt(1)))
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "read random error")
+	}
+
+	return k.Add(k, big.NewInt(1)), nil
+}
+
+// randomPolynomial random polynomial of degree threshold - 1 with constant term secret
+func randomPolynomial(suite Ciphersuite, secret *big.Int, threshold int) ([]*big.Int, error) {
+	coefficients := []*big.Int{secret}
+
+	for i := 1; i < threshold; i++ {
+		a, err := randomScalar(suite)
+
+		if err != nil {
+			return nil, err
+		}
+
+		coefficients = append(coefficients, a)
+	}
+
+	return coefficients, nil
+}
+
+func evaluate(suite Ciphersuite, coefficients []*big.Int, x int) *big.Int {
+	result := new(big.Int)
+
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result.Mul(result, big.NewInt(int64(x)))
+		result.Add(result, coefficients[i])
+		result.Mod(result, suite.order())
+	}
+
+	return result
+}
+
+// evaluateCommitments f(x) * G from the commitments of the coefficients
+func evaluateCommitments(suite Ciphersuite, commitments []element, x int) element {
+	result := suite.identity()
+
+	for i := len(commitments) - 1; i >= 0; i-- {
+		result = result.mult(big.NewInt(int64(x))).add(commitments[i])
+	}
+
+	return result
+}
+
+// deal shamir split the secret with the coefficients to participants 1 to count
+func deal(suite Ciphersuite, coefficients []*big.Int, threshold, count int) []*KeyShare {
+	key := GroupKey{
+		Threshold: threshold,
+		PublicKey: suite.encodeElement(suite.baseMult(coefficients[0])),
+		Shares:    make(map[int][]byte, count),
+	}
+
+	secrets := make([]*big.Int, count)
+
+	for i := range secrets {
+		secrets[i] = evaluate(suite, coefficients, i+1)
+
+		key.Shares[i+1] = suite.encodeElement(suite.baseMult(secrets[i]))
+	}
+
+	shares := make([]*KeyShare, count)
+
+	for i, secret := range secrets {
+		shares[i] = &KeyShare{
+			ID:       i + 1,
+			Secret:   suite.encodeScalar(secret),
+			GroupKey: key,
+		}
+	}
+
+	return shares
+}
+
+// TrustedDealerKeygen split the group secret key to count participants 1 to count, threshold of them
+// can sign, a random secret is generated if secret is nil
+func TrustedDealerKeygen(suite Ciphersuite, secret []byte, threshold, count int) ([]*KeyShare, error) {
+	if err := checkThreshold(threshold, count); err != nil {
+		return nil, err
+	}
+
+	var s *big.Int
+	var err error
+
+	if secret == nil {
+		s, err = randomScalar(suite)
+	} else {
+		s, err = suite.decodeScalar(secret)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Sign() == 0 {
+		return nil, xerrors.Wrapf(ErrEncoding, "group secret key is zero")
+	}
+
+	coefficients, err := randomPolynomial(suite, s, threshold)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return deal(suite, coefficients, threshold, count), nil
+}
+
+// DKGCommitment dkg round one broadcast message, the commitments of the polynomial coefficients
+// and the schnorr proof of knowledge (R, mu) of the constant term
+type DKGCommitment struct {
+	ID          int      `json:"id"`
+	Commitments [][]byte `json:"commitments"`
+	R           []byte   `json:"r"`
+	Mu          []byte   `json:"mu"`
+}
+
+// DKGShare dkg round two point to point message, the secret share must be sent over a confidential
+// and authenticated channel
+type DKGShare struct {
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Share []byte `json:"share"`
+}
+
+// DKG participant state machine of the two rounds distributed key generation of the FROST paper,
+// every participant deals a random secret, the group secret key is the sum of them
+type DKG struct {
+	suite       Ciphersuite
+	id          int
+	threshold   int
+	count       int
+	polynomial  []*big.Int
+	commitments map[int][]element
+}
+
+// NewDKG create dkg state machine of participant id out of participants 1 to count
+func NewDKG(suite Ciphersuite, id, threshold, count int) (*DKG, error) {
+	if err := checkThreshold(threshold, count); err != nil {
+		return nil, err
+	}
+
+	if id < 1 || id > count {
+		return nil, xerrors.Wrapf(ErrParticipants, "participant id %d out of 1 to %d", id, count)
+	}
+
+	secret, err := randomScalar(suite)
+
+	if err != nil {
+		return nil, err
+	}
+
+	polynomial, err := randomPolynomial(suite, secret, threshold)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &DKG{
+		suite:      suite,
+		id:         id,
+		threshold:  threshold,
+		count:      count,
+		polynomial: polynomial,
+	}, nil
+}
+
+// proofChallenge Hdkg(id || a0 * G || R)
+func proofChallenge(suite Ciphersuite, id int, a0, r element) *big.Int {
+	m := append(suite.encodeScalar(big.NewInt(int64(id))), suite.encodeElement(a0)...)
+
+	return suite.hdkg(append(m, suite.encodeElement(r)...))
+}
+
+// Round1 the commitment message broadcast to the other participants
+func (dkg *DKG) Round1() (*DKGCommitment, error) {
+	suite := dkg.suite
+
+	k, err := randomScalar(suite)
+
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &DKGCommitment{ID: dkg.id}
+
+	for _, a := range dkg.polynomial {
+		msg.Commitments = append(msg.Commitments, suite.encodeElement(suite.baseMult(a)))
+	}
+
+	r := suite.baseMult(k)
+
+	c := proofChallenge(suite, dkg.id, suite.baseMult(dkg.polynomial[0]), r)
+
+	// mu = k + a0 * c
+	mu := new(big.Int).Mul(dkg.polynomial[0], c)
+
+	msg.R = suite.encodeElement(r)
+	msg.Mu = suite.encodeScalar(mu.Add(mu, k))
+
+	return msg, nil
+}
+
+// Round2 verify the commitment messages of all the other participants, return the secret shares
+// sent to each of them
+func (dkg *DKG) Round2(msgs []*DKGCommitment) ([]*DKGShare, error) {
+	suite := dkg.suite
+
+	commitments := make(map[int][]element, dkg.count)
+
+	for _, msg := range msgs {
+		if msg == nil || msg.ID < 1 || msg.ID > dkg.count || msg.ID == dkg.id || commitments[msg.ID] != nil {
+			return nil, xerrors.Wrapf(ErrParticipants, "unexpected dkg commitment")
+		}
+
+		if len(msg.Commitments) != dkg.threshold {
+			return nil, xerrors.Wrapf(ErrCommitment, "participant %d commits %d coefficients", msg.ID, len(msg.Commitments))
+		}
+
+		var points []element
+
+		for _, buff := range msg.Commitments {
+			point, err := suite.decodeElement(buff)
+
+			if err != nil {
+				return nil, xerrors.Wrapf(err, "decode commitment of %d error", msg.ID)
+			}
+
+			points = append(points, point)
+		}
+
+		r, err := suite.decodeElement(msg.R)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "decode proof of %d error", msg.ID)
+		}
+
+		mu, err := suite.decodeScalar(msg.Mu)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "decode proof of %d error", msg.ID)
+		}
+
+		// R = mu * G - c * a0 * G
+		c := proofChallenge(suite, msg.ID, points[0], r)
+
+		if !suite.baseMult(mu).equal(r.add(points[0].mult(c))) {
+			return nil, xerrors.Wrapf(ErrProof, "proof of knowledge of %d verify failed", msg.ID)
+		}
+
+		commitments[msg.ID] = points
+	}
+
+	if len(commitments) != dkg.count-1 {
+		return nil, xerrors.Wrapf(ErrParticipants, "received %d dkg commitments, expect %d", len(commitments), dkg.count-1)
+	}
+
+	var own []element
+
+	for _, a := range dkg.polynomial {
+		own = append(own, suite.baseMult(a))
+	}
+
+	commitments[dkg.id] = own
+
+	dkg.commitments = commitments
+
+	var shares []*DKGShare
+
+	for id := 1; id <= dkg.count; id++ {
+		if id == dkg.id {
+			continue
+		}
+
+		shares = append(shares, &DKGShare{
+			From:  dkg.id,
+			To:    id,
+			Share: suite.encodeScalar(evaluate(suite, dkg.polynomial, id)),
+		})
+	}
+
+	return shares, nil
+}
+
+// Finish verify the secret shares from all the other participants against their commitments and
+// return the key share
+func (dkg *DKG) Finish(shares []*DKGShare) (*KeyShare, error) {
+	suite := dkg.suite
+
+	if dkg.commitments == nil || dkg.polynomial == nil {
+		return nil, xerrors.Wrapf(ErrCommitment, "dkg round two not finished or dkg already finished")
+	}
+
+	secret := evaluate(suite, dkg.polynomial, dkg.id)
+
+	received := make(map[int]bool, dkg.count)
+
+	for _, share := range shares {
+		if share == nil || share.To != dkg.id || share.From == dkg.id || dkg.commitments[share.From] == nil || received[share.From] {
+			return nil, xerrors.Wrapf(ErrParticipants, "unexpected dkg share")
+		}
+
+		s, err := suite.decodeScalar(share.Share)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "decode share of %d error", share.From)
+		}
+
+		if !suite.baseMult(s).equal(evaluateCommitments(suite, dkg.commitments[share.From], dkg.id)) {
+			return nil, xerrors.Wrapf(ErrShare, "secret share from %d verify failed", share.From)
+		}
+
+		secret.Add(secret, s)
+
+		received[share.From] = true
+	}
+
+	if len(received) != dkg.count-1 {
+		return nil, xerrors.Wrapf(ErrParticipants, "received %d dkg shares, expect %d", len(received), dkg.count-1)
+	}
+
+	publicKey := suite.identity()
+
+	for _, commitments := range dkg.commitments {
+		publicKey = publicKey.add(commitments[0])
+	}
+
+	key := GroupKey{
+		Threshold: dkg.threshold,
+		PublicKey: suite.encodeElement(publicKey),
+		Shares:    make(map[int][]byte, dkg.count),
+	}
+
+	for id := 1; id <= dkg.count; id++ {
+		public := suite.identity()
+
+		for _, commitments := range dkg.commitments {
+			public = public.add(evaluateCommitments(suite, commitments, id))
+		}
+
+		key.Shares[id] = suite.encodeElement(public)
+	}
+
+	// erase the polynomial, the dkg is done
+	dkg.polynomial = nil
+
+	return &KeyShare{
+		ID:       dkg.id,
+		Secret:   suite.encodeScalar(secret.Mod(secret, suite.order())),
+		GroupKey: key,
+	}, nil
+}
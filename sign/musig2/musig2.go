@@ -0,0 +1,254 @@
+// Package musig2 bip327 MuSig2 n-of-n multi-signature over secp256k1, the signers aggregate their
+// public keys and nonces, and the partial signatures aggregate into an ordinary bip340 schnorr
+// signature of the aggregated x-only public key
+package musig2
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/secp256k1"
+	"github.com/laplacenetwork/key/sign/schnorr"
+)
+
+// Errors
+var (
+	ErrPublicKey  = errors.New("invalid public key")
+	ErrTweak      = errors.New("invalid tweak")
+	ErrSecretKey  = errors.New("invalid secret key")
+	ErrNonce      = errors.New("invalid nonce")
+	ErrNonceReuse = errors.New("secret nonce already used")
+	ErrPartialSig = errors.New("invalid partial signature")
+)
+
+var curve = secp256k1.SECP256K1()
+
+// point affine secp256k1 point, the infinity is (0, 0)
+type point struct {
+	x, y *big.Int
+}
+
+func infinity() *point {
+	return &point{x: new(big.Int), y: new(big.Int)}
+}
+
+func baseMult(k *big.Int) *point {
+	x, y := curve.ScalarBaseMult(bytes32(k))
+
+	return &point{x: x, y: y}
+}
+
+func (p *point) add(q *point) *point {
+	x, y := curve.Add(p.x, p.y, q.x, q.y)
+
+	return &point{x: x, y: y}
+}
+
+func (p *point) mult(k *big.Int) *point {
+	x, y := curve.ScalarMult(p.x, p.y, bytes32(k))
+
+	return &point{x: x, y: y}
+}
+
+func (p *point) neg() *point {
+	if p.isInfinity() {
+		return p
+	}
+
+	return &point{x: p.x, y: new(big.Int).Sub(curve.Params().P, p.y)}
+}
+
+func (p *point) isInfinity() bool {
+	return p.x.Sign() == 0 && p.y.Sign() == 0
+}
+
+func (p *point) hasEvenY() bool {
+	return p.y.Bit(0) == 0
+}
+
+func (p *point) equal(q *point) bool {
+	return p.x.Cmp(q.x) == 0 && p.y.Cmp(q.y) == 0
+}
+
+// xbytes 32 bytes x coordinate
+func (p *point) xbytes() []byte {
+	return bytes32(p.x)
+}
+
+// cbytes 33 bytes compressed encoding
+func (p *point) cbytes() []byte {
+	return secp256k1.CompressPubkey(p.x, p.y)
+}
+
+// cbytesExt compressed encoding with 33 zero bytes for the infinity
+func (p *point) cbytesExt() []byte {
+	if p.isInfinity() {
+		return make([]byte, 33)
+	}
+
+	return p.cbytes()
+}
+
+func cpoint(buff []byte) (*point, error) {
+	x, y, err := secp256k1.DecompressPubkey(buff)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &point{x: x, y: y}, nil
+}
+
+func cpointExt(buff []byte) (*point, error) {
+	if bytes.Equal(buff, make([]byte, 33)) {
+		return infinity(), nil
+	}
+
+	return cpoint(buff)
+}
+
+// bytes32 the scalar mod n as 32 bytes big endian
+func bytes32(k *big.Int) []byte {
+	return new(big.Int).Mod(k, curve.Params().N).FillBytes(make([]byte, 32))
+}
+
+// hashInt tagged hash as integer mod n
+func hashInt(tag string, msgs ...[]byte) *big.Int {
+	k := new(big.Int).SetBytes(schnorr.TaggedHash(tag, msgs...))
+
+	return k.Mod(k, curve.Params().N)
+}
+
+// KeyAggContext the aggregated public key Q = gacc * (a_1 P_1 + ... + a_u P_u) + tacc * G of the
+// individual public keys with the tweaks applied
+type KeyAggContext struct {
+	pubkeys   [][]byte
+	hashKeys  []byte // hash_KeyAgg list(pk_1 || ... || pk_u)
+	secondKey []byte // the first key different from pk_1
+	q         *point
+	gacc      *big.Int
+	tacc      *big.Int
+}
+
+// KeyAgg aggregate the 33 bytes compressed public keys, the order of the keys matters
+func KeyAgg(pubkeys [][]byte) (*KeyAggContext, error) {
+	if len(pubkeys) == 0 {
+		return nil, xerrors.Wrapf(ErrPublicKey, "no public keys")
+	}
+
+	ctx := &KeyAggContext{
+		pubkeys:   pubkeys,
+		hashKeys:  schnorr.TaggedHash("KeyAgg list", pubkeys...),
+		secondKey: make([]byte, 33),
+		q:         infinity(),
+		gacc:      big.NewInt(1),
+		tacc:      new(big.Int),
+	}
+
+	for _, pk := range pubkeys[1:] {
+		if !bytes.Equal(pk, pubkeys[0]) {
+			ctx.secondKey = pk
+			break
+		}
+	}
+
+	for i, pk := range pubkeys {
+		p, err := cpoint(pk)
+
+		if err != nil {
+			return nil, xerrors.Wrapf(ErrPublicKey, "public key %d: %s", i, err)
+		}
+
+		ctx.q = ctx.q.add(p.mult(ctx.coefficient(pk)))
+	}
+
+	if ctx.q.isInfinity() {
+		return nil, xerrors.Wrapf(ErrPublicKey, "aggregated public key is infinity")
+	}
+
+	return ctx, nil
+}
+
+// coefficient key aggregation coefficient a_i, the second distinct key has coefficient 1
+func (ctx *KeyAggContext) coefficient(pk []byte) *big.Int {
+	if bytes.Equal(pk, ctx.secondKey) {
+		return big.NewInt(1)
+	}
+
+	return hashInt("KeyAgg coefficient", ctx.hashKeys, pk)
+}
+
+// sessionCoefficient the coefficient of the signer's public key, which must be one of the keys
+func (ctx *KeyAggContext) sessionCoefficient(pk []byte) (*big.Int, error) {
+	for _, key := range ctx.pubkeys {
+		if bytes.Equal(key, pk) {
+			return ctx.coefficient(pk), nil
+		}
+	}
+
+	return nil, xerrors.Wrapf(ErrPublicKey, "public key is not one of the aggregated keys")
+}
+
+// ApplyTweak return the context with plain or x-only tweak applied, x-only tweak is added to the
+// even y key
+func (ctx *KeyAggContext) ApplyTweak(tweak []byte, xonly bool) (*KeyAggContext, error) {
+	n := curve.Params().N
+
+	if len(tweak) != 32 {
+		return nil, xerrors.Wrapf(ErrTweak, "tweak length %d error", len(tweak))
+	}
+
+	t := new(big.Int).SetBytes(tweak)
+
+	if t.Cmp(n) >= 0 {
+		return nil, xerrors.Wrapf(ErrTweak, "the tweak must be less than n")
+	}
+
+	g := big.NewInt(1)
+
+	q := ctx.q
+
+	if xonly && !q.hasEvenY() {
+		g.Sub(n, g)
+		q = q.neg()
+	}
+
+	q = q.add(baseMult(t))
+
+	if q.isInfinity() {
+		return nil, xerrors.Wrapf(ErrTweak, "the result of tweaking cannot be infinity")
+	}
+
+	tweaked := *ctx
+
+	tweaked.q = q
+	tweaked.gacc = new(big.Int).Mod(new(big.Int).Mul(g, ctx.gacc), n)
+	tweaked.tacc = new(big.Int).Mod(new(big.Int).Add(t, new(big.Int).Mul(g, ctx.tacc)), n)
+
+	return &tweaked, nil
+}
+
+// TaprootTweak return the context of the bip341 output key of the aggregated key as internal key,
+// merkleRoot is empty for key path only output
+func (ctx *KeyAggContext) TaprootTweak(merkleRoot []byte) (*KeyAggContext, error) {
+	tweak, err := schnorr.TapTweak(ctx.XOnly(), merkleRoot)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(ErrTweak, "taproot tweak error: %s", err)
+	}
+
+	return ctx.ApplyTweak(tweak, true)
+}
+
+// XOnly 32 bytes x-only aggregated public key
+func (ctx *KeyAggContext) XOnly() []byte {
+	return ctx.q.xbytes()
+}
+
+// PubKey 33 bytes compressed aggregated public key
+func (ctx *KeyAggContext) PubKey() []byte {
+	return ctx.q.cbytes()
+}
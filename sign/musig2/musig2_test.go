@@ -0,0 +1,346 @@
+package musig2
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/laplacenetwork/key"
+	_ "github.com/laplacenetwork/key/provider"
+	"github.com/laplacenetwork/key/sign/schnorr"
+)
+
+func unhex(t *testing.T, s string) []byte {
+	buff, err := hex.DecodeString(s)
+
+	require.NoError(t, err)
+
+	return buff
+}
+
+func load(t *testing.T, file string, v interface{}) {
+	buff, err := ioutil.ReadFile(file)
+
+	require.NoError(t, err)
+
+	require.NoError(t, json.Unmarshal(buff, v))
+}
+
+func pick(t *testing.T, list []string, indices []int) [][]byte {
+	var result [][]byte
+
+	for _, i := range indices {
+		result = append(result, unhex(t, list[i]))
+	}
+
+	return result
+}
+
+func secNonce(t *testing.T, s string) *SecNonce {
+	buff := unhex(t, s)
+
+	return &SecNonce{
+		k1: new(big.Int).SetBytes(buff[:32]),
+		k2: new(big.Int).SetBytes(buff[32:64]),
+		pk: buff[64:],
+	}
+}
+
+func TestKeyAggVectors(t *testing.T) {
+	var v struct {
+		PubKeys []string `json:"pubkeys"`
+		Tweaks  []string `json:"tweaks"`
+		Valid   []struct {
+			KeyIndices []int  `json:"key_indices"`
+			Expected   string `json:"expected"`
+		} `json:"valid_test_cases"`
+		Error []struct {
+			KeyIndices   []int  `json:"key_indices"`
+			TweakIndices []int  `json:"tweak_indices"`
+			IsXOnly      []bool `json:"is_xonly"`
+		} `json:"error_test_cases"`
+	}
+
+	load(t, "testdata/key_agg_vectors.json", &v)
+
+	for _, c := range v.Valid {
+		ctx, err := KeyAgg(pick(t, v.PubKeys, c.KeyIndices))
+
+		require.NoError(t, err)
+
+		require.Equal(t, unhex(t, c.Expected), ctx.XOnly())
+	}
+
+	for _, c := range v.Error {
+		ctx, err := KeyAgg(pick(t, v.PubKeys, c.KeyIndices))
+
+		for i := 0; err == nil && i < len(c.TweakIndices); i++ {
+			ctx, err = ctx.ApplyTweak(unhex(t, v.Tweaks[c.TweakIndices[i]]), c.IsXOnly[i])
+		}
+
+		require.Error(t, err)
+	}
+}
+
+func TestNonceGen(t *testing.T) {
+	sk := bytes.Repeat([]byte{2}, 32)
+
+	pk := baseMult(new(big.Int).SetBytes(sk)).cbytes()
+
+	random := make([]byte, 32)
+
+	secnonce, pubnonce, err := nonceGen(random, sk, pk, nil, nil, nil)
+
+	require.NoError(t, err)
+
+	require.Equal(t, append(baseMult(secnonce.k1).cbytes(), baseMult(secnonce.k2).cbytes()...), pubnonce)
+
+	require.Equal(t, pk, secnonce.pk)
+
+	// deterministic of the inputs, absent and empty message differ
+	_, again, err := nonceGen(random, sk, pk, nil, nil, nil)
+
+	require.NoError(t, err)
+
+	require.Equal(t, pubnonce, again)
+
+	_, empty, err := nonceGen(random, sk, pk, nil, []byte{}, nil)
+
+	require.NoError(t, err)
+
+	require.NotEqual(t, pubnonce, empty)
+
+	_, _, err = nonceGen(random, sk, pk[1:], nil, nil, nil)
+
+	require.Error(t, err)
+
+	_, _, err = nonceGen(random, sk[1:], pk, nil, nil, nil)
+
+	require.Error(t, err)
+}
+
+func TestSignVerifyVectors(t *testing.T) {
+	var v struct {
+		SK        string   `json:"sk"`
+		PubKeys   []string `json:"pubkeys"`
+		SecNonce  string   `json:"secnonce"`
+		PubNonces []string `json:"pnonces"`
+		AggNonces []string `json:"aggnonces"`
+		Msgs      []string `json:"msgs"`
+		Valid     []struct {
+			KeyIndices    []int  `json:"key_indices"`
+			NonceIndices  []int  `json:"nonce_indices"`
+			AggNonceIndex int    `json:"aggnonce_index"`
+			MsgIndex      int    `json:"msg_index"`
+			SignerIndex   int    `json:"signer_index"`
+			Expected      string `json:"expected"`
+		} `json:"valid_test_cases"`
+	}
+
+	load(t, "testdata/sign_verify_vectors.json", &v)
+
+	for _, c := range v.Valid {
+		ctx, err := KeyAgg(pick(t, v.PubKeys, c.KeyIndices))
+
+		require.NoError(t, err)
+
+		pubnonces := pick(t, v.PubNonces, c.NonceIndices)
+
+		aggnonce, err := NonceAgg(pubnonces)
+
+		require.NoError(t, err)
+
+		require.Equal(t, unhex(t, v.AggNonces[c.AggNonceIndex]), aggnonce)
+
+		session, err := NewSession(ctx, aggnonce, unhex(t, v.Msgs[c.MsgIndex]))
+
+		require.NoError(t, err)
+
+		psig, err := session.Sign(secNonce(t, v.SecNonce), unhex(t, v.SK))
+
+		require.NoError(t, err)
+
+		require.Equal(t, unhex(t, c.Expected), psig)
+
+		pk := unhex(t, v.PubKeys[0])
+
+		require.True(t, session.PartialSigVerify(psig, pubnonces[c.SignerIndex], pk))
+
+		// wrong signer
+		other := pubnonces[(c.SignerIndex+1)%len(pubnonces)]
+
+		require.False(t, session.PartialSigVerify(psig, other, pk))
+	}
+}
+
+func TestTweakVectors(t *testing.T) {
+	var v struct {
+		SK        string   `json:"sk"`
+		PubKeys   []string `json:"pubkeys"`
+		SecNonce  string   `json:"secnonce"`
+		PubNonces []string `json:"pnonces"`
+		AggNonce  string   `json:"aggnonce"`
+		Tweaks    []string `json:"tweaks"`
+		Msg       string   `json:"msg"`
+		Valid     []struct {
+			KeyIndices   []int  `json:"key_indices"`
+			NonceIndices []int  `json:"nonce_indices"`
+			TweakIndices []int  `json:"tweak_indices"`
+			IsXOnly      []bool `json:"is_xonly"`
+			SignerIndex  int    `json:"signer_index"`
+			Expected     string `json:"expected"`
+		} `json:"valid_test_cases"`
+	}
+
+	load(t, "testdata/tweak_vectors.json", &v)
+
+	for _, c := range v.Valid {
+		ctx, err := KeyAgg(pick(t, v.PubKeys, c.KeyIndices))
+
+		require.NoError(t, err)
+
+		for i, index := range c.TweakIndices {
+			ctx, err = ctx.ApplyTweak(unhex(t, v.Tweaks[index]), c.IsXOnly[i])
+
+			require.NoError(t, err)
+		}
+
+		pubnonces := pick(t, v.PubNonces, c.NonceIndices)
+
+		aggnonce, err := NonceAgg(pubnonces)
+
+		require.NoError(t, err)
+
+		require.Equal(t, unhex(t, v.AggNonce), aggnonce)
+
+		session, err := NewSession(ctx, aggnonce, unhex(t, v.Msg))
+
+		require.NoError(t, err)
+
+		psig, err := session.Sign(secNonce(t, v.SecNonce), unhex(t, v.SK))
+
+		require.NoError(t, err)
+
+		require.Equal(t, unhex(t, c.Expected), psig)
+
+		require.True(t, session.PartialSigVerify(psig, pubnonces[c.SignerIndex], unhex(t, v.PubKeys[0])))
+	}
+
+	// the tweak must be less than n
+	ctx, err := KeyAgg(pick(t, v.PubKeys, []int{1, 2, 0}))
+
+	require.NoError(t, err)
+
+	_, err = ctx.ApplyTweak(unhex(t, v.Tweaks[4]), false)
+
+	require.Error(t, err)
+}
+
+func TestSign(t *testing.T) {
+	msg := []byte("musig2 multi-signature message!!")
+
+	var pubkeys, secrets [][]byte
+
+	for i := 0; i < 3; i++ {
+		k, err := key.New("taproot")
+
+		require.NoError(t, err)
+
+		pubkeys = append(pubkeys, k.PublicKey().Compressed())
+
+		secrets = append(secrets, k.PriKey())
+	}
+
+	internal, err := KeyAgg(pubkeys)
+
+	require.NoError(t, err)
+
+	ctx, err := internal.TaprootTweak(nil)
+
+	require.NoError(t, err)
+
+	var secnonces []*SecNonce
+
+	var pubnonces [][]byte
+
+	for i := range pubkeys {
+		secnonce, pubnonce, err := NonceGen(secrets[i], pubkeys[i], ctx.XOnly(), msg, nil)
+
+		require.NoError(t, err)
+
+		secnonces = append(secnonces, secnonce)
+
+		pubnonces = append(pubnonces, pubnonce)
+	}
+
+	aggnonce, err := NonceAgg(pubnonces)
+
+	require.NoError(t, err)
+
+	session, err := NewSession(ctx, aggnonce, msg)
+
+	require.NoError(t, err)
+
+	var psigs [][]byte
+
+	for i := range pubkeys {
+		copied := *secnonces[i]
+
+		psig, err := session.Sign(secnonces[i], secrets[i])
+
+		require.NoError(t, err)
+
+		require.True(t, session.PartialSigVerify(psig, pubnonces[i], pubkeys[i]))
+
+		// the secret nonce and its copies can not sign again
+		_, err = session.Sign(secnonces[i], secrets[i])
+
+		require.Equal(t, ErrNonceReuse, err)
+
+		_, err = session.Sign(&copied, secrets[i])
+
+		require.Equal(t, ErrNonceReuse, err)
+
+		psigs = append(psigs, psig)
+	}
+
+	// partial signature of the other signer
+	require.False(t, session.PartialSigVerify(psigs[0], pubnonces[1], pubkeys[1]))
+
+	sig, err := session.PartialSigAgg(psigs)
+
+	require.NoError(t, err)
+
+	require.Len(t, sig, schnorr.SignatureSize)
+
+	require.True(t, schnorr.Verify(ctx.XOnly(), msg, sig))
+
+	// taproot provider verifies against the output key of the internal key
+	ok, err := key.Verify("taproot", internal.XOnly(), sig, msg)
+
+	require.NoError(t, err)
+
+	require.True(t, ok)
+
+	// invalid partial signature
+	invalid := append([][]byte{}, psigs...)
+
+	invalid[2] = bytes.Repeat([]byte{0xff}, 32)
+
+	_, err = session.PartialSigAgg(invalid)
+
+	require.Error(t, err)
+
+	invalid[2] = make([]byte, 32)
+
+	sig, err = session.PartialSigAgg(invalid)
+
+	require.NoError(t, err)
+
+	require.False(t, schnorr.Verify(ctx.XOnly(), msg, sig))
+}
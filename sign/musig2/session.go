@@ -0,0 +1,318 @@
+package musig2
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/sign/schnorr"
+)
+
+// Sizes
+const (
+	PubNonceSize   = 66
+	AggNonceSize   = 66
+	PartialSigSize = 32
+)
+
+// SecNonce secret nonce of one signing, Sign erases it so it can never sign twice, copies of it
+// share the erased state, it deliberately has no serialized form
+type SecNonce struct {
+	k1 *big.Int
+	k2 *big.Int
+	pk []byte // the signer's public key
+}
+
+// used the nonce was erased by Sign
+func (nonce *SecNonce) used() bool {
+	return nonce.k1.Sign() == 0 || nonce.k2.Sign() == 0
+}
+
+func (nonce *SecNonce) erase() {
+	nonce.k1.SetInt64(0)
+	nonce.k2.SetInt64(0)
+}
+
+func nonceGen(random, sk, pk, aggpk, msg, extra []byte) (*SecNonce, []byte, error) {
+	if len(pk) != 33 {
+		return nil, nil, xerrors.Wrapf(ErrPublicKey, "public key length %d error", len(pk))
+	}
+
+	if sk != nil {
+		if len(sk) != 32 {
+			return nil, nil, xerrors.Wrapf(ErrSecretKey, "secret key length %d error", len(sk))
+		}
+
+		mask := schnorr.TaggedHash("MuSig/aux", random)
+
+		random = make([]byte, 32)
+
+		for i := range random {
+			random[i] = sk[i] ^ mask[i]
+		}
+	}
+
+	var prefixed []byte
+
+	if msg == nil {
+		prefixed = []byte{0}
+	} else {
+		prefixed = make([]byte, 9)
+
+		prefixed[0] = 1
+
+		binary.BigEndian.PutUint64(prefixed[1:], uint64(len(msg)))
+
+		prefixed = append(prefixed, msg...)
+	}
+
+	extraLen := make([]byte, 4)
+
+	binary.BigEndian.PutUint32(extraLen, uint32(len(extra)))
+
+	k := make([]*big.Int, 2)
+
+	for i := range k {
+		k[i] = hashInt("MuSig/nonce", random, []byte{byte(len(pk))}, pk, []byte{byte(len(aggpk))}, aggpk,
+			prefixed, extraLen, extra, []byte{byte(i)})
+
+		if k[i].Sign() == 0 {
+			return nil, nil, xerrors.Wrapf(ErrNonce, "generated nonce is zero")
+		}
+	}
+
+	pubnonce := append(baseMult(k[0]).cbytes(), baseMult(k[1]).cbytes()...)
+
+	return &SecNonce{k1: k[0], k2: k[1], pk: pk}, pubnonce, nil
+}
+
+// NonceGen generate the secret nonce and the 66 bytes public nonce of the signer's 33 bytes public
+// key pk, the secret key, the x-only aggregated key, the message and the extra input are optional
+// and only strengthen the nonce against bad randomness
+func NonceGen(sk, pk, aggpk, msg, extra []byte) (*SecNonce, []byte, error) {
+	random := make([]byte, 32)
+
+	if _, err := rand.Read(random); err != nil {
+		return nil, nil, xerrors.Wrapf(err, "read random error")
+	}
+
+	return nonceGen(random, sk, pk, aggpk, msg, extra)
+}
+
+// NonceAgg aggregate the public nonces of all signers
+func NonceAgg(pubnonces [][]byte) ([]byte, error) {
+	var aggnonce []byte
+
+	for j := 0; j < 2; j++ {
+		r := infinity()
+
+		for i, pubnonce := range pubnonces {
+			if len(pubnonce) != PubNonceSize {
+				return nil, xerrors.Wrapf(ErrNonce, "public nonce %d length %d error", i, len(pubnonce))
+			}
+
+			p, err := cpoint(pubnonce[j*33 : (j+1)*33])
+
+			if err != nil {
+				return nil, xerrors.Wrapf(ErrNonce, "public nonce %d: %s", i, err)
+			}
+
+			r = r.add(p)
+		}
+
+		aggnonce = append(aggnonce, r.cbytesExt()...)
+	}
+
+	return aggnonce, nil
+}
+
+// Session signing session of the message with the aggregated key and the aggregated nonce
+type Session struct {
+	ctx *KeyAggContext
+	msg []byte
+	b   *big.Int // nonce coefficient
+	r   *point   // final nonce
+	e   *big.Int // bip340 challenge
+}
+
+// NewSession create signing session, all signers and the aggregator create the same session
+func NewSession(ctx *KeyAggContext, aggnonce []byte, msg []byte) (*Session, error) {
+	if len(aggnonce) != AggNonceSize {
+		return nil, xerrors.Wrapf(ErrNonce, "aggregated nonce length %d error", len(aggnonce))
+	}
+
+	r1, err := cpointExt(aggnonce[:33])
+
+	if err != nil {
+		return nil, xerrors.Wrapf(ErrNonce, "aggregated nonce: %s", err)
+	}
+
+	r2, err := cpointExt(aggnonce[33:])
+
+	if err != nil {
+		return nil, xerrors.Wrapf(ErrNonce, "aggregated nonce: %s", err)
+	}
+
+	b := hashInt("MuSig/noncecoef", aggnonce, ctx.XOnly(), msg)
+
+	// R = R1 + b * R2, or G if it is infinity
+	r := r1.add(r2.mult(b))
+
+	if r.isInfinity() {
+		r = baseMult(big.NewInt(1))
+	}
+
+	return &Session{
+		ctx: ctx,
+		msg: msg,
+		b:   b,
+		r:   r,
+		e:   hashInt("BIP0340/challenge", r.xbytes(), ctx.XOnly(), msg),
+	}, nil
+}
+
+// g the negation factor of the odd y aggregated key
+func (s *Session) g() *big.Int {
+	if s.ctx.q.hasEvenY() {
+		return big.NewInt(1)
+	}
+
+	return new(big.Int).Sub(curve.Params().N, big.NewInt(1))
+}
+
+// Sign partial sign with the secret nonce and the 32 bytes secret key, the secret nonce is erased
+// before signing, so it fails if the nonce is reused
+func (s *Session) Sign(secnonce *SecNonce, sk []byte) ([]byte, error) {
+	n := curve.Params().N
+
+	if secnonce == nil || secnonce.used() {
+		return nil, ErrNonceReuse
+	}
+
+	k1, k2 := new(big.Int).Set(secnonce.k1), new(big.Int).Set(secnonce.k2)
+
+	secnonce.erase()
+
+	if k1.Cmp(n) >= 0 || k2.Cmp(n) >= 0 {
+		return nil, xerrors.Wrapf(ErrNonce, "secret nonce out of range")
+	}
+
+	pubnonce := append(baseMult(k1).cbytes(), baseMult(k2).cbytes()...)
+
+	if !s.r.hasEvenY() {
+		k1.Sub(n, k1)
+		k2.Sub(n, k2)
+	}
+
+	d := new(big.Int).SetBytes(sk)
+
+	if len(sk) != 32 || d.Sign() == 0 || d.Cmp(n) >= 0 {
+		return nil, xerrors.Wrapf(ErrSecretKey, "secret key out of range")
+	}
+
+	pk := baseMult(d).cbytes()
+
+	if !bytes.Equal(pk, secnonce.pk) {
+		return nil, xerrors.Wrapf(ErrSecretKey, "secret key does not match the public key of the nonce")
+	}
+
+	a, err := s.ctx.sessionCoefficient(pk)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// s = k1 + b * k2 + e * a * g * gacc * d
+	d.Mul(d, s.g())
+	d.Mul(d, s.ctx.gacc)
+	d.Mul(d, a)
+	d.Mul(d, s.e)
+
+	k2.Mul(k2, s.b)
+
+	psig := bytes32(d.Add(d, k1.Add(k1, k2)))
+
+	if !s.PartialSigVerify(psig, pubnonce, pk) {
+		return nil, xerrors.Wrapf(ErrPartialSig, "generated partial signature verify failed")
+	}
+
+	return psig, nil
+}
+
+// PartialSigVerify verify the partial signature of the signer's public nonce and public key,
+// s * G = Re + e * a * g * gacc * P
+func (s *Session) PartialSigVerify(psig, pubnonce, pk []byte) bool {
+	if len(psig) != PartialSigSize || len(pubnonce) != PubNonceSize {
+		return false
+	}
+
+	z := new(big.Int).SetBytes(psig)
+
+	if z.Cmp(curve.Params().N) >= 0 {
+		return false
+	}
+
+	r1, err := cpoint(pubnonce[:33])
+
+	if err != nil {
+		return false
+	}
+
+	r2, err := cpoint(pubnonce[33:])
+
+	if err != nil {
+		return false
+	}
+
+	re := r1.add(r2.mult(s.b))
+
+	if !s.r.hasEvenY() {
+		re = re.neg()
+	}
+
+	p, err := cpoint(pk)
+
+	if err != nil {
+		return false
+	}
+
+	a, err := s.ctx.sessionCoefficient(pk)
+
+	if err != nil {
+		return false
+	}
+
+	k := new(big.Int).Mul(s.e, a)
+
+	k.Mul(k, s.g())
+	k.Mul(k, s.ctx.gacc)
+
+	return baseMult(z).equal(re.add(p.mult(k)))
+}
+
+// PartialSigAgg aggregate the partial signatures into the 64 bytes bip340 signature
+func (s *Session) PartialSigAgg(psigs [][]byte) ([]byte, error) {
+	n := curve.Params().N
+
+	z := new(big.Int)
+
+	for i, psig := range psigs {
+		k := new(big.Int).SetBytes(psig)
+
+		if len(psig) != PartialSigSize || k.Cmp(n) >= 0 {
+			return nil, xerrors.Wrapf(ErrPartialSig, "partial signature %d out of range", i)
+		}
+
+		z.Add(z, k)
+	}
+
+	// s = s_1 + ... + s_u + e * g * tacc
+	t := new(big.Int).Mul(s.e, s.g())
+
+	z.Add(z, t.Mul(t, s.ctx.tacc))
+
+	return append(s.r.xbytes(), bytes32(z)...), nil
+}
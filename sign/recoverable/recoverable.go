@@ -4,54 +4,17 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"errors"
-	"fmt"
 	"math/big"
 
 	"github.com/dynamicgo/xerrors"
+	"github.com/laplacenetwork/key/internal/ecdsax"
 	"github.com/laplacenetwork/key/sign"
 	"github.com/laplacenetwork/key/sign/rfc6979"
 )
 
-func isOdd(a *big.Int) bool {
-	return a.Bit(0) == 1
-}
-
+// decompressPoint compute y of x on curve y^2 = x^3 + ax + b, a is -3 unless the curve implements CurveA
 func decompressPoint(curve elliptic.Curve, x *big.Int, ybit bool) (*big.Int, error) {
-	// TODO: This will probably only work for secp256k1 due to
-	// optimizations.
-
-	// Y = +-sqrt(x^3 + B)
-	x3 := new(big.Int).Mul(x, x)
-	x3.Mul(x3, x)
-	x3.Add(x3, curve.Params().B)
-	x3.Mod(x3, curve.Params().P)
-
-	q := new(big.Int).Div(new(big.Int).Add(curve.Params().P,
-		big.NewInt(1)), big.NewInt(4))
-
-	// Now calculate sqrt mod p of x^3 + B
-	// This code used to do a full sqrt based on tonelli/shanks,
-	// but this was replaced by the algorithms referenced in
-	// https://bitcointalk.org/index.php?topic=162805.msg1712294#msg1712294
-	y := new(big.Int).Exp(x3, q, curve.Params().P)
-
-	if ybit != isOdd(y) {
-		y.Sub(curve.Params().P, y)
-	}
-
-	// Check that y is a square root of x^3 + B.
-	y2 := new(big.Int).Mul(y, y)
-	y2.Mod(y2, curve.Params().P)
-	if y2.Cmp(x3) != 0 {
-		return nil, fmt.Errorf("invalid square root")
-	}
-
-	// Verify that y-coord has expected parity.
-	if ybit != isOdd(y) {
-		return nil, fmt.Errorf("ybit doesn't match oddness")
-	}
-
-	return y, nil
+	return ecdsax.DecompressY(curve, x, ybit)
 }
 
 // ErrCurve .
@@ -65,13 +28,44 @@ type Cofactor interface {
 	H() int
 }
 
+// CurveA elliptic.CurveParams extend interface for curves whose a parameter is not -3
+type CurveA interface {
+	A() *big.Int
+}
+
+// CombinedMult elliptic.Curve extend interface computing baseScalar * G + scalar * P in one pass,
+// e.g. the secp256k1 curve of this module
+type CombinedMult interface {
+	CombinedMult(Px, Py *big.Int, baseScalar, scalar []byte) (x, y *big.Int)
+}
+
+// cofactors of standard library curves, which don't implement Cofactor
+var knownCofactors = map[string]int{
+	"P-224": 1,
+	"P-256": 1,
+	"P-384": 1,
+	"P-521": 1,
+}
+
+func cofactor(curve elliptic.Curve) (int, error) {
+	if c, ok := curve.(Cofactor); ok {
+		return c.H(), nil
+	}
+
+	if h, ok := knownCofactors[curve.Params().Name]; ok {
+		return h, nil
+	}
+
+	return 0, xerrors.Wrapf(ErrCurve, "curve %s not support cofactor params", curve.Params().Name)
+}
+
 // Sign .
 func Sign(privateKey *ecdsa.PrivateKey, hash []byte, compressed bool) (*sign.Signature, error) {
 
-	cofactor, ok := privateKey.Curve.(Cofactor)
+	h, err := cofactor(privateKey.Curve)
 
-	if !ok {
-		return nil, xerrors.Wrapf(ErrCurve, "curve %s not support cofactor params", privateKey.Curve.Params().Name)
+	if err != nil {
+		return nil, err
 	}
 
 	sig, err := rfc6979.Sign(privateKey, hash)
@@ -85,7 +79,7 @@ func Sign(privateKey *ecdsa.PrivateKey, hash []byte, compressed bool) (*sign.Sig
 	// bitcoind checks the bit length of R and S here. The ecdsa signature
 	// algorithm returns R and S mod N therefore they will be the bitsize of
 	// the curve, and thus correctly sized.
-	for i := 0; i < (cofactor.H()+1)*2; i++ {
+	for i := 0; i < (h+1)*2; i++ {
 		pk, err := recoverKeyFromSignature(curve, sig, hash, i, true)
 		if err == nil && pk.X.Cmp(privateKey.X) == 0 && pk.Y.Cmp(privateKey.Y) == 0 {
 
@@ -103,20 +97,27 @@ func Sign(privateKey *ecdsa.PrivateKey, hash []byte, compressed bool) (*sign.Sig
 	return nil, xerrors.Wrapf(err, "can't find v for public key")
 }
 
-// Recover recover public key from sig and hash
+// Recover recover public key from sig and hash, r and s must be in [1, N-1]
 func Recover(curve elliptic.Curve, sig *sign.Signature, hash []byte) (*ecdsa.PublicKey, bool, error) {
+	n := curve.Params().N
+
+	if sig.R == nil || sig.S == nil || sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.Cmp(n) >= 0 || sig.S.Cmp(n) >= 0 {
+		return nil, false, sign.ErrRange
+	}
 
-	v := sig.V.Bytes()
+	iteration, err := sig.RecoveryID()
 
-	iteration := int((v[0] - 27) & ^byte(4))
+	if err != nil {
+		return nil, false, err
+	}
 
 	// The iteration used here was encoded
-	key, err := recoverKeyFromSignature(curve, sig, hash, iteration, false)
+	key, err := recoverKeyFromSignature(curve, sig, hash, int(iteration), false)
 	if err != nil {
 		return nil, false, err
 	}
 
-	return key, ((v[0] - 27) & 4) == 4, nil
+	return key, sig.Compressed(), nil
 }
 
 // hashToInt converts a hash value to an integer. There is some disagreement
@@ -183,22 +184,35 @@ func recoverKeyFromSignature(curve elliptic.Curve, sig *sign.Signature, msg []by
 	// inverse of r (from the signature). We then add them to calculate
 	// Q = r^-1(sR-eG)
 	invr := new(big.Int).ModInverse(sig.R, curve.Params().N)
+	if invr == nil {
+		return nil, errors.New("r has no inverse mod N")
+	}
 
 	// first term.
 	invrS := new(big.Int).Mul(invr, sig.S)
 	invrS.Mod(invrS, curve.Params().N)
-	sRx, sRy := curve.ScalarMult(Rx, Ry, invrS.Bytes())
 
 	// second term.
 	e.Neg(e)
 	e.Mod(e, curve.Params().N)
 	e.Mul(e, invr)
 	e.Mod(e, curve.Params().N)
-	minuseGx, minuseGy := curve.ScalarBaseMult(e.Bytes())
 
-	// TODO: this would be faster if we did a mult and add in one
-	// step to prevent the jacobian conversion back and forth.
-	Qx, Qy := curve.Add(sRx, sRy, minuseGx, minuseGy)
+	var Qx, Qy *big.Int
+
+	if combined, ok := curve.(CombinedMult); ok {
+		// shamir's trick shares the doublings of both terms
+		Qx, Qy = combined.CombinedMult(Rx, Ry, e.Bytes(), invrS.Bytes())
+	} else {
+		sRx, sRy := curve.ScalarMult(Rx, Ry, invrS.Bytes())
+		minuseGx, minuseGy := curve.ScalarBaseMult(e.Bytes())
+
+		Qx, Qy = curve.Add(sRx, sRy, minuseGx, minuseGy)
+	}
+
+	if Qx.Sign() == 0 && Qy.Sign() == 0 {
+		return nil, errors.New("recovered public key is the point at infinity")
+	}
 
 	return &ecdsa.PublicKey{
 		Curve: curve,
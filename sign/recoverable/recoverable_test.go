@@ -2,10 +2,14 @@ package recoverable
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
 	"testing"
 
 	"github.com/laplacenetwork/key/internal/secp256k1"
+	"github.com/laplacenetwork/key/sign"
 	"github.com/stretchr/testify/require"
 )
 
@@ -54,3 +58,64 @@ func TestCompressSignVerify(t *testing.T) {
 	require.Equal(t, publicKey.X, privateKey.PublicKey.X)
 	require.Equal(t, publicKey.Y, privateKey.PublicKey.Y)
 }
+
+func TestP256SignRecover(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	require.NoError(t, err)
+
+	hash := sha256.Sum256([]byte("hello p256"))
+
+	sign, err := Sign(privateKey, hash[:], false)
+
+	require.NoError(t, err)
+
+	require.True(t, sign.Verfiy(&privateKey.PublicKey, hash[:]))
+
+	publicKey, _, err := Recover(privateKey.Curve, sign, hash[:])
+
+	require.NoError(t, err)
+
+	require.Equal(t, publicKey.X, privateKey.PublicKey.X)
+	require.Equal(t, publicKey.Y, privateKey.PublicKey.Y)
+}
+
+func BenchmarkRecover(b *testing.B) {
+	privateKey, _ := ecdsa.GenerateKey(secp256k1.SECP256K1(), rand.Reader)
+
+	hash := sha256.Sum256([]byte("hello rfc6979"))
+
+	sign, _ := Sign(privateKey, hash[:], false)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Recover(privateKey.Curve, sign, hash[:])
+	}
+}
+
+func TestRecoverRange(t *testing.T) {
+	hash := sha256.Sum256([]byte("hello range"))
+
+	for _, curve := range []elliptic.Curve{secp256k1.SECP256K1(), elliptic.P256()} {
+		n := curve.Params().N
+
+		for _, r := range []*big.Int{big.NewInt(0), n, new(big.Int).Add(n, big.NewInt(1))} {
+			for _, v := range []int64{0, 1, 27, 28, 31, 32} {
+				sig := &sign.Signature{R: r, S: big.NewInt(1), V: big.NewInt(v)}
+
+				_, _, err := Recover(curve, sig, hash[:])
+
+				require.Error(t, err)
+			}
+		}
+
+		_, _, err := Recover(curve, &sign.Signature{R: big.NewInt(1), S: n, V: big.NewInt(27)}, hash[:])
+
+		require.Equal(t, sign.ErrRange, err)
+
+		_, _, err = Recover(curve, &sign.Signature{R: big.NewInt(1), S: big.NewInt(1)}, hash[:])
+
+		require.Error(t, err)
+	}
+}
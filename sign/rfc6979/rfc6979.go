@@ -22,6 +22,11 @@ var (
 	oneInitializer = []byte{0x01}
 )
 
+// invertible curves provide constant time inverse modulo the group order, same as crypto/ecdsa
+type invertible interface {
+	Inverse(k *big.Int) *big.Int
+}
+
 // Sign .
 func Sign(privateKey *ecdsa.PrivateKey, hash []byte) (*sign.Signature, error) {
 
@@ -31,8 +36,15 @@ func Sign(privateKey *ecdsa.PrivateKey, hash []byte) (*sign.Signature, error) {
 
 	k := nonceRFC6979(privateKey.Curve, privateKey.D, hash)
 
-	inv := new(big.Int).ModInverse(k, N)
-	r, _ := privateKey.Curve.ScalarBaseMult(k.Bytes())
+	var inv *big.Int
+
+	if curve, ok := privateKey.Curve.(invertible); ok {
+		inv = curve.Inverse(k)
+	} else {
+		inv = new(big.Int).ModInverse(k, N)
+	}
+
+	r, _ := privateKey.Curve.ScalarBaseMult(k.FillBytes(make([]byte, (N.BitLen()+7)/8)))
 	if r.Cmp(N) == 1 {
 		r.Sub(r, N)
 	}
@@ -0,0 +1,84 @@
+package schnorr
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/laplacenetwork/key/internal/secp256k1"
+)
+
+// BatchVerify bip340 batch verification, check (a1s1 + ... + ausu)G = a1R1 + ... + auRu + a1e1P1 + ... + aueuPu
+// with random a2...au in one multi scalar multiplication, return true only if all signatures are valid
+func BatchVerify(xonlys [][]byte, msgs [][]byte, sigs [][]byte) bool {
+	if len(xonlys) != len(msgs) || len(xonlys) != len(sigs) {
+		return false
+	}
+
+	curve := secp256k1.SECP256K1()
+
+	n := curve.Params().N
+
+	xs := make([]*big.Int, 0, 2*len(sigs)+1)
+	ys := make([]*big.Int, 0, 2*len(sigs)+1)
+	scalars := make([][]byte, 0, 2*len(sigs)+1)
+
+	sum := new(big.Int)
+
+	for i, sig := range sigs {
+		if len(sig) != SignatureSize {
+			return false
+		}
+
+		pub, err := LiftX(xonlys[i])
+
+		if err != nil {
+			return false
+		}
+
+		// lift x rejects r >= p and r not on curve
+		r, err := LiftX(sig[:32])
+
+		if err != nil {
+			return false
+		}
+
+		s := new(big.Int).SetBytes(sig[32:])
+
+		if s.Cmp(n) >= 0 {
+			return false
+		}
+
+		e := new(big.Int).SetBytes(TaggedHash("BIP0340/challenge", sig[:32], xonlys[i], msgs[i]))
+
+		e.Mod(e, n)
+
+		a := big.NewInt(1)
+
+		if i > 0 {
+			a, err = rand.Int(rand.Reader, new(big.Int).Sub(n, big.NewInt(1)))
+
+			if err != nil {
+				return false
+			}
+
+			a.Add(a, big.NewInt(1))
+		}
+
+		sum.Add(sum, new(big.Int).Mul(a, s))
+
+		xs = append(xs, r.X, pub.X)
+		ys = append(ys, r.Y, pub.Y)
+		scalars = append(scalars, bytes32(a), bytes32(e.Mod(e.Mul(e, a), n)))
+	}
+
+	// the sum of all terms with -(a1s1 + ... + ausu)G must be the point at infinity
+	sum.Mod(sum.Neg(sum), n)
+
+	xs = append(xs, curve.Params().Gx)
+	ys = append(ys, curve.Params().Gy)
+	scalars = append(scalars, bytes32(sum))
+
+	x, y := secp256k1.MultiScalarMult(xs, ys, scalars)
+
+	return x.Sign() == 0 && y.Sign() == 0
+}
@@ -0,0 +1,97 @@
+package schnorr
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/csv"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/laplacenetwork/key/internal/secp256k1"
+)
+
+func TestBatchVerify(t *testing.T) {
+	file, err := os.Open("testdata/bip340-vectors.csv")
+
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+
+	require.NoError(t, err)
+
+	var pubkeys, msgs, sigs [][]byte
+
+	type invalid struct{ pubkey, msg, sig []byte }
+
+	var invalids []invalid
+
+	for _, record := range records[1:] {
+		pubkey, msg, sig := decodeHex(t, record[2]), decodeHex(t, record[4]), decodeHex(t, record[5])
+
+		if record[6] == "TRUE" {
+			pubkeys = append(pubkeys, pubkey)
+			msgs = append(msgs, msg)
+			sigs = append(sigs, sig)
+		} else {
+			invalids = append(invalids, invalid{pubkey, msg, sig})
+		}
+	}
+
+	require.True(t, BatchVerify(pubkeys, msgs, sigs))
+
+	for _, v := range invalids {
+		require.False(t, BatchVerify(append(pubkeys, v.pubkey), append(msgs, v.msg), append(sigs, v.sig)))
+	}
+
+	require.True(t, BatchVerify(nil, nil, nil))
+
+	require.False(t, BatchVerify(pubkeys, msgs[1:], sigs))
+}
+
+func benchmarkBatch(b *testing.B, size int) ([][]byte, [][]byte, [][]byte) {
+	var pubkeys, msgs, sigs [][]byte
+
+	for i := 0; i < size; i++ {
+		privateKey, _ := ecdsa.GenerateKey(secp256k1.SECP256K1(), rand.Reader)
+
+		msg := make([]byte, 32)
+
+		rand.Read(msg)
+
+		sig, err := Sign(privateKey, msg, nil)
+
+		require.NoError(b, err)
+
+		pubkeys = append(pubkeys, XOnly(&privateKey.PublicKey))
+		msgs = append(msgs, msg)
+		sigs = append(sigs, sig)
+	}
+
+	return pubkeys, msgs, sigs
+}
+
+func BenchmarkVerify64(b *testing.B) {
+	pubkeys, msgs, sigs := benchmarkBatch(b, 64)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for j := range sigs {
+			Verify(pubkeys[j], msgs[j], sigs[j])
+		}
+	}
+}
+
+func BenchmarkBatchVerify64(b *testing.B) {
+	pubkeys, msgs, sigs := benchmarkBatch(b, 64)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		BatchVerify(pubkeys, msgs, sigs)
+	}
+}
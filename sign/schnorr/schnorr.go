@@ -0,0 +1,199 @@
+// Package schnorr bip340 schnorr signature over secp256k1 implement
+package schnorr
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/secp256k1"
+)
+
+// Errors
+var (
+	ErrCurve      = errors.New("schnorr signature only support secp256k1")
+	ErrPrivateKey = errors.New("invalid private key")
+	ErrPublicKey  = errors.New("invalid x-only public key")
+	ErrNonce      = errors.New("generated nonce is zero")
+	ErrVerify     = errors.New("generated signature verify failed")
+)
+
+// Sizes
+const (
+	PublicKeySize = 32
+	SignatureSize = 64
+)
+
+// combinedMult secp256k1 computes baseScalar * G + scalar * P in one pass
+type combinedMult interface {
+	CombinedMult(Px, Py *big.Int, baseScalar, scalar []byte) (x, y *big.Int)
+}
+
+// TaggedHash bip340 tagged hash sha256(sha256(tag) || sha256(tag) || msgs...)
+func TaggedHash(tag string, msgs ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+
+	hasher := sha256.New()
+
+	hasher.Write(tagHash[:])
+	hasher.Write(tagHash[:])
+
+	for _, msg := range msgs {
+		hasher.Write(msg)
+	}
+
+	return hasher.Sum(nil)
+}
+
+func bytes32(v *big.Int) []byte {
+	buff := make([]byte, 32)
+
+	vBytes := v.Bytes()
+
+	copy(buff[32-len(vBytes):], vBytes)
+
+	return buff
+}
+
+// XOnly get the 32 bytes x-only public key
+func XOnly(pub *ecdsa.PublicKey) []byte {
+	return bytes32(pub.X)
+}
+
+// LiftX parse x-only public key to the point with even y
+func LiftX(xonly []byte) (*ecdsa.PublicKey, error) {
+	if len(xonly) != PublicKeySize {
+		return nil, xerrors.Wrapf(ErrPublicKey, "length %d error", len(xonly))
+	}
+
+	x, y, err := secp256k1.DecompressPubkey(append([]byte{0x02}, xonly...))
+
+	if err != nil {
+		return nil, xerrors.Wrapf(ErrPublicKey, "lift x error: %s", err)
+	}
+
+	return &ecdsa.PublicKey{Curve: secp256k1.SECP256K1(), X: x, Y: y}, nil
+}
+
+// Sign bip340 sign message with aux randomness, if aux is nil 32 bytes random aux will be generated
+func Sign(privateKey *ecdsa.PrivateKey, msg []byte, aux []byte) ([]byte, error) {
+	curve := secp256k1.SECP256K1()
+
+	if privateKey.Curve.Params().Name != curve.Params().Name {
+		return nil, ErrCurve
+	}
+
+	n := curve.Params().N
+
+	if privateKey.D.Sign() == 0 || privateKey.D.Cmp(n) >= 0 {
+		return nil, ErrPrivateKey
+	}
+
+	if aux == nil {
+		aux = make([]byte, 32)
+
+		if _, err := io.ReadFull(rand.Reader, aux); err != nil {
+			return nil, xerrors.Wrapf(err, "read aux randomness error")
+		}
+	}
+
+	px, py := curve.ScalarBaseMult(bytes32(privateKey.D))
+
+	d := new(big.Int).Set(privateKey.D)
+
+	if py.Bit(0) == 1 {
+		d.Sub(n, d)
+	}
+
+	t := bytes32(d)
+
+	auxHash := TaggedHash("BIP0340/aux", aux)
+
+	for i := range t {
+		t[i] ^= auxHash[i]
+	}
+
+	pBytes := bytes32(px)
+
+	k := new(big.Int).SetBytes(TaggedHash("BIP0340/nonce", t, pBytes, msg))
+
+	k.Mod(k, n)
+
+	if k.Sign() == 0 {
+		return nil, ErrNonce
+	}
+
+	rx, ry := curve.ScalarBaseMult(bytes32(k))
+
+	if ry.Bit(0) == 1 {
+		k.Sub(n, k)
+	}
+
+	rBytes := bytes32(rx)
+
+	e := new(big.Int).SetBytes(TaggedHash("BIP0340/challenge", rBytes, pBytes, msg))
+
+	e.Mod(e, n)
+
+	s := new(big.Int).Mul(e, d)
+
+	s.Add(s, k)
+	s.Mod(s, n)
+
+	sig := append(rBytes, bytes32(s)...)
+
+	if !Verify(pBytes, msg, sig) {
+		return nil, ErrVerify
+	}
+
+	return sig, nil
+}
+
+// Verify bip340 verify signature with x-only public key
+func Verify(xonly []byte, msg []byte, sig []byte) bool {
+	if len(sig) != SignatureSize {
+		return false
+	}
+
+	pub, err := LiftX(xonly)
+
+	if err != nil {
+		return false
+	}
+
+	curve := secp256k1.SECP256K1()
+
+	r := new(big.Int).SetBytes(sig[:32])
+
+	if r.Cmp(curve.Params().P) >= 0 {
+		return false
+	}
+
+	s := new(big.Int).SetBytes(sig[32:])
+
+	if s.Cmp(curve.Params().N) >= 0 {
+		return false
+	}
+
+	e := new(big.Int).SetBytes(TaggedHash("BIP0340/challenge", sig[:32], xonly, msg))
+
+	e.Mod(e, curve.Params().N)
+
+	// R = sG - eP
+	rx, ry := curve.(combinedMult).CombinedMult(pub.X, pub.Y, bytes32(s), bytes32(e.Sub(curve.Params().N, e)))
+
+	if rx.Sign() == 0 && ry.Sign() == 0 {
+		return false
+	}
+
+	if ry.Bit(0) == 1 {
+		return false
+	}
+
+	return rx.Cmp(r) == 0
+}
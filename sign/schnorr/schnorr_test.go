@@ -0,0 +1,78 @@
+package schnorr
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/laplacenetwork/key/internal/ecdsax"
+	"github.com/laplacenetwork/key/internal/secp256k1"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	buff, err := hex.DecodeString(s)
+
+	require.NoError(t, err)
+
+	return buff
+}
+
+func TestBIP340Vectors(t *testing.T) {
+	file, err := os.Open("testdata/bip340-vectors.csv")
+
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+
+	require.NoError(t, err)
+
+	for _, record := range records[1:] {
+		index, secretKey, publicKey, auxRand, message, signature, result := record[0], record[1], record[2], record[3], record[4], record[5], record[6]
+
+		pubkey := decodeHex(t, publicKey)
+		msg := decodeHex(t, message)
+		sig := decodeHex(t, signature)
+
+		if secretKey != "" {
+			privateKey := ecdsax.BytesToPrivateKey(decodeHex(t, secretKey), secp256k1.SECP256K1())
+
+			require.Equal(t, pubkey, XOnly(&privateKey.PublicKey), index)
+
+			generated, err := Sign(privateKey, msg, decodeHex(t, auxRand))
+
+			require.NoError(t, err, index)
+
+			require.Equal(t, sig, generated, index)
+		}
+
+		require.Equal(t, result == "TRUE", Verify(pubkey, msg, sig), index)
+	}
+}
+
+func TestTweak(t *testing.T) {
+	privateKey := ecdsax.BytesToPrivateKey(decodeHex(t, "B7E151628AED2A6ABF7158809CF4F3C762E7160F38B4DA56A784D9045190CFEF"), secp256k1.SECP256K1())
+
+	for _, merkleRoot := range [][]byte{nil, make([]byte, 32)} {
+		output, _, err := TweakPublicKey(XOnly(&privateKey.PublicKey), merkleRoot)
+
+		require.NoError(t, err)
+
+		tweaked, err := TweakPrivateKey(privateKey, merkleRoot)
+
+		require.NoError(t, err)
+
+		require.Equal(t, output, XOnly(&tweaked.PublicKey))
+
+		sig, err := Sign(tweaked, []byte("hello taproot"), nil)
+
+		require.NoError(t, err)
+
+		require.True(t, Verify(output, []byte("hello taproot"), sig))
+		require.False(t, Verify(XOnly(&privateKey.PublicKey), []byte("hello taproot"), sig))
+	}
+}
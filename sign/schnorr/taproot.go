@@ -0,0 +1,88 @@
+package schnorr
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/ecdsax"
+	"github.com/laplacenetwork/key/internal/secp256k1"
+)
+
+// ErrTweak .
+var ErrTweak = errors.New("invalid taproot tweak")
+
+// TapTweak bip341 tweak hash_TapTweak(xonly || merkleRoot), merkleRoot is empty for key path only output
+func TapTweak(xonly []byte, merkleRoot []byte) ([]byte, error) {
+	tweak := TaggedHash("TapTweak", xonly, merkleRoot)
+
+	if new(big.Int).SetBytes(tweak).Cmp(secp256k1.SECP256K1().Params().N) >= 0 {
+		return nil, xerrors.Wrapf(ErrTweak, "tweak out of range")
+	}
+
+	return tweak, nil
+}
+
+// TweakPublicKey compute bip341 output key Q = P + tG for x-only internal key P,
+// returns x-only output key and the y parity of Q
+func TweakPublicKey(internal []byte, merkleRoot []byte) ([]byte, bool, error) {
+	pub, err := LiftX(internal)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	tweak, err := TapTweak(internal, merkleRoot)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	curve := secp256k1.SECP256K1()
+
+	tx, ty := curve.ScalarBaseMult(tweak)
+
+	qx, qy := curve.Add(pub.X, pub.Y, tx, ty)
+
+	if qx.Sign() == 0 && qy.Sign() == 0 {
+		return nil, false, xerrors.Wrapf(ErrTweak, "output key is infinity")
+	}
+
+	return bytes32(qx), qy.Bit(0) == 1, nil
+}
+
+// TweakPrivateKey compute bip341 tweaked private key, which sign for the output key of TweakPublicKey
+func TweakPrivateKey(privateKey *ecdsa.PrivateKey, merkleRoot []byte) (*ecdsa.PrivateKey, error) {
+	curve := secp256k1.SECP256K1()
+
+	n := curve.Params().N
+
+	if privateKey.D.Sign() == 0 || privateKey.D.Cmp(n) >= 0 {
+		return nil, ErrPrivateKey
+	}
+
+	px, py := curve.ScalarBaseMult(bytes32(privateKey.D))
+
+	d := new(big.Int).Set(privateKey.D)
+
+	if py.Bit(0) == 1 {
+		d.Sub(n, d)
+	}
+
+	tweak, err := TapTweak(bytes32(px), merkleRoot)
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.Add(d, new(big.Int).SetBytes(tweak))
+	d.Mod(d, n)
+
+	if d.Sign() == 0 {
+		return nil, xerrors.Wrapf(ErrTweak, "tweaked private key is zero")
+	}
+
+	return ecdsax.BytesToPrivateKey(bytes32(d), curve), nil
+}
@@ -2,9 +2,30 @@ package sign
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
 	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrRange = errors.New("signature r or s out of range")
+	ErrHighS = errors.New("signature s is not in the lower half order")
+	ErrHash  = errors.New("hash length mismatch the curve order length")
 )
 
+// CheckHash the signed hash must be exactly as long as the curve order, ecdsa silently truncates a longer
+// hash so a signature of h would also verify against h || suffix
+func CheckHash(curve elliptic.Curve, hash []byte) error {
+	if len(hash) != (curve.Params().N.BitLen()+7)/8 {
+		return xerrors.Wrapf(ErrHash, "hash length %d", len(hash))
+	}
+
+	return nil
+}
+
 // Signature .
 type Signature struct {
 	R *big.Int
@@ -16,3 +37,106 @@ type Signature struct {
 func (sign *Signature) Verfiy(publicKey *ecdsa.PublicKey, hash []byte) bool {
 	return ecdsa.Verify(publicKey, hash, sign.R, sign.S)
 }
+
+// VerifyStrict verify the signature rejecting malleable high s, out of range r, s and non canonical v
+func (sign *Signature) VerifyStrict(publicKey *ecdsa.PublicKey, hash []byte) bool {
+	if sign.Check(publicKey.Curve) != nil {
+		return false
+	}
+
+	return sign.Verfiy(publicKey, hash)
+}
+
+// IsLowS check s is in the lower half order of curve
+func (sign *Signature) IsLowS(curve elliptic.Curve) bool {
+	return sign.S != nil && sign.S.Cmp(new(big.Int).Rsh(curve.Params().N, 1)) <= 0
+}
+
+// Check strict check the signature, r and s must be in [1, N-1], s must be in the lower half order
+// and v must be 27/28 or 31/32 with the compressed flag if present
+func (sign *Signature) Check(curve elliptic.Curve) error {
+	n := curve.Params().N
+
+	if sign.R == nil || sign.S == nil || sign.R.Sign() <= 0 || sign.S.Sign() <= 0 || sign.R.Cmp(n) >= 0 || sign.S.Cmp(n) >= 0 {
+		return ErrRange
+	}
+
+	if !sign.IsLowS(curve) {
+		return ErrHighS
+	}
+
+	if sign.V != nil {
+		if !sign.V.IsUint64() || sign.V.Uint64() < uint64(VLegacy) {
+			return xerrors.Wrapf(ErrRecovery, "v %s without offset 27", sign.V)
+		}
+
+		if _, err := sign.RecoveryID(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Normalize get the lower s form of the signature, a high s is replaced by N - s and the recovery id of V
+// is flipped keeping the V offset and compressed flag
+func (sign *Signature) Normalize(curve elliptic.Curve) (*Signature, error) {
+	n := curve.Params().N
+
+	if sign.R == nil || sign.S == nil || sign.R.Sign() <= 0 || sign.S.Sign() <= 0 || sign.R.Cmp(n) >= 0 || sign.S.Cmp(n) >= 0 {
+		return nil, ErrRange
+	}
+
+	normalized := &Signature{
+		R: new(big.Int).Set(sign.R),
+		S: new(big.Int).Set(sign.S),
+	}
+
+	if sign.V != nil {
+		normalized.V = new(big.Int).Set(sign.V)
+	}
+
+	if sign.IsLowS(curve) {
+		return normalized, nil
+	}
+
+	normalized.S.Sub(n, normalized.S)
+
+	if sign.V != nil {
+		recid, err := sign.RecoveryID()
+
+		if err != nil {
+			return nil, xerrors.Wrapf(err, "normalize signature")
+		}
+
+		normalized.V.Sub(normalized.V, big.NewInt(int64(recid)))
+		normalized.V.Add(normalized.V, big.NewInt(int64(recid^1)))
+	}
+
+	return normalized, nil
+}
+
+// NormalizeEncoded convert the high s signature decoded by Decode to the lower s form with flipped
+// recovery id, the normalized signature keeps the encoding of the input signature
+func NormalizeEncoded(curve elliptic.Curve, buff []byte) ([]byte, error) {
+	sig, err := Decode(buff)
+
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := sig.Normalize(curve)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(buff) {
+	case 65:
+		return EncodeRecoverable(normalized, (curve.Params().BitSize+7)/8, VLegacy)
+	case 64:
+		return EncodeCompact(normalized)
+	default:
+		return EncodeDER(normalized)
+	}
+}
@@ -0,0 +1,115 @@
+package sign
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	curve := elliptic.P256()
+
+	privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+
+	require.NoError(t, err)
+
+	hash := sha256.Sum256([]byte("hello"))
+
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash[:])
+
+	require.NoError(t, err)
+
+	n := curve.Params().N
+
+	low := &Signature{R: r, S: s, V: big.NewInt(27)}
+
+	if !low.IsLowS(curve) {
+		low.S = new(big.Int).Sub(n, s)
+	}
+
+	high := &Signature{R: low.R, S: new(big.Int).Sub(n, low.S), V: big.NewInt(32)}
+
+	require.True(t, low.Verfiy(&privateKey.PublicKey, hash[:]))
+	require.True(t, high.Verfiy(&privateKey.PublicKey, hash[:]))
+
+	require.True(t, low.VerifyStrict(&privateKey.PublicKey, hash[:]))
+	require.False(t, high.VerifyStrict(&privateKey.PublicKey, hash[:]))
+
+	require.Equal(t, ErrHighS, high.Check(curve))
+
+	normalized, err := high.Normalize(curve)
+
+	require.NoError(t, err)
+
+	require.Equal(t, low.S, normalized.S)
+	require.Equal(t, big.NewInt(31), normalized.V)
+	require.Equal(t, big.NewInt(32), high.V)
+
+	require.True(t, normalized.VerifyStrict(&privateKey.PublicKey, hash[:]))
+
+	normalized, err = low.Normalize(curve)
+
+	require.NoError(t, err)
+
+	require.Equal(t, low, normalized)
+
+	for _, invalid := range []*Signature{
+		{R: big.NewInt(0), S: big.NewInt(1)},
+		{R: n, S: big.NewInt(1)},
+		{R: big.NewInt(1), S: n},
+		{R: big.NewInt(1), S: big.NewInt(1), V: big.NewInt(29)},
+	} {
+		require.Error(t, invalid.Check(curve))
+	}
+
+	// the encoded signature keeps its encoding
+	highBuff, err := EncodeRecoverable(&Signature{R: high.R, S: high.S, V: big.NewInt(28)}, 32, VLegacy)
+
+	require.NoError(t, err)
+
+	lowBuff, err := EncodeRecoverable(&Signature{R: low.R, S: low.S, V: big.NewInt(27)}, 32, VLegacy)
+
+	require.NoError(t, err)
+
+	normalizedBuff, err := NormalizeEncoded(curve, highBuff)
+
+	require.NoError(t, err)
+
+	require.Equal(t, lowBuff, normalizedBuff)
+
+	// non canonical v
+	require.Error(t, (&Signature{R: low.R, S: low.S, V: big.NewInt(1)}).Check(curve))
+
+	rawBuff, err := EncodeRecoverable(&Signature{R: high.R, S: high.S, V: big.NewInt(28)}, 32, VRaw)
+
+	require.NoError(t, err)
+
+	_, err = NormalizeEncoded(curve, rawBuff)
+
+	require.Error(t, err)
+
+	highDER, err := EncodeDER(high)
+
+	require.NoError(t, err)
+
+	lowDER, err := EncodeDER(low)
+
+	require.NoError(t, err)
+
+	normalizedDER, err := NormalizeEncoded(curve, highDER)
+
+	require.NoError(t, err)
+
+	require.Equal(t, lowDER, normalizedDER)
+
+	decoded, err := Decode(lowDER)
+
+	require.NoError(t, err)
+
+	require.Nil(t, decoded.V)
+}
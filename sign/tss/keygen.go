@@ -0,0 +1,368 @@
+package tss
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/ecdsax"
+	"github.com/laplacenetwork/key/internal/paillier"
+)
+
+// Errors
+var (
+	ErrParties = errors.New("invalid parties or threshold")
+	ErrShare   = errors.New("invalid secret share")
+)
+
+// PaillierBits paillier modulus length
+const PaillierBits = 2048
+
+// keygen rounds
+const (
+	keygenCommit = iota + 1
+	keygenDecommit
+	keygenShare
+	keygenProof
+)
+
+// PreParams the paillier key of safe primes, generating it takes a while so it may be prepared
+// before the key generation
+type PreParams struct {
+	Paillier *paillier.PrivateKey `json:"paillier"`
+}
+
+// GeneratePreParams generate paillier key of safe primes
+func GeneratePreParams() (*PreParams, error) {
+	key, err := paillier.GenerateKey(rand.Reader, PaillierBits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreParams{Paillier: key}, nil
+}
+
+// Peer public parameters of the other party
+type Peer struct {
+	Paillier *paillier.PublicKey    `json:"paillier"`
+	Pedersen *paillier.RingPedersen `json:"pedersen"`
+}
+
+// KeyShare one party share of the threshold key, Threshold parties out of Parties can sign together
+type KeyShare struct {
+	ID        int                    `json:"id"`
+	Threshold int                    `json:"threshold"`
+	Parties   []int                  `json:"parties"`
+	Secret    *big.Int               `json:"secret"`    // shamir share of the private key
+	PublicKey *Point                 `json:"publickey"` // public key
+	Shares    map[int]*Point         `json:"shares"`    // public shares of all parties, secret * G
+	Paillier  *paillier.PrivateKey   `json:"paillier"`
+	Pedersen  *paillier.RingPedersen `json:"pedersen"`
+	Peers     map[int]*Peer          `json:"peers"`
+}
+
+// ECDSAPublicKey the ecdsa public key of the threshold key
+func (share *KeyShare) ECDSAPublicKey() *ecdsa.PublicKey {
+	return &ecdsa.PublicKey{Curve: curve, X: share.PublicKey.X, Y: share.PublicKey.Y}
+}
+
+// PubKey the uncompressed public key bytes, the same format as eth and did PubKey
+func (share *KeyShare) PubKey() []byte {
+	return ecdsax.PublicKeyBytes(share.ECDSAPublicKey())
+}
+
+type keygenCommitMessage struct {
+	Commitment    []byte                  `json:"commitment"`
+	Paillier      *paillier.PublicKey     `json:"paillier"`
+	Pedersen      *paillier.RingPedersen  `json:"pedersen"`
+	ModulusProof  *paillier.ModulusProof  `json:"modulusproof"`
+	PedersenProof *paillier.PedersenProof `json:"pedersenproof"`
+}
+
+type keygenDecommitMessage struct {
+	Commitments []*Point `json:"commitments"`
+	Nonce       []byte   `json:"nonce"`
+}
+
+type keygenShareMessage struct {
+	Share *big.Int `json:"share"` // paillier ciphertext of the share under the recipient key
+}
+
+type keygenProofMessage struct {
+	Proof *schnorrProof `json:"proof"`
+}
+
+func checkParties(id int, parties []int, threshold int) ([]int, error) {
+	sorted := append([]int{}, parties...)
+
+	sort.Ints(sorted)
+
+	found := false
+
+	for i, party := range sorted {
+		if party < 1 || (i > 0 && sorted[i-1] == party) {
+			return nil, xerrors.Wrapf(ErrParties, "party id %d must be unique and positive", party)
+		}
+
+		found = found || party == id
+	}
+
+	if !found {
+		return nil, xerrors.Wrapf(ErrParties, "party %d not in parties", id)
+	}
+
+	if threshold < 1 || threshold > len(sorted) {
+		return nil, xerrors.Wrapf(ErrParties, "threshold %d of %d parties", threshold, len(sorted))
+	}
+
+	return sorted, nil
+}
+
+// Keygen distributed key generation with feldman vss, any threshold of the parties can sign and the
+// private key never exists in one place, preParams is generated if nil
+func Keygen(id int, parties []int, threshold int, preParams *PreParams, transport Transport) (*KeyShare, error) {
+	parties, err := checkParties(id, parties, threshold)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if preParams == nil {
+		if preParams, err = GeneratePreParams(); err != nil {
+			return nil, err
+		}
+	}
+
+	s := newSession(id, parties, transport)
+
+	share, err := keygen(s, threshold, preParams.Paillier)
+
+	if err != nil {
+		return nil, s.abort(err)
+	}
+
+	return share, nil
+}
+
+func keygen(s *session, threshold int, paillierKey *paillier.PrivateKey) (*KeyShare, error) {
+	u, err := randomScalar()
+
+	if err != nil {
+		return nil, err
+	}
+
+	poly, err := newPolynomial(u, threshold)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pedersen, lambda, err := paillierKey.RingPedersen(rand.Reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	pedersenProof, err := paillierKey.ProvePedersen(rand.Reader, pedersen, lambda)
+
+	if err != nil {
+		return nil, err
+	}
+
+	commitments := poly.commitments()
+
+	commitment, nonce, err := commit(commitments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// round 1, commit to the feldman commitments and publish the paillier key
+	err = s.broadcast(keygenCommit, &keygenCommitMessage{
+		Commitment:    commitment,
+		Paillier:      &paillierKey.PublicKey,
+		Pedersen:      pedersen,
+		ModulusProof:  paillierKey.ProveModulus(),
+		PedersenProof: pedersenProof,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	commitMessages := make(map[int]*keygenCommitMessage)
+
+	err = s.receive(keygenCommit, func(from int) interface{} {
+		commitMessages[from] = new(keygenCommitMessage)
+		return commitMessages[from]
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	share := &KeyShare{
+		ID:        s.id,
+		Threshold: threshold,
+		Parties:   append([]int{s.id}, s.peers...),
+		Paillier:  paillierKey,
+		Pedersen:  pedersen,
+		Peers:     make(map[int]*Peer),
+		Shares:    make(map[int]*Point),
+	}
+
+	sort.Ints(share.Parties)
+
+	for from, msg := range commitMessages {
+		if msg.Paillier == nil || msg.Pedersen == nil {
+			return nil, xerrors.Wrapf(ErrMessage, "party %d missing paillier key", from)
+		}
+
+		if err := msg.Paillier.VerifyModulus(msg.ModulusProof); err != nil {
+			return nil, xerrors.Wrapf(err, "party %d", from)
+		}
+
+		if msg.Pedersen.N == nil || msg.Pedersen.N.Cmp(msg.Paillier.N) != 0 {
+			return nil, xerrors.Wrapf(ErrMessage, "party %d ring pedersen modulus mismatch", from)
+		}
+
+		if err := msg.Pedersen.Verify(msg.PedersenProof); err != nil {
+			return nil, xerrors.Wrapf(err, "party %d", from)
+		}
+
+		share.Peers[from] = &Peer{Paillier: msg.Paillier, Pedersen: msg.Pedersen}
+	}
+
+	// round 2, open the feldman commitments and send each party its share encrypted under its paillier key
+	err = s.broadcast(keygenDecommit, &keygenDecommitMessage{Commitments: commitments, Nonce: nonce})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, peer := range s.peers {
+		c, _, err := share.Peers[peer].Paillier.Encrypt(rand.Reader, poly.evaluate(peer))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.send(peer, keygenShare, &keygenShareMessage{Share: c}); err != nil {
+			return nil, err
+		}
+	}
+
+	decommitMessages := make(map[int]*keygenDecommitMessage)
+
+	err = s.receive(keygenDecommit, func(from int) interface{} {
+		decommitMessages[from] = new(keygenDecommitMessage)
+		return decommitMessages[from]
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	shareMessages := make(map[int]*keygenShareMessage)
+
+	err = s.receive(keygenShare, func(from int) interface{} {
+		shareMessages[from] = new(keygenShareMessage)
+		return shareMessages[from]
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	allCommitments := map[int][]*Point{s.id: commitments}
+
+	secret := poly.evaluate(s.id)
+
+	for from, msg := range decommitMessages {
+		if len(msg.Commitments) != threshold {
+			return nil, xerrors.Wrapf(ErrMessage, "party %d feldman commitments count %d", from, len(msg.Commitments))
+		}
+
+		for _, p := range msg.Commitments {
+			if !p.valid() {
+				return nil, xerrors.Wrapf(ErrMessage, "party %d feldman commitment not on curve", from)
+			}
+		}
+
+		if err := checkCommitment(msg.Commitments, msg.Nonce, commitMessages[from].Commitment); err != nil {
+			return nil, xerrors.Wrapf(err, "party %d", from)
+		}
+
+		v, err := paillierKey.Decrypt(shareMessages[from].Share)
+
+		if err != nil || !validScalar(v) {
+			return nil, xerrors.Wrapf(ErrShare, "party %d share decryption", from)
+		}
+
+		if !baseMult(v).equal(evaluateCommitments(msg.Commitments, s.id)) {
+			return nil, xerrors.Wrapf(ErrShare, "party %d share mismatch feldman commitments", from)
+		}
+
+		allCommitments[from] = msg.Commitments
+
+		secret.Add(secret, v)
+	}
+
+	share.Secret = secret.Mod(secret, order())
+
+	// public key and public shares from the feldman commitments of all parties
+	share.PublicKey = identity()
+
+	for _, commitments := range allCommitments {
+		share.PublicKey = share.PublicKey.add(commitments[0])
+	}
+
+	if share.PublicKey.isIdentity() {
+		return nil, xerrors.Wrapf(ErrShare, "public key is the identity")
+	}
+
+	for _, party := range share.Parties {
+		X := identity()
+
+		for _, commitments := range allCommitments {
+			X = X.add(evaluateCommitments(commitments, party))
+		}
+
+		share.Shares[party] = X
+	}
+
+	// round 3, prove knowledge of the secret share
+	proof, err := proveSchnorr(s.id, share.Secret, share.Shares[s.id])
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.broadcast(keygenProof, &keygenProofMessage{Proof: proof}); err != nil {
+		return nil, err
+	}
+
+	proofMessages := make(map[int]*keygenProofMessage)
+
+	err = s.receive(keygenProof, func(from int) interface{} {
+		proofMessages[from] = new(keygenProofMessage)
+		return proofMessages[from]
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for from, msg := range proofMessages {
+		if !msg.Proof.verify(from, share.Shares[from]) {
+			return nil, xerrors.Wrapf(ErrProof, "party %d secret share proof", from)
+		}
+	}
+
+	return share, nil
+}
@@ -0,0 +1,158 @@
+package tss
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/secp256k1"
+)
+
+var curve = secp256k1.SECP256K1()
+
+// Point secp256k1 affine point, the identity is (0, 0)
+type Point struct {
+	X *big.Int `json:"x"`
+	Y *big.Int `json:"y"`
+}
+
+func order() *big.Int {
+	return curve.Params().N
+}
+
+func scalarBytes(k *big.Int) []byte {
+	return new(big.Int).Mod(k, order()).FillBytes(make([]byte, 32))
+}
+
+func baseMult(k *big.Int) *Point {
+	x, y := curve.ScalarBaseMult(scalarBytes(k))
+
+	return &Point{X: x, Y: y}
+}
+
+func (p *Point) mult(k *big.Int) *Point {
+	x, y := curve.ScalarMult(p.X, p.Y, scalarBytes(k))
+
+	return &Point{X: x, Y: y}
+}
+
+func (p *Point) add(q *Point) *Point {
+	x, y := curve.Add(p.X, p.Y, q.X, q.Y)
+
+	return &Point{X: x, Y: y}
+}
+
+func (p *Point) neg() *Point {
+	if p.isIdentity() {
+		return p
+	}
+
+	return &Point{X: p.X, Y: new(big.Int).Sub(curve.Params().P, p.Y)}
+}
+
+func (p *Point) isIdentity() bool {
+	return p.X.Sign() == 0 && p.Y.Sign() == 0
+}
+
+func (p *Point) equal(q *Point) bool {
+	return p.X.Cmp(q.X) == 0 && p.Y.Cmp(q.Y) == 0
+}
+
+// valid check the point decoded from peer message is on curve or the identity
+func (p *Point) valid() bool {
+	return p != nil && p.X != nil && p.Y != nil && (p.isIdentity() || curve.IsOnCurve(p.X, p.Y))
+}
+
+func identity() *Point {
+	return &Point{X: new(big.Int), Y: new(big.Int)}
+}
+
+// randomScalar random scalar in [1, N-1]
+func randomScalar() (*big.Int, error) {
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(order(), big.NewInt(1)))
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "read random error")
+	}
+
+	return k.Add(k, big.NewInt(1)), nil
+}
+
+// validScalar check the scalar decoded from peer message is in [0, N-1]
+func validScalar(k *big.Int) bool {
+	return k != nil && k.Sign() >= 0 && k.Cmp(order()) < 0
+}
+
+// polynomial random polynomial of degree threshold - 1 with constant term secret
+type polynomial []*big.Int
+
+func newPolynomial(secret *big.Int, threshold int) (polynomial, error) {
+	p := polynomial{secret}
+
+	for i := 1; i < threshold; i++ {
+		a, err := randomScalar()
+
+		if err != nil {
+			return nil, err
+		}
+
+		p = append(p, a)
+	}
+
+	return p, nil
+}
+
+func (p polynomial) evaluate(x int) *big.Int {
+	result := new(big.Int)
+
+	for i := len(p) - 1; i >= 0; i-- {
+		result.Mul(result, big.NewInt(int64(x)))
+		result.Add(result, p[i])
+		result.Mod(result, order())
+	}
+
+	return result
+}
+
+// commitments feldman commitments of the coefficients
+func (p polynomial) commitments() []*Point {
+	result := make([]*Point, len(p))
+
+	for i, a := range p {
+		result[i] = baseMult(a)
+	}
+
+	return result
+}
+
+// evaluateCommitments f(x) * G from the feldman commitments
+func evaluateCommitments(commitments []*Point, x int) *Point {
+	result := identity()
+
+	for i := len(commitments) - 1; i >= 0; i-- {
+		result = result.mult(big.NewInt(int64(x))).add(commitments[i])
+	}
+
+	return result
+}
+
+// lagrange coefficient of party id at 0 for the signers
+func lagrange(id int, signers []int) *big.Int {
+	numerator, denominator := big.NewInt(1), big.NewInt(1)
+
+	for _, j := range signers {
+		if j == id {
+			continue
+		}
+
+		numerator.Mul(numerator, big.NewInt(int64(j)))
+		denominator.Mul(denominator, big.NewInt(int64(j-id)))
+	}
+
+	denominator.Mod(denominator, order())
+
+	numerator.Mul(numerator, new(big.Int).ModInverse(denominator, order()))
+
+	return numerator.Mod(numerator, order())
+}
@@ -0,0 +1,410 @@
+package tss
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/internal/paillier"
+)
+
+// Errors
+var (
+	ErrCommitment = errors.New("commitment mismatch")
+	ErrProof      = errors.New("invalid zero knowledge proof")
+)
+
+// commit hash commitment of json encoded value, return the commitment and the decommitment nonce
+func commit(value interface{}) ([]byte, []byte, error) {
+	nonce := make([]byte, 32)
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, xerrors.Wrapf(err, "read random error")
+	}
+
+	commitment, err := commitment(value, nonce)
+
+	return commitment, nonce, err
+}
+
+func commitment(value interface{}, nonce []byte) ([]byte, error) {
+	buff, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "marshal commitment error")
+	}
+
+	hash := sha256.New()
+
+	hash.Write(nonce)
+	hash.Write(buff)
+
+	return hash.Sum(nil), nil
+}
+
+func checkCommitment(value interface{}, nonce []byte, expected []byte) error {
+	actual, err := commitment(value, nonce)
+
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(actual, expected) != 1 {
+		return ErrCommitment
+	}
+
+	return nil
+}
+
+// challenge fiat shamir challenge mod N of the curve
+func challenge(values ...*big.Int) *big.Int {
+	e := paillier.HashInt(256+64, values...)
+
+	return e.Mod(e, order())
+}
+
+func pointValues(points ...*Point) []*big.Int {
+	var values []*big.Int
+
+	for _, p := range points {
+		values = append(values, p.X, p.Y)
+	}
+
+	return values
+}
+
+// schnorrProof proof of knowledge of x with X = xG bound to the prover id
+type schnorrProof struct {
+	A *Point   `json:"a"`
+	Z *big.Int `json:"z"`
+}
+
+func proveSchnorr(id int, x *big.Int, X *Point) (*schnorrProof, error) {
+	a, err := randomScalar()
+
+	if err != nil {
+		return nil, err
+	}
+
+	A := baseMult(a)
+
+	e := challenge(append([]*big.Int{big.NewInt(int64(id))}, pointValues(X, A)...)...)
+
+	z := new(big.Int).Mul(e, x)
+
+	return &schnorrProof{A: A, Z: z.Mod(z.Add(z, a), order())}, nil
+}
+
+func (proof *schnorrProof) verify(id int, X *Point) bool {
+	if proof == nil || !proof.A.valid() || !validScalar(proof.Z) {
+		return false
+	}
+
+	e := challenge(append([]*big.Int{big.NewInt(int64(id))}, pointValues(X, proof.A)...)...)
+
+	return baseMult(proof.Z).equal(proof.A.add(X.mult(e)))
+}
+
+// pedersenProof proof of knowledge of s and l with V = sR + lG
+type pedersenProof struct {
+	A *Point   `json:"a"`
+	T *big.Int `json:"t"`
+	U *big.Int `json:"u"`
+}
+
+func provePedersen(id int, s, l *big.Int, R, V *Point) (*pedersenProof, error) {
+	a, err := randomScalar()
+
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := randomScalar()
+
+	if err != nil {
+		return nil, err
+	}
+
+	A := R.mult(a).add(baseMult(b))
+
+	e := challenge(append([]*big.Int{big.NewInt(int64(id))}, pointValues(R, V, A)...)...)
+
+	t := new(big.Int).Mul(e, s)
+	t.Mod(t.Add(t, a), order())
+
+	u := new(big.Int).Mul(e, l)
+	u.Mod(u.Add(u, b), order())
+
+	return &pedersenProof{A: A, T: t, U: u}, nil
+}
+
+func (proof *pedersenProof) verify(id int, R, V *Point) bool {
+	if proof == nil || !proof.A.valid() || !validScalar(proof.T) || !validScalar(proof.U) {
+		return false
+	}
+
+	e := challenge(append([]*big.Int{big.NewInt(int64(id))}, pointValues(R, V, proof.A)...)...)
+
+	return R.mult(proof.T).add(baseMult(proof.U)).equal(proof.A.add(V.mult(e)))
+}
+
+func randomInt(max *big.Int) (*big.Int, error) {
+	v, err := rand.Int(rand.Reader, max)
+
+	if err != nil {
+		return nil, xerrors.Wrapf(err, "read random error")
+	}
+
+	return v, nil
+}
+
+func pow(q *big.Int, e int) *big.Int {
+	return new(big.Int).Exp(q, big.NewInt(int64(e)), nil)
+}
+
+func inRange(v *big.Int, max *big.Int) bool {
+	return v != nil && v.Sign() >= 0 && v.Cmp(max) <= 0
+}
+
+func unit(v, n *big.Int) bool {
+	return v != nil && v.Sign() > 0 && v.Cmp(n) < 0 && new(big.Int).GCD(nil, nil, v, n).Cmp(big.NewInt(1)) == 0
+}
+
+// rangeProof alice proof that the paillier ciphertext c encrypts m in [0, q^3] with the verifier ring
+// pedersen parameters, https://eprint.iacr.org/2019/114 appendix A.1
+type rangeProof struct {
+	Z  *big.Int `json:"z"`
+	U  *big.Int `json:"u"`
+	W  *big.Int `json:"w"`
+	S  *big.Int `json:"s"`
+	S1 *big.Int `json:"s1"`
+	S2 *big.Int `json:"s2"`
+}
+
+func proveRange(pub *paillier.PublicKey, params *paillier.RingPedersen, c, m, r *big.Int) (*rangeProof, error) {
+	q := order()
+	q3 := pow(q, 3)
+
+	alpha, err := randomInt(q3)
+
+	if err != nil {
+		return nil, err
+	}
+
+	beta, err := paillier.RandomUnit(rand.Reader, pub.N)
+
+	if err != nil {
+		return nil, err
+	}
+
+	gamma, err := randomInt(new(big.Int).Mul(q3, params.N))
+
+	if err != nil {
+		return nil, err
+	}
+
+	rho, err := randomInt(new(big.Int).Mul(q, params.N))
+
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &rangeProof{
+		Z: params.Commit(m, rho),
+		U: pub.EncryptWithNonce(alpha, beta),
+		W: params.Commit(alpha, gamma),
+	}
+
+	e := challenge(pub.N, params.N, params.S, params.T, c, proof.Z, proof.U, proof.W)
+
+	proof.S = new(big.Int).Exp(r, e, pub.N)
+	proof.S.Mod(proof.S.Mul(proof.S, beta), pub.N)
+
+	proof.S1 = new(big.Int).Mul(e, m)
+	proof.S1.Add(proof.S1, alpha)
+
+	proof.S2 = new(big.Int).Mul(e, rho)
+	proof.S2.Add(proof.S2, gamma)
+
+	return proof, nil
+}
+
+func (proof *rangeProof) verify(pub *paillier.PublicKey, params *paillier.RingPedersen, c *big.Int) bool {
+	if proof == nil || !pub.ValidCiphertext(c) || !pub.ValidCiphertext(proof.U) || !unit(proof.S, pub.N) ||
+		!unit(proof.Z, params.N) || !unit(proof.W, params.N) || proof.S2 == nil || proof.S2.Sign() < 0 {
+		return false
+	}
+
+	if !inRange(proof.S1, pow(order(), 3)) {
+		return false
+	}
+
+	e := challenge(pub.N, params.N, params.S, params.T, c, proof.Z, proof.U, proof.W)
+
+	n2 := pub.N2()
+
+	// u = (N + 1)^s1 * s^N * c^-e mod N^2
+	u := pub.EncryptWithNonce(proof.S1, proof.S)
+	u.Mod(u.Mul(u, new(big.Int).Exp(new(big.Int).ModInverse(c, n2), e, n2)), n2)
+
+	if u.Cmp(proof.U) != 0 {
+		return false
+	}
+
+	// w = s^s1 * t^s2 * z^-e mod Ñ
+	w := params.Commit(proof.S1, proof.S2)
+	w.Mod(w.Mul(w, new(big.Int).Exp(new(big.Int).ModInverse(proof.Z, params.N), e, params.N)), params.N)
+
+	return w.Cmp(proof.W) == 0
+}
+
+// affineProof bob proof that c2 = c1^x * (N + 1)^y * r^N mod N^2 with x in [0, q^3] and y in [0, q^7],
+// and X = xG if X is present, https://eprint.iacr.org/2019/114 appendix A.2 and A.3
+type affineProof struct {
+	U      *Point   `json:"u,omitempty"`
+	Z      *big.Int `json:"z"`
+	ZPrime *big.Int `json:"zp"`
+	T      *big.Int `json:"t"`
+	V      *big.Int `json:"v"`
+	W      *big.Int `json:"w"`
+	S      *big.Int `json:"s"`
+	S1     *big.Int `json:"s1"`
+	S2     *big.Int `json:"s2"`
+	T1     *big.Int `json:"t1"`
+	T2     *big.Int `json:"t2"`
+}
+
+func affineChallenge(pub *paillier.PublicKey, params *paillier.RingPedersen, X *Point, c1, c2 *big.Int, proof *affineProof) *big.Int {
+	values := []*big.Int{pub.N, params.N, params.S, params.T, c1, c2, proof.Z, proof.ZPrime, proof.T, proof.V, proof.W}
+
+	if X != nil {
+		values = append(values, pointValues(X, proof.U)...)
+	}
+
+	return challenge(values...)
+}
+
+func proveAffine(pub *paillier.PublicKey, params *paillier.RingPedersen, X *Point, c1, c2, x, y, r *big.Int) (*affineProof, error) {
+	q := order()
+	q3 := pow(q, 3)
+	qN := new(big.Int).Mul(q, params.N)
+	q3N := new(big.Int).Mul(q3, params.N)
+
+	var randoms [6]*big.Int
+
+	for i, max := range []*big.Int{q3, qN, q3N, qN, pow(q, 7), q3N} {
+		v, err := randomInt(max)
+
+		if err != nil {
+			return nil, err
+		}
+
+		randoms[i] = v
+	}
+
+	alpha, rho, rhoPrime, sigma, gamma, tau := randoms[0], randoms[1], randoms[2], randoms[3], randoms[4], randoms[5]
+
+	beta, err := paillier.RandomUnit(rand.Reader, pub.N)
+
+	if err != nil {
+		return nil, err
+	}
+
+	n2 := pub.N2()
+
+	proof := &affineProof{
+		Z:      params.Commit(x, rho),
+		ZPrime: params.Commit(alpha, rhoPrime),
+		T:      params.Commit(y, sigma),
+		W:      params.Commit(gamma, tau),
+	}
+
+	// v = c1^α * (N + 1)^γ * β^N mod N^2
+	proof.V = pub.EncryptWithNonce(gamma, beta)
+	proof.V.Mod(proof.V.Mul(proof.V, new(big.Int).Exp(c1, alpha, n2)), n2)
+
+	if X != nil {
+		proof.U = baseMult(alpha)
+	}
+
+	e := affineChallenge(pub, params, X, c1, c2, proof)
+
+	proof.S = new(big.Int).Exp(r, e, pub.N)
+	proof.S.Mod(proof.S.Mul(proof.S, beta), pub.N)
+
+	linear := func(a, b *big.Int) *big.Int {
+		v := new(big.Int).Mul(e, a)
+
+		return v.Add(v, b)
+	}
+
+	proof.S1 = linear(x, alpha)
+	proof.S2 = linear(rho, rhoPrime)
+	proof.T1 = linear(y, gamma)
+	proof.T2 = linear(sigma, tau)
+
+	return proof, nil
+}
+
+func (proof *affineProof) verify(pub *paillier.PublicKey, params *paillier.RingPedersen, X *Point, c1, c2 *big.Int) bool {
+	if proof == nil || !pub.ValidCiphertext(c1) || !pub.ValidCiphertext(c2) || !pub.ValidCiphertext(proof.V) ||
+		!unit(proof.S, pub.N) || proof.S2 == nil || proof.S2.Sign() < 0 || proof.T2 == nil || proof.T2.Sign() < 0 {
+		return false
+	}
+
+	for _, v := range []*big.Int{proof.Z, proof.ZPrime, proof.T, proof.W} {
+		if !unit(v, params.N) {
+			return false
+		}
+	}
+
+	if !inRange(proof.S1, pow(order(), 3)) || !inRange(proof.T1, pow(order(), 7)) {
+		return false
+	}
+
+	if X != nil && (!proof.U.valid() || proof.U.isIdentity()) {
+		return false
+	}
+
+	e := affineChallenge(pub, params, X, c1, c2, proof)
+
+	// s^s1 * t^s2 = z^e * z' mod Ñ
+	left := params.Commit(proof.S1, proof.S2)
+	right := new(big.Int).Exp(proof.Z, e, params.N)
+	right.Mod(right.Mul(right, proof.ZPrime), params.N)
+
+	if left.Cmp(right) != 0 {
+		return false
+	}
+
+	// s^t1 * t^t2 = t^e * w mod Ñ
+	left = params.Commit(proof.T1, proof.T2)
+	right = new(big.Int).Exp(proof.T, e, params.N)
+	right.Mod(right.Mul(right, proof.W), params.N)
+
+	if left.Cmp(right) != 0 {
+		return false
+	}
+
+	// c1^s1 * s^N * (N + 1)^t1 = c2^e * v mod N^2
+	n2 := pub.N2()
+
+	left = pub.EncryptWithNonce(proof.T1, proof.S)
+	left.Mod(left.Mul(left, new(big.Int).Exp(c1, proof.S1, n2)), n2)
+
+	right = new(big.Int).Exp(c2, e, n2)
+	right.Mod(right.Mul(right, proof.V), n2)
+
+	if left.Cmp(right) != 0 {
+		return false
+	}
+
+	// s1 * G = e * X + u
+	return X == nil || baseMult(proof.S1).equal(X.mult(e).add(proof.U))
+}
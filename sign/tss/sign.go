@@ -0,0 +1,696 @@
+package tss
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/sign"
+	"github.com/laplacenetwork/key/sign/recoverable"
+)
+
+// Errors
+var (
+	ErrSigners   = errors.New("invalid signers")
+	ErrSignature = errors.New("invalid threshold signature")
+)
+
+// signing rounds
+const (
+	signCommit = iota + 1
+	signEncrypt
+	signMtA
+	signDelta
+	signDecommit
+	signCheckCommit
+	signCheckDecommit
+	signCheckResultCommit
+	signCheckResultDecommit
+	signPartial
+)
+
+type signCommitMessage struct {
+	Commitment []byte `json:"commitment"` // commitment of Γi = γi * G
+}
+
+type signEncryptMessage struct {
+	K     *big.Int    `json:"k"` // paillier ciphertext of ki under the sender key
+	Proof *rangeProof `json:"proof"`
+}
+
+type signMtAMessage struct {
+	Gamma      *big.Int     `json:"gamma"` // ciphertext of ki * γj + β'
+	GammaProof *affineProof `json:"gammaproof"`
+	W          *big.Int     `json:"w"` // ciphertext of ki * wj + ν'
+	WProof     *affineProof `json:"wproof"`
+}
+
+type signDeltaMessage struct {
+	Delta *big.Int `json:"delta"`
+}
+
+type signDecommitMessage struct {
+	Gamma *Point        `json:"gamma"`
+	Nonce []byte        `json:"nonce"`
+	Proof *schnorrProof `json:"proof"`
+}
+
+type signCheckCommitMessage struct {
+	Commitment []byte `json:"commitment"` // commitment of Vi, Ai
+}
+
+type signCheckDecommitMessage struct {
+	V      *Point         `json:"v"`
+	A      *Point         `json:"a"`
+	Nonce  []byte         `json:"nonce"`
+	VProof *pedersenProof `json:"vproof"`
+	AProof *schnorrProof  `json:"aproof"`
+}
+
+type signCheckResultMessage struct {
+	Commitment []byte `json:"commitment"` // commitment of Ui, Ti
+}
+
+type signCheckResultDecommitMessage struct {
+	U     *Point `json:"u"`
+	T     *Point `json:"t"`
+	Nonce []byte `json:"nonce"`
+}
+
+type signPartialMessage struct {
+	S *big.Int `json:"s"`
+}
+
+// signer one party state of a signing run
+type signer struct {
+	*session
+	share  *KeyShare
+	hash   *big.Int
+	w      *big.Int         // lagrange weighted secret share
+	W      map[int]*Point   // lagrange weighted public shares
+	k      *big.Int         // nonce share
+	gamma  *big.Int         // blinding share
+	cK     *big.Int         // paillier ciphertext of k
+	peerCK map[int]*big.Int // paillier ciphertexts of the peers k
+}
+
+func checkSigners(share *KeyShare, signers []int) ([]int, error) {
+	sorted := append([]int{}, signers...)
+
+	sort.Ints(sorted)
+
+	found := false
+
+	for i, id := range sorted {
+		if _, ok := share.Shares[id]; !ok || (i > 0 && sorted[i-1] == id) {
+			return nil, xerrors.Wrapf(ErrSigners, "signer %d is not a party or duplicated", id)
+		}
+
+		if id != share.ID {
+			if _, ok := share.Peers[id]; !ok {
+				return nil, xerrors.Wrapf(ErrSigners, "signer %d missing paillier key", id)
+			}
+		}
+
+		found = found || id == share.ID
+	}
+
+	if !found {
+		return nil, xerrors.Wrapf(ErrSigners, "party %d not in signers", share.ID)
+	}
+
+	if len(sorted) < share.Threshold {
+		return nil, xerrors.Wrapf(ErrSigners, "%d signers less than threshold %d", len(sorted), share.Threshold)
+	}
+
+	return sorted, nil
+}
+
+// Sign threshold sign the hash together with the other signers, https://eprint.iacr.org/2019/114,
+// return 65 bytes r || s || v recoverable low s signature with v 27/28, the same as eth and did signature
+func Sign(share *KeyShare, signers []int, hash []byte, transport Transport) ([]byte, error) {
+	sig, err := SignRecoverable(share, signers, hash, transport)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sign.EncodeRecoverable(sig, 32, sign.VLegacy)
+}
+
+// SignRecoverable threshold sign the hash, return the low s signature with recovery id
+func SignRecoverable(share *KeyShare, signers []int, hash []byte, transport Transport) (*sign.Signature, error) {
+	signers, err := checkSigners(share, signers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &signer{
+		session: newSession(share.ID, signers, transport),
+		share:   share,
+		hash:    hashToInt(hash),
+		W:       make(map[int]*Point),
+		peerCK:  make(map[int]*big.Int),
+	}
+
+	for _, id := range signers {
+		s.W[id] = share.Shares[id].mult(lagrange(id, signers))
+	}
+
+	s.w = new(big.Int).Mul(share.Secret, lagrange(share.ID, signers))
+	s.w.Mod(s.w, order())
+
+	sig, err := s.sign()
+
+	if err != nil {
+		return nil, s.abort(err)
+	}
+
+	return sig, nil
+}
+
+// hashToInt the leftmost 256 bits of hash, the same as crypto/ecdsa
+func hashToInt(hash []byte) *big.Int {
+	if len(hash) > 32 {
+		hash = hash[:32]
+	}
+
+	return new(big.Int).SetBytes(hash)
+}
+
+func (s *signer) sign() (*sign.Signature, error) {
+	var err error
+
+	if s.k, err = randomScalar(); err != nil {
+		return nil, err
+	}
+
+	if s.gamma, err = randomScalar(); err != nil {
+		return nil, err
+	}
+
+	Gamma := baseMult(s.gamma)
+
+	commitment, nonce, err := commit(Gamma)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// phase 1, commit to Γi and send the encrypted ki with range proofs to each peer
+	if err := s.broadcast(signCommit, &signCommitMessage{Commitment: commitment}); err != nil {
+		return nil, err
+	}
+
+	paillierKey := s.share.Paillier
+
+	cK, r, err := paillierKey.Encrypt(rand.Reader, s.k)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.cK = cK
+
+	for _, peer := range s.peers {
+		proof, err := proveRange(&paillierKey.PublicKey, s.share.Peers[peer].Pedersen, cK, s.k, r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.send(peer, signEncrypt, &signEncryptMessage{K: cK, Proof: proof}); err != nil {
+			return nil, err
+		}
+	}
+
+	commitMessages := make(map[int]*signCommitMessage)
+
+	err = s.receive(signCommit, func(from int) interface{} {
+		commitMessages[from] = new(signCommitMessage)
+		return commitMessages[from]
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// phase 2, multiplicative to additive share conversion of ki * γj and ki * wj
+	alphas, betas, err := s.mta()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// δi = ki * γi + sum(αij + βji), σi = ki * wi + sum(μij + νji)
+	delta := new(big.Int).Mul(s.k, s.gamma)
+	sigma := new(big.Int).Mul(s.k, s.w)
+
+	for _, peer := range s.peers {
+		delta.Add(delta, alphas[peer][0])
+		delta.Add(delta, betas[peer][0])
+		sigma.Add(sigma, alphas[peer][1])
+		sigma.Add(sigma, betas[peer][1])
+	}
+
+	delta.Mod(delta, order())
+	sigma.Mod(sigma, order())
+
+	// phase 3, δ = k * γ
+	if err := s.broadcast(signDelta, &signDeltaMessage{Delta: delta}); err != nil {
+		return nil, err
+	}
+
+	deltaMessages := make(map[int]*signDeltaMessage)
+
+	err = s.receive(signDelta, func(from int) interface{} {
+		deltaMessages[from] = new(signDeltaMessage)
+		return deltaMessages[from]
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for from, msg := range deltaMessages {
+		if !validScalar(msg.Delta) {
+			return nil, xerrors.Wrapf(ErrMessage, "party %d delta out of range", from)
+		}
+
+		delta.Add(delta, msg.Delta)
+	}
+
+	delta.Mod(delta, order())
+
+	deltaInv := new(big.Int).ModInverse(delta, order())
+
+	if deltaInv == nil {
+		return nil, xerrors.Wrapf(ErrSignature, "delta is zero")
+	}
+
+	// phase 4, open Γi and R = δ^-1 * sum(Γi) = k^-1 * G
+	proof, err := proveSchnorr(s.id, s.gamma, Gamma)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.broadcast(signDecommit, &signDecommitMessage{Gamma: Gamma, Nonce: nonce, Proof: proof}); err != nil {
+		return nil, err
+	}
+
+	decommitMessages := make(map[int]*signDecommitMessage)
+
+	err = s.receive(signDecommit, func(from int) interface{} {
+		decommitMessages[from] = new(signDecommitMessage)
+		return decommitMessages[from]
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	R := Gamma
+
+	for from, msg := range decommitMessages {
+		if !msg.Gamma.valid() {
+			return nil, xerrors.Wrapf(ErrMessage, "party %d gamma not on curve", from)
+		}
+
+		if err := checkCommitment(msg.Gamma, msg.Nonce, commitMessages[from].Commitment); err != nil {
+			return nil, xerrors.Wrapf(err, "party %d gamma", from)
+		}
+
+		if !msg.Proof.verify(from, msg.Gamma) {
+			return nil, xerrors.Wrapf(ErrProof, "party %d gamma proof", from)
+		}
+
+		R = R.add(msg.Gamma)
+	}
+
+	R = R.mult(deltaInv)
+
+	if R.isIdentity() {
+		return nil, xerrors.Wrapf(ErrSignature, "R is the identity")
+	}
+
+	rx := new(big.Int).Mod(R.X, order())
+
+	if rx.Sign() == 0 {
+		return nil, xerrors.Wrapf(ErrSignature, "r is zero")
+	}
+
+	// the 0/1 recovery id can't express R.x >= N, which happens with negligible probability
+	if R.X.Cmp(order()) >= 0 {
+		return nil, xerrors.Wrapf(ErrSignature, "R.x overflows the curve order, sign again")
+	}
+
+	// si = m * ki + r * σi
+	si := new(big.Int).Mul(s.hash, s.k)
+	si.Add(si, new(big.Int).Mul(rx, sigma))
+	si.Mod(si, order())
+
+	// phase 5, check the sum of si is valid before revealing any si
+	if err := s.checkPartial(R, rx, si); err != nil {
+		return nil, err
+	}
+
+	if err := s.broadcast(signPartial, &signPartialMessage{S: si}); err != nil {
+		return nil, err
+	}
+
+	partialMessages := make(map[int]*signPartialMessage)
+
+	err = s.receive(signPartial, func(from int) interface{} {
+		partialMessages[from] = new(signPartialMessage)
+		return partialMessages[from]
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sum := new(big.Int).Set(si)
+
+	for from, msg := range partialMessages {
+		if !validScalar(msg.S) {
+			return nil, xerrors.Wrapf(ErrMessage, "party %d partial signature out of range", from)
+		}
+
+		sum.Add(sum, msg.S)
+	}
+
+	sum.Mod(sum, order())
+
+	sig := &sign.Signature{
+		R: rx,
+		S: sum,
+		V: big.NewInt(int64(sign.VLegacy) + int64(R.Y.Bit(0))),
+	}
+
+	if sig, err = sig.Normalize(curve); err != nil {
+		return nil, xerrors.Wrapf(ErrSignature, "%s", err)
+	}
+
+	publicKey := s.share.ECDSAPublicKey()
+
+	if !ecdsa.Verify(publicKey, hashToBytes(s.hash), sig.R, sig.S) {
+		return nil, xerrors.Wrapf(ErrSignature, "signature verification failed")
+	}
+
+	recovered, _, err := recoverable.Recover(curve, sig, hashToBytes(s.hash))
+
+	if err != nil || recovered.X.Cmp(publicKey.X) != 0 || recovered.Y.Cmp(publicKey.Y) != 0 {
+		return nil, xerrors.Wrapf(ErrSignature, "signature recovery failed")
+	}
+
+	return sig, nil
+}
+
+func hashToBytes(hash *big.Int) []byte {
+	return hash.FillBytes(make([]byte, 32))
+}
+
+// mta run the mta and mtawc protocols with each peer as bob and as alice, return the additive shares
+// [αij of ki * γj, μij of ki * wj] as alice and [βij of kj * γi, νij of kj * wi] as bob
+func (s *signer) mta() (map[int][2]*big.Int, map[int][2]*big.Int, error) {
+	encryptMessages := make(map[int]*signEncryptMessage)
+
+	err := s.receive(signEncrypt, func(from int) interface{} {
+		encryptMessages[from] = new(signEncryptMessage)
+		return encryptMessages[from]
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	betas := make(map[int][2]*big.Int)
+
+	for from, msg := range encryptMessages {
+		peer := s.share.Peers[from]
+
+		if !msg.Proof.verify(peer.Paillier, s.share.Pedersen, msg.K) {
+			return nil, nil, xerrors.Wrapf(ErrProof, "party %d range proof", from)
+		}
+
+		s.peerCK[from] = msg.K
+
+		response := &signMtAMessage{}
+
+		var shares [2]*big.Int
+
+		for i, b := range []*big.Int{s.gamma, s.w} {
+			c, beta, proof, err := s.bob(from, msg.K, b, i == 1)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			shares[i] = beta
+
+			if i == 0 {
+				response.Gamma, response.GammaProof = c, proof
+			} else {
+				response.W, response.WProof = c, proof
+			}
+		}
+
+		betas[from] = shares
+
+		if err := s.send(from, signMtA, response); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	mtaMessages := make(map[int]*signMtAMessage)
+
+	err = s.receive(signMtA, func(from int) interface{} {
+		mtaMessages[from] = new(signMtAMessage)
+		return mtaMessages[from]
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alphas := make(map[int][2]*big.Int)
+
+	paillierKey := s.share.Paillier
+
+	for from, msg := range mtaMessages {
+		if !msg.GammaProof.verify(&paillierKey.PublicKey, s.share.Pedersen, nil, s.cK, msg.Gamma) {
+			return nil, nil, xerrors.Wrapf(ErrProof, "party %d mta proof", from)
+		}
+
+		if !msg.WProof.verify(&paillierKey.PublicKey, s.share.Pedersen, s.W[from], s.cK, msg.W) {
+			return nil, nil, xerrors.Wrapf(ErrProof, "party %d mtawc proof", from)
+		}
+
+		var shares [2]*big.Int
+
+		for i, c := range []*big.Int{msg.Gamma, msg.W} {
+			alpha, err := paillierKey.Decrypt(c)
+
+			if err != nil {
+				return nil, nil, xerrors.Wrapf(err, "party %d mta decryption", from)
+			}
+
+			shares[i] = alpha.Mod(alpha, order())
+		}
+
+		alphas[from] = shares
+	}
+
+	return alphas, betas, nil
+}
+
+// bob mta response c = cK^b * Enc(β') of alice ciphertext cK, return c, β = -β' mod N and the proof
+// with the check of b * G if withCheck
+func (s *signer) bob(alice int, cK, b *big.Int, withCheck bool) (*big.Int, *big.Int, *affineProof, error) {
+	peer := s.share.Peers[alice]
+
+	betaPrime, err := randomInt(pow(order(), 5))
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	encrypted, r, err := peer.Paillier.Encrypt(rand.Reader, betaPrime)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	c := peer.Paillier.Add(peer.Paillier.Mul(cK, b), encrypted)
+
+	var X *Point
+
+	if withCheck {
+		X = s.W[s.id]
+	}
+
+	proof, err := proveAffine(peer.Paillier, peer.Pedersen, X, cK, c, b, betaPrime, r)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	beta := new(big.Int).Neg(betaPrime)
+
+	return c, beta.Mod(beta, order()), proof, nil
+}
+
+// checkPartial gg18 phase 5 check, the sum of si is a valid signature iff sum(Ui) = sum(Ti)
+func (s *signer) checkPartial(R *Point, rx, si *big.Int) error {
+	l, err := randomScalar()
+
+	if err != nil {
+		return err
+	}
+
+	rho, err := randomScalar()
+
+	if err != nil {
+		return err
+	}
+
+	V := R.mult(si).add(baseMult(l))
+	A := baseMult(rho)
+
+	commitment, nonce, err := commit([]*Point{V, A})
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.broadcast(signCheckCommit, &signCheckCommitMessage{Commitment: commitment}); err != nil {
+		return err
+	}
+
+	commitMessages := make(map[int]*signCheckCommitMessage)
+
+	err = s.receive(signCheckCommit, func(from int) interface{} {
+		commitMessages[from] = new(signCheckCommitMessage)
+		return commitMessages[from]
+	})
+
+	if err != nil {
+		return err
+	}
+
+	vProof, err := provePedersen(s.id, si, l, R, V)
+
+	if err != nil {
+		return err
+	}
+
+	aProof, err := proveSchnorr(s.id, rho, A)
+
+	if err != nil {
+		return err
+	}
+
+	err = s.broadcast(signCheckDecommit, &signCheckDecommitMessage{V: V, A: A, Nonce: nonce, VProof: vProof, AProof: aProof})
+
+	if err != nil {
+		return err
+	}
+
+	decommitMessages := make(map[int]*signCheckDecommitMessage)
+
+	err = s.receive(signCheckDecommit, func(from int) interface{} {
+		decommitMessages[from] = new(signCheckDecommitMessage)
+		return decommitMessages[from]
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// V = -m * G - r * X + sum(Vi), A = sum(Ai)
+	sumV := V.add(baseMult(new(big.Int).Neg(s.hash))).add(s.share.PublicKey.mult(new(big.Int).Neg(rx)))
+	sumA := A
+
+	for from, msg := range decommitMessages {
+		if !msg.V.valid() || !msg.A.valid() {
+			return xerrors.Wrapf(ErrMessage, "party %d check points not on curve", from)
+		}
+
+		if err := checkCommitment([]*Point{msg.V, msg.A}, msg.Nonce, commitMessages[from].Commitment); err != nil {
+			return xerrors.Wrapf(err, "party %d check", from)
+		}
+
+		if !msg.VProof.verify(from, R, msg.V) || !msg.AProof.verify(from, msg.A) {
+			return xerrors.Wrapf(ErrProof, "party %d check proof", from)
+		}
+
+		sumV = sumV.add(msg.V)
+		sumA = sumA.add(msg.A)
+	}
+
+	U := sumV.mult(rho)
+	T := sumA.mult(l)
+
+	commitment, nonce, err = commit([]*Point{U, T})
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.broadcast(signCheckResultCommit, &signCheckResultMessage{Commitment: commitment}); err != nil {
+		return err
+	}
+
+	resultMessages := make(map[int]*signCheckResultMessage)
+
+	err = s.receive(signCheckResultCommit, func(from int) interface{} {
+		resultMessages[from] = new(signCheckResultMessage)
+		return resultMessages[from]
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.broadcast(signCheckResultDecommit, &signCheckResultDecommitMessage{U: U, T: T, Nonce: nonce}); err != nil {
+		return err
+	}
+
+	resultDecommitMessages := make(map[int]*signCheckResultDecommitMessage)
+
+	err = s.receive(signCheckResultDecommit, func(from int) interface{} {
+		resultDecommitMessages[from] = new(signCheckResultDecommitMessage)
+		return resultDecommitMessages[from]
+	})
+
+	if err != nil {
+		return err
+	}
+
+	sumU, sumT := U, T
+
+	for from, msg := range resultDecommitMessages {
+		if !msg.U.valid() || !msg.T.valid() {
+			return xerrors.Wrapf(ErrMessage, "party %d check result not on curve", from)
+		}
+
+		if err := checkCommitment([]*Point{msg.U, msg.T}, msg.Nonce, resultMessages[from].Commitment); err != nil {
+			return xerrors.Wrapf(err, "party %d check result", from)
+		}
+
+		sumU = sumU.add(msg.U)
+		sumT = sumT.add(msg.T)
+	}
+
+	if !sumU.equal(sumT) {
+		return xerrors.Wrapf(ErrSignature, "phase 5 check failed, a party cheated")
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+package tss
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/dynamicgo/xerrors"
+)
+
+// Errors
+var (
+	ErrMessage = errors.New("invalid protocol message")
+	ErrAbort   = errors.New("protocol aborted by peer")
+)
+
+// abortRound the round of the message telling the peers the sender aborted
+const abortRound = -1
+
+// Message protocol message, To is 0 for broadcast
+type Message struct {
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+	Round   int    `json:"round"`
+	Payload []byte `json:"payload"`
+}
+
+// Transport authenticated message channel of one party for one protocol run, broadcast messages
+// must reach all the other parties, point to point messages carry no secret in the clear
+type Transport interface {
+	Send(msg *Message) error    // send point to point message or broadcast if To is 0
+	Receive() (*Message, error) // receive next message addressed to this party
+}
+
+// localHub in process message router
+type localHub struct {
+	sync.Mutex
+	cond   *sync.Cond
+	queues map[int][]*Message
+}
+
+type localTransport struct {
+	hub *localHub
+	id  int
+}
+
+// NewLocalTransports in process transports of the parties, for tests and single process deployments
+func NewLocalTransports(parties []int) map[int]Transport {
+	hub := &localHub{
+		queues: make(map[int][]*Message),
+	}
+
+	hub.cond = sync.NewCond(hub)
+
+	transports := make(map[int]Transport, len(parties))
+
+	for _, id := range parties {
+		hub.queues[id] = nil
+		transports[id] = &localTransport{hub: hub, id: id}
+	}
+
+	return transports
+}
+
+func (transport *localTransport) Send(msg *Message) error {
+	hub := transport.hub
+
+	hub.Lock()
+	defer hub.Unlock()
+
+	msg.From = transport.id
+
+	for id := range hub.queues {
+		if id != transport.id && (msg.To == 0 || msg.To == id) {
+			hub.queues[id] = append(hub.queues[id], msg)
+		}
+	}
+
+	hub.cond.Broadcast()
+
+	return nil
+}
+
+func (transport *localTransport) Receive() (*Message, error) {
+	hub := transport.hub
+
+	hub.Lock()
+	defer hub.Unlock()
+
+	for len(hub.queues[transport.id]) == 0 {
+		hub.cond.Wait()
+	}
+
+	msg := hub.queues[transport.id][0]
+
+	hub.queues[transport.id] = hub.queues[transport.id][1:]
+
+	return msg, nil
+}
+
+// session one party view of a protocol run, buffer the messages of later rounds
+type session struct {
+	id        int
+	peers     []int // the other parties
+	transport Transport
+	pending   []*Message
+}
+
+func newSession(id int, parties []int, transport Transport) *session {
+	s := &session{id: id, transport: transport}
+
+	for _, party := range parties {
+		if party != id {
+			s.peers = append(s.peers, party)
+		}
+	}
+
+	return s
+}
+
+func (s *session) send(to int, round int, payload interface{}) error {
+	buff, err := json.Marshal(payload)
+
+	if err != nil {
+		return xerrors.Wrapf(err, "marshal round %d message error", round)
+	}
+
+	return s.transport.Send(&Message{From: s.id, To: to, Round: round, Payload: buff})
+}
+
+func (s *session) broadcast(round int, payload interface{}) error {
+	return s.send(0, round, payload)
+}
+
+// abort tell the peers the protocol failed, best effort
+func (s *session) abort(err error) error {
+	s.broadcast(abortRound, err.Error())
+
+	return err
+}
+
+func (s *session) isPeer(id int) bool {
+	for _, peer := range s.peers {
+		if peer == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// receive one message of round from each peer, the payloads are unmarshalled with newPayload
+func (s *session) receive(round int, newPayload func(from int) interface{}) error {
+	received := make(map[int]bool, len(s.peers))
+
+	handle := func(msg *Message) (bool, error) {
+		if msg.Round == abortRound {
+			var reason string
+
+			json.Unmarshal(msg.Payload, &reason)
+
+			return false, xerrors.Wrapf(ErrAbort, "party %d: %s", msg.From, reason)
+		}
+
+		if msg.Round != round {
+			return false, nil
+		}
+
+		if !s.isPeer(msg.From) || received[msg.From] || (msg.To != 0 && msg.To != s.id) {
+			return false, xerrors.Wrapf(ErrMessage, "unexpected round %d message from %d", round, msg.From)
+		}
+
+		if err := json.Unmarshal(msg.Payload, newPayload(msg.From)); err != nil {
+			return false, xerrors.Wrapf(ErrMessage, "round %d message from %d: %s", round, msg.From, err)
+		}
+
+		received[msg.From] = true
+
+		return true, nil
+	}
+
+	var pending []*Message
+
+	for _, msg := range s.pending {
+		ok, err := handle(msg)
+
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			pending = append(pending, msg)
+		}
+	}
+
+	s.pending = pending
+
+	for len(received) < len(s.peers) {
+		msg, err := s.transport.Receive()
+
+		if err != nil {
+			return xerrors.Wrapf(err, "receive round %d message error", round)
+		}
+
+		ok, err := handle(msg)
+
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			s.pending = append(s.pending, msg)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package tss
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/openzknetwork/sha3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/internal/paillier"
+	_ "github.com/laplacenetwork/key/provider"
+)
+
+// pre-generated 1024 bits safe primes, generating them takes tens of seconds
+var testPrimes = []string{
+	"dae7993651f5f5cc61c9991477adf9e6ebc45f218b0aad0b468559f85b467b6c4187d8f7b45d82231f433b3904990cb1bfd130745bc1c4539df255511fd72bf6df4d77c303c89913f769baaf4fc986e496fe56661cd3e197875ad7ac9ea9b4ab4d64561a7746ca895b2f28c665394e48058424742a01493446e3f6fa58a65d13",
+	"d947bd58bb76b3f555353e67e0b1031274ed8efdbc222f6a5fa5a555a9d949dd19a5ebf3c8d84b938543b7f05376a43141dbcc8293b94aba5a571486358a54e81e087c87685026c668c41d1e581873636218baa168a1badd61a3e7599d8c4e05ae62195ae91405137e0bfae7ee6985572561a6192d3a4c39d8f54617a534ad8f",
+	"ce4fff6ab641ca2f4d222c7292c4d3418a8fb0797c3419f9e77bde0983c519680f120e3ada4997cb744a97a6d27ff8357c43a58926a860459f26682df033bfe3860ec49abac0e7e10a0f61f1e2b05d735602c85ddbe9d2ae0113212e3cba9ce2ffc7f07722178207bead5680931a0b271177826e20737f8091b526e77e5f1d43",
+	"fe62621e19bcea43222578a89f1e5d4c875a0f73016315cb8e8558f8c5eae3e32b1ebf93dfd3fd6d316f87a482a6bf7a6a5137a8250fa8b19ac171cb572721ecfe778fbb1c8ac46c5424e8b64505ac890dd1de5a98d41dff5c3fe910be31f450ef47b70cf9ac871b4ecd12d9908acaeb66a9ca904b3298c09e637bad76491777",
+	"c67fe204ff3efbdd55eb41fd2416bd98283c623e237f6bbd3eae7d8475e1bc575cb349aacb56e68bca710e2c82de3fe2962a8070d00449125b0ce365411eaf05ed4957f4af8e7a52d6da995d5c41d8dfbcbdc7474b10b0e5240c2630cf79dd70d2d9e19fe473ab1f33441da6700aedbb5b35182c62f717881484aff16c9e84c7",
+	"f2b680e9582ea339bf8aca171e5b678beafbd6238b3218e3c01b4c26512c51f5cf5d69dd985f784c1094d8c1173c9b3391a477b256a3c56e76fecfe899672bbc4c4609c5acc6a574d210aa0a0932b7f3386b14f235a31c1e783f235db2f1d43b2ac64d6163559660db8cd7b9450febddaefbc77536e0a984bfc6518c6a3964e3",
+}
+
+func testPreParams(t *testing.T, i int) *PreParams {
+	p, _ := new(big.Int).SetString(testPrimes[2*i], 16)
+	q, _ := new(big.Int).SetString(testPrimes[2*i+1], 16)
+
+	paillierKey, err := paillier.NewPrivateKey(p, q)
+
+	require.NoError(t, err)
+
+	return &PreParams{Paillier: paillierKey}
+}
+
+// run the protocol of each party concurrently over in process transports
+func run(parties []int, f func(id int, transport Transport) error) map[int]error {
+	transports := NewLocalTransports(parties)
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+
+	errs := make(map[int]error)
+
+	for _, id := range parties {
+		wg.Add(1)
+
+		go func(id int) {
+			defer wg.Done()
+
+			err := f(id, transports[id])
+
+			mutex.Lock()
+			errs[id] = err
+			mutex.Unlock()
+		}(id)
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+func testKeygen(t *testing.T) map[int]*KeyShare {
+	parties := []int{1, 2, 3}
+
+	shares := make(map[int]*KeyShare)
+
+	var mutex sync.Mutex
+
+	errs := run(parties, func(id int, transport Transport) error {
+		share, err := Keygen(id, parties, 2, testPreParams(t, id-1), transport)
+
+		mutex.Lock()
+		shares[id] = share
+		mutex.Unlock()
+
+		return err
+	})
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	return shares
+}
+
+func TestKeygenSign(t *testing.T) {
+	shares := testKeygen(t)
+
+	pubkey := shares[1].PubKey()
+
+	for _, share := range shares {
+		require.Equal(t, pubkey, share.PubKey())
+	}
+
+	// the shamir shares interpolate to the private key of the public key
+	secret := new(big.Int)
+
+	for _, id := range []int{1, 3} {
+		secret.Add(secret, new(big.Int).Mul(shares[id].Secret, lagrange(id, []int{1, 3})))
+	}
+
+	require.True(t, baseMult(secret).equal(shares[2].PublicKey))
+
+	hasher := sha3.NewKeccak256()
+
+	hasher.Write([]byte("threshold ecdsa"))
+
+	hash := hasher.Sum(nil)
+
+	for _, signers := range [][]int{{1, 2}, {3, 2}, {1, 2, 3}} {
+		sigs := make(map[int][]byte)
+
+		var mutex sync.Mutex
+
+		errs := run(signers, func(id int, transport Transport) error {
+			sig, err := Sign(shares[id], signers, hash, transport)
+
+			mutex.Lock()
+			sigs[id] = sig
+			mutex.Unlock()
+
+			return err
+		})
+
+		for id, err := range errs {
+			require.NoError(t, err)
+
+			require.Len(t, sigs[id], 65)
+
+			require.Equal(t, sigs[signers[0]], sigs[id])
+		}
+
+		for _, driver := range []string{"eth", "did"} {
+			ok, err := key.Verify(driver, pubkey, sigs[signers[0]], hash)
+
+			require.NoError(t, err)
+
+			require.True(t, ok)
+
+			recovered, err := key.Recover(driver, sigs[signers[0]], hash)
+
+			require.NoError(t, err)
+
+			require.Equal(t, pubkey, recovered)
+		}
+	}
+
+	// the key share survives json persistence
+	buff, err := json.Marshal(shares[1])
+
+	require.NoError(t, err)
+
+	share := new(KeyShare)
+
+	require.NoError(t, json.Unmarshal(buff, share))
+
+	require.Equal(t, pubkey, share.PubKey())
+
+	require.Equal(t, shares[1].Secret, share.Secret)
+
+	// not enough signers
+	_, err = Sign(shares[1], []int{1}, hash, NewLocalTransports([]int{1})[1])
+
+	require.Error(t, err)
+
+	// signers disagreeing on the hash fail the phase 5 check without revealing partial signatures
+	errs := run([]int{1, 2}, func(id int, transport Transport) error {
+		_, err := Sign(shares[id], []int{1, 2}, append([]byte{byte(id)}, hash[1:]...), transport)
+
+		return err
+	})
+
+	for _, err := range errs {
+		require.Error(t, err)
+	}
+}
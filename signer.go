@@ -0,0 +1,97 @@
+package key
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"errors"
+	"io"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key/sign"
+	"github.com/laplacenetwork/key/sign/rfc6979"
+)
+
+// Errors
+var (
+	ErrCryptoKey = errors.New("key not support standard library crypto key")
+	ErrDigest    = errors.New("digest length mismatch hash function")
+)
+
+// CryptoKey the key can be exposed as standard library private key
+type CryptoKey interface {
+	CryptoPrivateKey() crypto.PrivateKey // *ecdsa.PrivateKey or ed25519.PrivateKey
+}
+
+// CryptoPublicKey the public key can be exposed as standard library public key
+type CryptoPublicKey interface {
+	CryptoPublicKey() crypto.PublicKey // *ecdsa.PublicKey or ed25519.PublicKey
+}
+
+// curves supported by crypto/ecdsa constant time implement
+var stdCurves = map[string]bool{
+	"P-224": true,
+	"P-256": true,
+	"P-384": true,
+	"P-521": true,
+}
+
+type signerImpl struct {
+	key       Key
+	private   crypto.PrivateKey
+	publicKey crypto.PublicKey
+}
+
+// NewSigner expose the key as crypto.Signer, ecdsa keys produce asn.1 der signature
+// and ed25519 keys follow ed25519.PrivateKey signer options
+func NewSigner(key Key) (crypto.Signer, error) {
+	cryptoKey, ok := key.(CryptoKey)
+
+	if !ok {
+		return nil, xerrors.Wrapf(ErrCryptoKey, "driver %s", key.Provider().Name())
+	}
+
+	cryptoPublicKey, ok := key.PublicKey().(CryptoPublicKey)
+
+	if !ok {
+		return nil, xerrors.Wrapf(ErrCryptoKey, "driver %s", key.Provider().Name())
+	}
+
+	return &signerImpl{
+		key:       key,
+		private:   cryptoKey.CryptoPrivateKey(),
+		publicKey: cryptoPublicKey.CryptoPublicKey(),
+	}, nil
+}
+
+func (signer *signerImpl) Public() crypto.PublicKey {
+	return signer.publicKey
+}
+
+// Sign sign the digest hashed by opts.HashFunc(), the rand is ignored by secp256k1 keys
+// which use rfc6979 deterministic nonce
+func (signer *signerImpl) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	switch privateKey := signer.private.(type) {
+	case ed25519.PrivateKey:
+		return privateKey.Sign(rand, digest, opts)
+	case *ecdsa.PrivateKey:
+		if opts != nil && opts.HashFunc() != 0 && len(digest) != opts.HashFunc().Size() {
+			return nil, xerrors.Wrapf(ErrDigest, "digest length %d, %s size %d", len(digest), opts.HashFunc(), opts.HashFunc().Size())
+		}
+
+		if stdCurves[privateKey.Curve.Params().Name] {
+			return ecdsa.SignASN1(rand, privateKey, digest)
+		}
+
+		sig, err := rfc6979.Sign(privateKey, digest)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return sign.EncodeDER(sig)
+	default:
+		return nil, xerrors.Wrapf(ErrCryptoKey, "private key type %T", signer.private)
+	}
+}
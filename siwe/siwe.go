@@ -0,0 +1,476 @@
+// Package siwe erc-4361 sign-in with ethereum message implement
+package siwe
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dynamicgo/xerrors"
+
+	"github.com/laplacenetwork/key"
+	"github.com/laplacenetwork/key/provider/eth"
+	"github.com/laplacenetwork/key/sign"
+)
+
+// Errors
+var (
+	ErrMessage     = errors.New("invalid siwe message")
+	ErrExpired     = errors.New("siwe message expired")
+	ErrNotYetValid = errors.New("siwe message not yet valid")
+	ErrDomain      = errors.New("siwe message domain mismatch")
+	ErrNonce       = errors.New("siwe message nonce mismatch")
+	ErrSigner      = errors.New("siwe message signer mismatch")
+	ErrKeyProvider = errors.New("siwe message must be signed by eth key")
+)
+
+const (
+	headerSuffix  = " wants you to sign in with your Ethereum account:"
+	uriTag        = "URI: "
+	versionTag    = "Version: "
+	chainIDTag    = "Chain ID: "
+	nonceTag      = "Nonce: "
+	issuedAtTag   = "Issued At: "
+	expirationTag = "Expiration Time: "
+	notBeforeTag  = "Not Before: "
+	requestIDTag  = "Request ID: "
+	resourcesTag  = "Resources:"
+	resourcePre   = "- "
+	nonceChars    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	nonceLen      = 17
+)
+
+// Message erc-4361 message
+type Message struct {
+	Scheme         string // optional uri scheme of the origin
+	Domain         string
+	Address        string // eip-55 checksum address
+	Statement      string // optional
+	URI            string
+	Version        string
+	ChainID        uint64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time // optional
+	NotBefore      *time.Time // optional
+	RequestID      string     // optional
+	Resources      []string   // optional
+}
+
+// VerifyOptions expected values of message, domain and nonce are required to prevent the signature
+// from being replayed on another site or session
+type VerifyOptions struct {
+	Domain                  string
+	Nonce                   string
+	Time                    time.Time // zero value means time.Now()
+	InsecureSkipDomainNonce bool      // accept any domain and nonce, only for callers checking them elsewhere
+}
+
+// NewNonce generate random alphanumeric nonce
+func NewNonce() (string, error) {
+	buff := make([]byte, nonceLen)
+
+	max := big.NewInt(int64(len(nonceChars)))
+
+	for i := range buff {
+		n, err := rand.Int(rand.Reader, max)
+
+		if err != nil {
+			return "", xerrors.Wrapf(err, "read random error")
+		}
+
+		buff[i] = nonceChars[n.Int64()]
+	}
+
+	return string(buff), nil
+}
+
+// New create message with random nonce issued now
+func New(domain string, address string, uri string, chainID uint64) (*Message, error) {
+	nonce, err := NewNonce()
+
+	if err != nil {
+		return nil, err
+	}
+
+	message := &Message{
+		Domain:   domain,
+		Address:  address,
+		URI:      uri,
+		Version:  "1",
+		ChainID:  chainID,
+		Nonce:    nonce,
+		IssuedAt: time.Now().UTC().Truncate(time.Second),
+	}
+
+	if err := message.check(); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// String the erc-4361 message text to be signed
+func (message *Message) String() string {
+	var builder strings.Builder
+
+	if message.Scheme != "" {
+		builder.WriteString(message.Scheme + "://")
+	}
+
+	builder.WriteString(message.Domain + headerSuffix + "\n")
+	builder.WriteString(message.Address + "\n\n")
+
+	if message.Statement != "" {
+		builder.WriteString(message.Statement + "\n")
+	}
+
+	builder.WriteString("\n")
+	builder.WriteString(uriTag + message.URI + "\n")
+	builder.WriteString(versionTag + message.Version + "\n")
+	builder.WriteString(chainIDTag + strconv.FormatUint(message.ChainID, 10) + "\n")
+	builder.WriteString(nonceTag + message.Nonce + "\n")
+	builder.WriteString(issuedAtTag + message.IssuedAt.Format(time.RFC3339Nano))
+
+	if message.ExpirationTime != nil {
+		builder.WriteString("\n" + expirationTag + message.ExpirationTime.Format(time.RFC3339Nano))
+	}
+
+	if message.NotBefore != nil {
+		builder.WriteString("\n" + notBeforeTag + message.NotBefore.Format(time.RFC3339Nano))
+	}
+
+	if message.RequestID != "" {
+		builder.WriteString("\n" + requestIDTag + message.RequestID)
+	}
+
+	if len(message.Resources) > 0 {
+		builder.WriteString("\n" + resourcesTag)
+
+		for _, resource := range message.Resources {
+			builder.WriteString("\n" + resourcePre + resource)
+		}
+	}
+
+	return builder.String()
+}
+
+// Parse parse erc-4361 message text
+func Parse(text string) (*Message, error) {
+	lines := strings.Split(text, "\n")
+
+	message := &Message{}
+
+	if len(lines) < 3 || !strings.HasSuffix(lines[0], headerSuffix) {
+		return nil, xerrors.Wrapf(ErrMessage, "invalid header")
+	}
+
+	origin := strings.TrimSuffix(lines[0], headerSuffix)
+
+	if i := strings.Index(origin, "://"); i >= 0 {
+		message.Scheme = origin[:i]
+		origin = origin[i+3:]
+	}
+
+	message.Domain = origin
+	message.Address = lines[1]
+
+	if lines[2] != "" {
+		return nil, xerrors.Wrapf(ErrMessage, "expect empty line after address")
+	}
+
+	lines = lines[3:]
+
+	if len(lines) > 0 && lines[0] != "" {
+		message.Statement = lines[0]
+		lines = lines[1:]
+	}
+
+	if len(lines) == 0 || lines[0] != "" {
+		return nil, xerrors.Wrapf(ErrMessage, "expect empty line before uri")
+	}
+
+	lines = lines[1:]
+
+	next := func(tag string, optional bool) (string, bool, error) {
+		if len(lines) == 0 || !strings.HasPrefix(lines[0], tag) {
+			if optional {
+				return "", false, nil
+			}
+
+			return "", false, xerrors.Wrapf(ErrMessage, "missing field %s", strings.TrimSuffix(tag, ": "))
+		}
+
+		value := strings.TrimPrefix(lines[0], tag)
+
+		lines = lines[1:]
+
+		return value, true, nil
+	}
+
+	var err error
+
+	if message.URI, _, err = next(uriTag, false); err != nil {
+		return nil, err
+	}
+
+	if message.Version, _, err = next(versionTag, false); err != nil {
+		return nil, err
+	}
+
+	chainID, _, err := next(chainIDTag, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if message.ChainID, err = strconv.ParseUint(chainID, 10, 64); err != nil {
+		return nil, xerrors.Wrapf(ErrMessage, "invalid chain id %s", chainID)
+	}
+
+	if message.Nonce, _, err = next(nonceTag, false); err != nil {
+		return nil, err
+	}
+
+	issuedAt, _, err := next(issuedAtTag, false)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if message.IssuedAt, err = parseTime(issuedAt); err != nil {
+		return nil, err
+	}
+
+	if value, ok, _ := next(expirationTag, true); ok {
+		expirationTime, err := parseTime(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		message.ExpirationTime = &expirationTime
+	}
+
+	if value, ok, _ := next(notBeforeTag, true); ok {
+		notBefore, err := parseTime(value)
+
+		if err != nil {
+			return nil, err
+		}
+
+		message.NotBefore = &notBefore
+	}
+
+	message.RequestID, _, _ = next(requestIDTag, true)
+
+	if len(lines) > 0 && lines[0] == resourcesTag {
+		lines = lines[1:]
+
+		for len(lines) > 0 && strings.HasPrefix(lines[0], resourcePre) {
+			message.Resources = append(message.Resources, strings.TrimPrefix(lines[0], resourcePre))
+			lines = lines[1:]
+		}
+	}
+
+	if len(lines) > 0 {
+		return nil, xerrors.Wrapf(ErrMessage, "unexpected line %q", lines[0])
+	}
+
+	if err := message.check(); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+
+	if err != nil {
+		return time.Time{}, xerrors.Wrapf(ErrMessage, "invalid rfc3339 time %s", value)
+	}
+
+	return t, nil
+}
+
+func checkURI(value string) error {
+	uri, err := url.Parse(value)
+
+	if err != nil || uri.Scheme == "" {
+		return xerrors.Wrapf(ErrMessage, "invalid uri %s", value)
+	}
+
+	return nil
+}
+
+// check the message fields format
+func (message *Message) check() error {
+	if message.Domain == "" || strings.ContainsAny(message.Domain, " \t\n/") {
+		return xerrors.Wrapf(ErrMessage, "invalid domain %q", message.Domain)
+	}
+
+	if err := eth.CheckAddress(message.Address, true); err != nil {
+		return xerrors.Wrapf(ErrMessage, "invalid address: %s", err)
+	}
+
+	if strings.Contains(message.Statement, "\n") {
+		return xerrors.Wrapf(ErrMessage, "statement must not contain line break")
+	}
+
+	if err := checkURI(message.URI); err != nil {
+		return err
+	}
+
+	if message.Version != "1" {
+		return xerrors.Wrapf(ErrMessage, "unsupported version %s", message.Version)
+	}
+
+	if len(message.Nonce) < 8 || strings.Trim(message.Nonce, nonceChars) != "" {
+		return xerrors.Wrapf(ErrMessage, "nonce %s must be at least 8 alphanumeric characters", message.Nonce)
+	}
+
+	if message.IssuedAt.IsZero() {
+		return xerrors.Wrapf(ErrMessage, "missing issued at")
+	}
+
+	if strings.Contains(message.RequestID, "\n") {
+		return xerrors.Wrapf(ErrMessage, "request id must not contain line break")
+	}
+
+	for _, resource := range message.Resources {
+		if err := checkURI(resource); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate check the message fields format and the validity time window
+func (message *Message) Validate(now time.Time) error {
+	if err := message.check(); err != nil {
+		return err
+	}
+
+	if message.ExpirationTime != nil && !now.Before(*message.ExpirationTime) {
+		return xerrors.Wrapf(ErrExpired, "expired at %s", message.ExpirationTime.Format(time.RFC3339))
+	}
+
+	if message.NotBefore != nil && now.Before(*message.NotBefore) {
+		return xerrors.Wrapf(ErrNotYetValid, "not before %s", message.NotBefore.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Hash eip-191 personal_sign hash of the message text
+func (message *Message) Hash() []byte {
+	return eth.HashPersonalMessage([]byte(message.String()))
+}
+
+// Sign sign the message with eth key, the key address must be the message address
+func (message *Message) Sign(k key.Key) ([]byte, error) {
+	if k.Provider().Name() != "eth" {
+		return nil, xerrors.Wrapf(ErrKeyProvider, "provider %s", k.Provider().Name())
+	}
+
+	if k.Address() != message.Address {
+		return nil, xerrors.Wrapf(ErrSigner, "key address %s", k.Address())
+	}
+
+	if err := message.check(); err != nil {
+		return nil, err
+	}
+
+	return k.Sign(message.Hash())
+}
+
+// Verify validate the message and check the signature is signed by the message address
+func (message *Message) Verify(sig []byte, options *VerifyOptions) error {
+	return message.verify(message.Hash(), sig, options)
+}
+
+func (message *Message) verify(hash []byte, sig []byte, options *VerifyOptions) error {
+	if options == nil {
+		options = &VerifyOptions{}
+	}
+
+	now := options.Time
+
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if err := message.Validate(now); err != nil {
+		return err
+	}
+
+	if !options.InsecureSkipDomainNonce {
+		if options.Domain == "" {
+			return xerrors.Wrapf(ErrDomain, "expected domain is required")
+		}
+
+		if options.Nonce == "" {
+			return xerrors.Wrapf(ErrNonce, "expected nonce is required")
+		}
+
+		if options.Domain != message.Domain {
+			return xerrors.Wrapf(ErrDomain, "expect %s got %s", options.Domain, message.Domain)
+		}
+
+		if options.Nonce != message.Nonce {
+			return xerrors.Wrapf(ErrNonce, "expect %s got %s", options.Nonce, message.Nonce)
+		}
+	}
+
+	// wallets like ledger return the raw 0/1 recovery id
+	if len(sig) == 65 && sig[64] < sign.VLegacy {
+		signature, err := sign.DecodeRecoverableOffset(sig, sign.VRaw)
+
+		if err != nil {
+			return xerrors.Wrapf(ErrSigner, "decode signature error: %s", err)
+		}
+
+		if sig, err = sign.EncodeRecoverable(signature, 32, sign.VLegacy); err != nil {
+			return xerrors.Wrapf(ErrSigner, "encode signature error: %s", err)
+		}
+	}
+
+	pubkey, err := key.Recover("eth", sig, hash)
+
+	if err != nil {
+		return xerrors.Wrapf(ErrSigner, "recover signer error: %s", err)
+	}
+
+	address, err := key.PublicKeyToAddress("eth", pubkey)
+
+	if err != nil {
+		return xerrors.Wrapf(ErrSigner, "recover signer error: %s", err)
+	}
+
+	if address != message.Address {
+		return xerrors.Wrapf(ErrSigner, "signer %s", address)
+	}
+
+	return nil
+}
+
+// Verify parse the message text and verify the signature over the original text
+func Verify(text string, sig []byte, options *VerifyOptions) (*Message, error) {
+	message, err := Parse(text)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := message.verify(eth.HashPersonalMessage([]byte(text)), sig, options); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
@@ -0,0 +1,139 @@
+package siwe
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/laplacenetwork/key"
+)
+
+const example = `example.com wants you to sign in with your Ethereum account:
+0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2
+
+I accept the ExampleOrg Terms of Service: https://example.com/tos
+
+URI: https://example.com/login
+Version: 1
+Chain ID: 1
+Nonce: 32891756
+Issued At: 2021-09-30T16:25:24Z
+Resources:
+- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/
+- https://example.com/my-web2-claim.json`
+
+func TestParse(t *testing.T) {
+	message, err := Parse(example)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "example.com", message.Domain)
+	require.Equal(t, "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", message.Address)
+	require.Equal(t, "I accept the ExampleOrg Terms of Service: https://example.com/tos", message.Statement)
+	require.Equal(t, uint64(1), message.ChainID)
+	require.Equal(t, "32891756", message.Nonce)
+	require.Len(t, message.Resources, 2)
+
+	require.Equal(t, example, message.String())
+
+	noStatement := strings.Replace(example, "I accept the ExampleOrg Terms of Service: https://example.com/tos\n", "", 1)
+
+	message, err = Parse("https://" + noStatement)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "https", message.Scheme)
+	require.Empty(t, message.Statement)
+
+	require.Equal(t, "https://"+noStatement, message.String())
+
+	for _, invalid := range []string{
+		strings.Replace(example, "Version: 1", "Version: 2", 1),
+		strings.Replace(example, "Nonce: 32891756", "Nonce: 1234", 1),
+		strings.Replace(example, "Chain ID: 1\n", "", 1),
+		strings.Replace(example, "2021-09-30T16:25:24Z", "yesterday", 1),
+		strings.Replace(example, "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", "0xC02a", 1),
+		example + "\nextra",
+	} {
+		_, err := Parse(invalid)
+
+		require.Error(t, err)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	k, err := key.New("eth")
+
+	require.NoError(t, err)
+
+	message, err := New("example.com", k.Address(), "https://example.com/login", 1)
+
+	require.NoError(t, err)
+
+	expiration := message.IssuedAt.Add(time.Hour)
+
+	message.ExpirationTime = &expiration
+	message.Resources = []string{"https://example.com/my-web2-claim.json"}
+
+	sig, err := message.Sign(k)
+
+	require.NoError(t, err)
+
+	options := &VerifyOptions{Domain: "example.com", Nonce: message.Nonce}
+
+	parsed, err := Verify(message.String(), sig, options)
+
+	require.NoError(t, err)
+
+	require.Equal(t, message.Nonce, parsed.Nonce)
+
+	// wallets returning v as 0/1
+	zeroV := append(append([]byte(nil), sig[:64]...), sig[64]-27)
+
+	_, err = Verify(message.String(), zeroV, options)
+
+	require.NoError(t, err)
+
+	_, err = Verify(message.String(), sig, &VerifyOptions{Domain: "evil.com", Nonce: message.Nonce})
+
+	require.ErrorIs(t, err, ErrDomain)
+
+	_, err = Verify(message.String(), sig, &VerifyOptions{Domain: "example.com", Nonce: "abcdefgh1"})
+
+	require.ErrorIs(t, err, ErrNonce)
+
+	// domain and nonce are required unless explicitly skipped
+	_, err = Verify(message.String(), sig, nil)
+
+	require.ErrorIs(t, err, ErrDomain)
+
+	_, err = Verify(message.String(), sig, &VerifyOptions{Nonce: message.Nonce})
+
+	require.ErrorIs(t, err, ErrDomain)
+
+	_, err = Verify(message.String(), sig, &VerifyOptions{Domain: "example.com"})
+
+	require.ErrorIs(t, err, ErrNonce)
+
+	_, err = Verify(message.String(), sig, &VerifyOptions{InsecureSkipDomainNonce: true})
+
+	require.NoError(t, err)
+
+	_, err = Verify(message.String(), sig, &VerifyOptions{Domain: "example.com", Nonce: message.Nonce, Time: expiration.Add(time.Second)})
+
+	require.ErrorIs(t, err, ErrExpired)
+
+	other, err := key.New("eth")
+
+	require.NoError(t, err)
+
+	_, err = message.Sign(other)
+
+	require.Error(t, err)
+
+	message.Address = other.Address()
+
+	require.ErrorIs(t, message.Verify(sig, options), ErrSigner)
+}
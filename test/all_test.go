@@ -2,13 +2,34 @@ package test
 
 import (
 	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/laplacenetwork/key"
 	_ "github.com/laplacenetwork/key/encryptor"
+	"github.com/laplacenetwork/key/hd"
+	"github.com/laplacenetwork/key/internal/ed25519x"
+	"github.com/laplacenetwork/key/internal/secp256k1"
+	"github.com/laplacenetwork/key/mnemonic"
 	_ "github.com/laplacenetwork/key/provider"
+	"github.com/laplacenetwork/key/provider/bls"
+	"github.com/laplacenetwork/key/provider/did"
+	"github.com/laplacenetwork/key/provider/eth"
+	"github.com/laplacenetwork/key/provider/p256"
+	"github.com/laplacenetwork/key/shamir"
+	signbls "github.com/laplacenetwork/key/sign/bls"
 )
 
 func TestEthKey(t *testing.T) {
@@ -35,7 +56,9 @@ func TestDidKey(t *testing.T) {
 
 func TestSign(t *testing.T) {
 
-	data := []byte("hello world")
+	hashed := sha256.Sum256([]byte("hello world"))
+
+	data := hashed[:]
 
 	did, err := key.New("did")
 
@@ -62,6 +85,102 @@ func TestSign(t *testing.T) {
 	require.NoError(t, err)
 
 	require.True(t, ok)
+
+	// r = 0 and r = N are rejected before recovery
+	n := secp256k1.SECP256K1().Params().N
+
+	for _, driver := range []string{"eth", "did"} {
+		for _, r := range []*big.Int{big.NewInt(0), n} {
+			bad := append(r.FillBytes(make([]byte, 32)), sig[32:]...)
+
+			ok, err := key.Verify(driver, nil, bad, data)
+
+			require.NoError(t, err)
+
+			require.False(t, ok)
+
+			_, err = key.Recover(driver, bad, data)
+
+			require.Error(t, err)
+		}
+	}
+}
+
+func TestSignHashLength(t *testing.T) {
+	hashed := sha256.Sum256([]byte("hello world"))
+
+	for _, driver := range []string{"eth", "did", "btc", "p256"} {
+		k, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		for _, hash := range [][]byte{[]byte("hello world"), append(hashed[:], hashed[:]...)} {
+			_, err = k.Sign(hash)
+
+			require.Error(t, err)
+		}
+
+		sig, err := k.Sign(hashed[:])
+
+		require.NoError(t, err)
+
+		require.True(t, k.PublicKey().Verify(sig, hashed[:]))
+
+		// ecdsa truncates long hashes, h || suffix must not verify the signature of h
+		suffixed := append(hashed[:], 0xde, 0xad)
+
+		require.False(t, k.PublicKey().Verify(sig, suffixed))
+
+		require.False(t, k.PublicKey().Verify(sig, hashed[:31]))
+
+		ok, err := key.Verify(driver, k.PubKey(), sig, suffixed)
+
+		require.NoError(t, err)
+
+		require.False(t, ok)
+	}
+}
+
+func TestCanonicalV(t *testing.T) {
+	hash := sha256.Sum256([]byte("hello v"))
+
+	for _, driver := range []string{"eth", "did"} {
+		k, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		sig, err := k.Sign(hash[:])
+
+		require.NoError(t, err)
+
+		recid := sig[64] - 27
+
+		// the raw recovery id and its compressed flag form are malleated encodings of the same signature
+		malleatedV := []byte{recid, recid + 4}
+
+		if driver == "eth" {
+			// ethereum v has no compressed flag
+			malleatedV = append(malleatedV, 31+recid)
+		}
+
+		for _, v := range malleatedV {
+			malleated := append(append([]byte(nil), sig[:64]...), v)
+
+			for _, pubkey := range [][]byte{k.PubKey(), nil} {
+				ok, err := key.Verify(driver, pubkey, malleated, hash[:])
+
+				require.NoError(t, err)
+
+				require.False(t, ok, "%s v %d", driver, v)
+			}
+
+			require.False(t, k.PublicKey().Verify(malleated, hash[:]))
+
+			_, err = key.Recover(driver, malleated, hash[:])
+
+			require.Error(t, err)
+		}
+	}
 }
 
 func TestWeb3Encryptor(t *testing.T) {
@@ -93,3 +212,910 @@ func TestWeb3Encryptor(t *testing.T) {
 	require.Equal(t, k.PriKey(), k2.PriKey())
 
 }
+
+func TestHDKey(t *testing.T) {
+	master, err := hd.NewMaster([]byte("laplace hd key seed"))
+
+	require.NoError(t, err)
+
+	child, err := master.Child(hd.HardenedKeyStart)
+
+	require.NoError(t, err)
+
+	child, err = child.Child(0)
+
+	require.NoError(t, err)
+
+	for _, driver := range []string{"eth", "did"} {
+		k, err := key.FromHD(driver, child)
+
+		require.NoError(t, err)
+
+		address, err := key.PublicKeyToAddress(driver, child.Neuter().PubKey())
+
+		require.NoError(t, err)
+
+		require.Equal(t, k.Address(), address)
+	}
+
+	_, err = key.FromHD("eth", child.Neuter())
+
+	require.Error(t, err)
+}
+
+func TestMnemonicKey(t *testing.T) {
+	words, err := mnemonic.New(12)
+
+	require.NoError(t, err)
+
+	k, err := mnemonic.NewKey("eth", words, "test")
+
+	require.NoError(t, err)
+
+	k2, err := mnemonic.NewKey("eth", words, "test")
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), k2.Address())
+
+	k3, err := mnemonic.NewKey("eth", words, "")
+
+	require.NoError(t, err)
+
+	require.NotEqual(t, k.Address(), k3.Address())
+}
+
+func TestDerivePath(t *testing.T) {
+	words := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	path, err := key.DefaultPath("eth", 0)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "m/44'/60'/0'/0/0", path)
+
+	k, err := mnemonic.NewKey("eth", words, "")
+
+	require.NoError(t, err)
+
+	require.Equal(t, "0x9858EfFD232B4033E47d90003D41EC34EcaEda94", k.Address())
+
+	seed, err := mnemonic.Seed(words, "")
+
+	require.NoError(t, err)
+
+	k, err = key.Derive("eth", seed, "m/44'/60'/0'/0/1")
+
+	require.NoError(t, err)
+
+	require.Equal(t, "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0", k.Address())
+
+	// slip10 ed25519 and p256 keys are not derived by secp256k1 bip32
+	for _, driver := range []string{"solana", "p256"} {
+		_, err = key.Derive(driver, seed, "m/44'/501'/0'/0'")
+
+		require.ErrorIs(t, err, key.ErrHD)
+	}
+
+	master, err := hd.NewMaster(seed)
+
+	require.NoError(t, err)
+
+	_, err = key.FromHD("solana", master)
+
+	require.ErrorIs(t, err, key.ErrHD)
+}
+
+func TestPublicKey(t *testing.T) {
+	master, err := hd.NewMaster([]byte("laplace hd key seed"))
+
+	require.NoError(t, err)
+
+	xpub := master.Neuter().String()
+
+	data := make([]byte, 32)
+
+	for _, driver := range []string{"eth", "did"} {
+		k, err := key.FromHD(driver, master)
+
+		require.NoError(t, err)
+
+		sig, err := k.Sign(data)
+
+		require.NoError(t, err)
+
+		watchOnly, err := hd.Parse(xpub)
+
+		require.NoError(t, err)
+
+		publicKey, err := key.NewPublicKey(driver, watchOnly.PubKey())
+
+		require.NoError(t, err)
+
+		require.Equal(t, k.Address(), publicKey.Address())
+		require.Equal(t, k.PubKey(), publicKey.Bytes())
+		require.True(t, publicKey.Verify(sig, data))
+
+		compressed, err := key.NewPublicKey(driver, publicKey.Compressed())
+
+		require.NoError(t, err)
+
+		require.Equal(t, publicKey.Uncompressed(), compressed.Uncompressed())
+		require.Equal(t, k.PublicKey().Address(), compressed.Address())
+
+		other, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		require.False(t, other.PublicKey().Verify(sig, data))
+	}
+
+	_, err = key.NewPublicKey("eth", []byte{1, 2, 3})
+
+	require.Error(t, err)
+}
+
+func TestBtcKey(t *testing.T) {
+	data := make([]byte, 32)
+
+	for _, driver := range []string{"btc", "btc.testnet", "btc.regtest"} {
+		k, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		require.True(t, k.Provider().ValidAddress(k.Address()))
+
+		sig, err := k.Sign(data)
+
+		require.NoError(t, err)
+
+		pubkey, err := key.Recover(driver, sig, data)
+
+		require.NoError(t, err)
+
+		require.Equal(t, k.PubKey(), pubkey)
+
+		ok, err := key.Verify(driver, pubkey, sig, data)
+
+		require.NoError(t, err)
+
+		require.True(t, ok)
+
+		address, err := key.PublicKeyToAddress(driver, pubkey)
+
+		require.NoError(t, err)
+
+		require.Equal(t, k.Address(), address)
+	}
+}
+
+func TestTaprootKey(t *testing.T) {
+	words := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	k, err := mnemonic.NewKey("taproot", words, "")
+
+	require.NoError(t, err)
+
+	require.Equal(t, "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", k.Address())
+
+	require.True(t, k.Provider().ValidAddress(k.Address()))
+
+	valid, err := key.ValidAddress("btc", k.Address())
+
+	require.NoError(t, err)
+
+	require.True(t, valid)
+
+	data := make([]byte, 32)
+
+	sig, err := k.Sign(data)
+
+	require.NoError(t, err)
+
+	require.Len(t, sig, 64)
+
+	ok, err := key.Verify("taproot", k.PubKey(), sig, data)
+
+	require.NoError(t, err)
+
+	require.True(t, ok)
+
+	publicKey, err := key.NewPublicKey("taproot", k.PublicKey().Compressed())
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), publicKey.Address())
+	require.True(t, publicKey.Verify(sig, data))
+}
+
+func TestBLSKey(t *testing.T) {
+	words := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	path, err := key.DefaultPath("bls", 3)
+
+	require.NoError(t, err)
+
+	require.Equal(t, "m/12381/3600/3/0/0", path)
+
+	k, err := mnemonic.NewKey("bls", words, "")
+
+	require.NoError(t, err)
+
+	// eip2333 instead of bip32
+	seed, err := mnemonic.Seed(words, "")
+
+	require.NoError(t, err)
+
+	sk, err := signbls.DerivePath(seed, "m/12381/3600/0/0/0")
+
+	require.NoError(t, err)
+
+	require.Equal(t, signbls.SecretKeyBytes(sk), k.PriKey())
+
+	require.Len(t, k.PubKey(), 48)
+
+	require.True(t, k.Provider().ValidAddress(k.Address()))
+
+	require.True(t, bls.PopVerify(k.PubKey(), k.(bls.Key).PopProve()))
+
+	data := make([]byte, 32)
+
+	sig, err := k.Sign(data)
+
+	require.NoError(t, err)
+
+	require.Len(t, sig, 96)
+
+	ok, err := key.Verify("bls", k.PubKey(), sig, data)
+
+	require.NoError(t, err)
+
+	require.True(t, ok)
+
+	publicKey, err := key.NewPublicKey("bls", k.PublicKey().Uncompressed())
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), publicKey.Address())
+	require.True(t, publicKey.Verify(sig, data))
+
+	// aggregation of the same hash and of distinct hashes
+	var pubkeys, sigs, hashes [][]byte
+
+	for i := 0; i < 3; i++ {
+		k, err := key.New("bls")
+
+		require.NoError(t, err)
+
+		hash := sha256.Sum256([]byte{byte(i)})
+
+		sig, err := k.Sign(data)
+
+		require.NoError(t, err)
+
+		pubkeys = append(pubkeys, k.PubKey())
+		sigs = append(sigs, sig)
+
+		sig, err = k.Sign(hash[:])
+
+		require.NoError(t, err)
+
+		hashes = append(hashes, hash[:])
+		sigs = append(sigs, sig)
+	}
+
+	aggregated, err := key.Aggregate("bls", [][]byte{sigs[0], sigs[2], sigs[4]})
+
+	require.NoError(t, err)
+
+	ok, err = key.FastAggregateVerify("bls", pubkeys, aggregated, data)
+
+	require.NoError(t, err)
+
+	require.True(t, ok)
+
+	aggregated, err = key.Aggregate("bls", [][]byte{sigs[1], sigs[3], sigs[5]})
+
+	require.NoError(t, err)
+
+	ok, err = key.AggregateVerify("bls", pubkeys, aggregated, hashes)
+
+	require.NoError(t, err)
+
+	require.True(t, ok)
+
+	ok, err = key.FastAggregateVerify("bls", pubkeys, aggregated, data)
+
+	require.NoError(t, err)
+
+	require.False(t, ok)
+
+	_, err = key.Aggregate("eth", sigs)
+
+	require.ErrorIs(t, err, key.ErrAggregate)
+}
+
+func TestEd25519Key(t *testing.T) {
+	data := []byte("hello ed25519")
+
+	for _, driver := range []string{"solana", "stellar", "near", "aptos"} {
+		k, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		require.Len(t, k.PriKey(), 32)
+		require.Len(t, k.PubKey(), 32)
+
+		require.True(t, k.Provider().ValidAddress(k.Address()), driver)
+
+		sig, err := k.Sign(data)
+
+		require.NoError(t, err)
+
+		ok, err := key.Verify(driver, k.PubKey(), sig, data)
+
+		require.NoError(t, err)
+
+		require.True(t, ok)
+
+		k2, err := key.From(driver, k)
+
+		require.NoError(t, err)
+
+		require.Equal(t, k.Address(), k2.Address())
+
+		// 64 bytes seed||pubkey private key
+		k2.SetBytes(append(k.PriKey(), k.PubKey()...))
+
+		require.Equal(t, k.Address(), k2.Address())
+
+		// truncated, padded or mismatched private keys are rejected and the previous key is kept
+		for _, invalid := range [][]byte{k.PriKey()[:20], make([]byte, 48), append(k.PriKey(), k.PriKey()...)} {
+			_, err = ed25519x.SeedFromBytes(invalid)
+
+			require.ErrorIs(t, err, ed25519x.ErrPrivateKey)
+
+			k2.SetBytes(invalid)
+
+			require.Equal(t, k.PriKey(), k2.PriKey())
+			require.Equal(t, k.Address(), k2.Address())
+			require.NotNil(t, k2.PublicKey())
+			require.Equal(t, k.PubKey(), k2.PublicKey().Bytes())
+
+			sig2, err := k2.Sign(data)
+
+			require.NoError(t, err)
+
+			require.Equal(t, sig, sig2)
+		}
+
+		publicKey, err := key.NewPublicKey(driver, k.PubKey())
+
+		require.NoError(t, err)
+
+		require.Equal(t, k.Address(), publicKey.Address())
+		require.True(t, publicKey.Verify(sig, data))
+	}
+
+	zero := make([]byte, 32)
+
+	for driver, expected := range map[string]string{
+		"solana":  "11111111111111111111111111111111",
+		"stellar": "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF",
+		"near":    "0000000000000000000000000000000000000000000000000000000000000000",
+	} {
+		address, err := key.PublicKeyToAddress(driver, zero)
+
+		require.NoError(t, err)
+
+		require.Equal(t, expected, address)
+	}
+
+	for driver, invalid := range map[string]string{
+		"solana":  "1111111111111111111111111111111",
+		"stellar": "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHG",
+		"near":    "Alice.near",
+		"aptos":   "0xzz",
+	} {
+		valid, err := key.ValidAddress(driver, invalid)
+
+		require.NoError(t, err)
+
+		require.False(t, valid, driver)
+	}
+}
+
+func TestP256Key(t *testing.T) {
+	k, err := key.New("p256")
+
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(k.Address(), "did:key:zDn"))
+
+	valid, err := key.ValidAddress("p256", k.Address())
+
+	require.NoError(t, err)
+
+	require.True(t, valid)
+
+	data := sha256.Sum256([]byte("hello p256"))
+
+	sig, err := k.Sign(data[:])
+
+	require.NoError(t, err)
+
+	require.Len(t, sig, 64)
+
+	ok, err := key.Verify("p256", k.PubKey(), sig, data[:])
+
+	require.NoError(t, err)
+
+	require.True(t, ok)
+
+	pk := k.(p256.Key)
+
+	der, err := pk.SignDER(data[:])
+
+	require.NoError(t, err)
+
+	ok, err = key.Verify("p256", k.PubKey(), der, data[:])
+
+	require.NoError(t, err)
+
+	require.True(t, ok)
+
+	recoverableSig, err := pk.SignRecoverable(data[:])
+
+	require.NoError(t, err)
+
+	pubkey, err := key.Recover("p256", recoverableSig, data[:])
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.PubKey(), pubkey)
+
+	ok, err = key.Verify("p256", nil, recoverableSig, data[:])
+
+	require.NoError(t, err)
+
+	require.True(t, ok)
+
+	// r = 0 has no inverse, the recovery fails for every v
+	zeroR := append(make([]byte, 32), recoverableSig[32:]...)
+
+	for _, v := range []byte{0, 1, 27, 28} {
+		zeroR[64] = v
+
+		_, err = key.Recover("p256", zeroR, data[:])
+
+		require.Error(t, err)
+
+		ok, err = key.Verify("p256", nil, zeroR, data[:])
+
+		require.NoError(t, err)
+
+		require.False(t, ok)
+	}
+
+	publicKey, err := key.NewPublicKey("p256", k.PublicKey().Compressed())
+
+	require.NoError(t, err)
+
+	require.Equal(t, k.Address(), publicKey.Address())
+
+	authenticatorData := []byte("authenticator data")
+	clientDataJSON := []byte(`{"type":"webauthn.get","challenge":"AAAA","origin":"https://example.com"}`)
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+
+	assertion := sha256.Sum256(append(append([]byte(nil), authenticatorData...), clientDataHash[:]...))
+
+	der, err = pk.SignDER(assertion[:])
+
+	require.NoError(t, err)
+
+	require.True(t, p256.VerifyWebAuthn(k.PubKey(), authenticatorData, clientDataJSON, der))
+
+	require.False(t, p256.VerifyWebAuthn(k.PubKey(), authenticatorData, []byte("{}"), der))
+}
+
+func TestCheckAddress(t *testing.T) {
+	require.NoError(t, key.CheckAddress("eth", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+
+	err := key.CheckAddress("eth", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+
+	require.ErrorIs(t, err, eth.ErrAddressChecksum)
+
+	valid, err := key.ValidAddress("eth", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+
+	require.NoError(t, err)
+
+	require.False(t, valid)
+
+	normalized, err := key.NormalizeAddress("eth", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+
+	require.NoError(t, err)
+
+	require.Equal(t, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", normalized)
+
+	require.ErrorIs(t, key.CheckAddress("solana", "0OIl"), key.ErrAddress)
+}
+
+func TestSignMessage(t *testing.T) {
+	message := []byte("hello world")
+
+	for _, driver := range []string{"eth", "did", "btc", "taproot", "p256", "solana", "stellar", "near", "aptos"} {
+		k, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		sig, err := key.SignMessage(k, message)
+
+		require.NoError(t, err)
+
+		ok, err := key.VerifyMessage(driver, k.PubKey(), sig, message)
+
+		require.NoError(t, err)
+
+		require.True(t, ok, driver)
+
+		ok, err = key.VerifyMessage(driver, k.PubKey(), sig, []byte("hello world!"))
+
+		require.NoError(t, err)
+
+		require.False(t, ok, driver)
+
+		hash, err := key.HashMessage(driver, message)
+
+		require.NoError(t, err)
+
+		require.True(t, k.PublicKey().Verify(sig, hash), driver)
+	}
+
+	hash, err := key.HashMessage("taproot", []byte("Hello World"))
+
+	require.NoError(t, err)
+
+	require.Equal(t, "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a", hex.EncodeToString(hash))
+
+	hash, err = key.HashMessage("eth", []byte(""))
+
+	require.NoError(t, err)
+
+	require.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", hex.EncodeToString(hash))
+}
+
+func TestCryptoSigner(t *testing.T) {
+	digest := sha256.Sum256([]byte("hello signer"))
+
+	for _, driver := range []string{"eth", "did", "btc", "p256"} {
+		k, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		signer, err := key.NewSigner(k)
+
+		require.NoError(t, err)
+
+		publicKey, ok := signer.Public().(*ecdsa.PublicKey)
+
+		require.True(t, ok, driver)
+
+		sig, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+
+		require.NoError(t, err)
+
+		require.True(t, ecdsa.VerifyASN1(publicKey, digest[:], sig), driver)
+
+		_, err = signer.Sign(rand.Reader, digest[:20], crypto.SHA256)
+
+		require.ErrorIs(t, err, key.ErrDigest)
+	}
+
+	k, err := key.New("solana")
+
+	require.NoError(t, err)
+
+	signer, err := key.NewSigner(k)
+
+	require.NoError(t, err)
+
+	message := []byte("hello signer")
+
+	sig, err := signer.Sign(rand.Reader, message, crypto.Hash(0))
+
+	require.NoError(t, err)
+
+	require.True(t, ed25519.Verify(signer.Public().(ed25519.PublicKey), message, sig))
+
+	k, err = key.New("taproot")
+
+	require.NoError(t, err)
+
+	_, err = key.NewSigner(k)
+
+	require.ErrorIs(t, err, key.ErrCryptoKey)
+}
+
+func TestFromPrivateKey(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(secp256k1.SECP256K1(), rand.Reader)
+
+	require.NoError(t, err)
+
+	ethKey, err := eth.FromPrivateKey(privateKey)
+
+	require.NoError(t, err)
+
+	didKey, err := did.FromPrivateKey(privateKey)
+
+	require.NoError(t, err)
+
+	k, err := key.New("eth")
+
+	require.NoError(t, err)
+
+	k.SetBytes(ethKey.PriKey())
+
+	require.Equal(t, k.Address(), ethKey.Address())
+
+	require.Equal(t, privateKey, ethKey.(key.CryptoKey).CryptoPrivateKey())
+
+	require.Equal(t, ethKey.PubKey(), didKey.PubKey())
+
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	require.NoError(t, err)
+
+	incomplete := &ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: secp256k1.SECP256K1()}}
+
+	for _, privateKey := range []*ecdsa.PrivateKey{nil, p256Key, {}, incomplete} {
+		_, err = eth.FromPrivateKey(privateKey)
+
+		require.ErrorIs(t, err, key.ErrPrivateKey)
+
+		_, err = did.FromPrivateKey(privateKey)
+
+		require.ErrorIs(t, err, key.ErrPrivateKey)
+	}
+}
+
+func TestCompressedPublicKey(t *testing.T) {
+	data := make([]byte, 32)
+
+	for _, driver := range []string{"eth", "did", "btc"} {
+		k, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		uncompressed := k.PublicKey().Uncompressed()
+
+		k.(key.CompressibleKey).SetCompressed(true)
+
+		pubkey := k.PubKey()
+
+		require.Len(t, pubkey, 33)
+
+		require.Equal(t, pubkey, k.PublicKey().Bytes())
+
+		address, err := key.PublicKeyToAddress(driver, pubkey)
+
+		require.NoError(t, err)
+
+		require.Equal(t, k.Address(), address)
+
+		publicKey, err := key.NewPublicKey(driver, pubkey)
+
+		require.NoError(t, err)
+
+		require.Equal(t, pubkey, publicKey.Bytes())
+
+		require.Equal(t, uncompressed, publicKey.Uncompressed())
+
+		sig, err := k.Sign(data)
+
+		require.NoError(t, err)
+
+		for _, p := range [][]byte{pubkey, uncompressed} {
+			ok, err := key.Verify(driver, p, sig, data)
+
+			require.NoError(t, err)
+
+			require.True(t, ok)
+		}
+
+		recovered, err := key.Recover(driver, sig, data)
+
+		require.NoError(t, err)
+
+		if driver == "eth" {
+			// ethereum signatures never carry the compressed flag
+			require.Equal(t, uncompressed, recovered)
+		} else {
+			require.Equal(t, pubkey, recovered)
+		}
+	}
+}
+
+func TestBatchVerify(t *testing.T) {
+	for _, driver := range []string{"eth", "did", "taproot"} {
+		var items []*key.VerifyItem
+
+		for i := 0; i < 100; i++ {
+			k, err := key.New(driver)
+
+			require.NoError(t, err)
+
+			hash := sha256.Sum256([]byte{byte(i)})
+
+			sig, err := k.Sign(hash[:])
+
+			require.NoError(t, err)
+
+			items = append(items, &key.VerifyItem{PubKey: k.PubKey(), Sig: sig, Hash: hash[:]})
+		}
+
+		results, valid, err := key.BatchVerify(driver, items)
+
+		require.NoError(t, err)
+
+		require.True(t, valid)
+
+		require.Len(t, results, len(items))
+
+		items[70].Hash = items[71].Hash
+
+		results, valid, err = key.BatchVerify(driver, items)
+
+		require.NoError(t, err)
+
+		require.False(t, valid)
+
+		for i, result := range results {
+			require.Equal(t, i != 70, result)
+		}
+	}
+
+	_, _, err := key.BatchVerify("unknown", nil)
+
+	require.Error(t, err)
+
+	_, _, err = key.BatchVerify("eth", []*key.VerifyItem{nil})
+
+	require.ErrorIs(t, err, key.ErrVerifyItem)
+
+	// recoverable providers would accept a nil public key in Verify, batch items must name the signer
+	for _, pubkey := range [][]byte{nil, {}} {
+		_, _, err = key.BatchVerify("eth", []*key.VerifyItem{{PubKey: pubkey, Sig: make([]byte, 65), Hash: make([]byte, 32)}})
+
+		require.ErrorIs(t, err, key.ErrVerifyItem)
+	}
+
+	// a panic of the provider fails the item instead of the process
+	items := []*key.VerifyItem{{PubKey: []byte{1}, Sig: []byte{1}}, {PubKey: []byte{1}}, {PubKey: []byte{1}, Sig: []byte{1}}}
+
+	results, valid, err := key.BatchVerify("test.panic", items)
+
+	require.NoError(t, err)
+
+	require.False(t, valid)
+
+	require.Equal(t, []bool{true, false, true}, results)
+}
+
+// panicProvider panics verifying the empty signature
+type panicProvider struct {
+	key.Provider
+}
+
+func (provider *panicProvider) Name() string {
+	return "test.panic"
+}
+
+func (provider *panicProvider) Verify(pubkey []byte, sig []byte, hash []byte) bool {
+	if len(sig) == 0 {
+		panic("empty signature")
+	}
+
+	return true
+}
+
+func init() {
+	key.RegisterProvider(&panicProvider{})
+}
+
+func TestSplitter(t *testing.T) {
+	for _, driver := range []string{"eth", "btc", "solana"} {
+		k, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		for _, splitter := range []string{"shamir", "slip39"} {
+			buffs := make([]*bytes.Buffer, 5)
+			writers := make([]io.Writer, 5)
+
+			for i := range buffs {
+				buffs[i] = new(bytes.Buffer)
+				writers[i] = buffs[i]
+			}
+
+			attrs := map[string]string{"threshold": "3", "passphrase": "test"}
+
+			err = key.Split(splitter, k, attrs, writers)
+
+			require.NoError(t, err)
+
+			k2, err := key.New(driver)
+
+			require.NoError(t, err)
+
+			err = key.Combine(splitter, k2, attrs, []io.Reader{
+				bytes.NewReader(buffs[4].Bytes()),
+				bytes.NewReader(buffs[0].Bytes()),
+				bytes.NewReader(buffs[2].Bytes()),
+			})
+
+			require.NoError(t, err)
+
+			require.Equal(t, k.PriKey(), k2.PriKey())
+
+			require.Equal(t, k.Address(), k2.Address())
+
+			err = key.Combine(splitter, k2, attrs, []io.Reader{
+				bytes.NewReader(buffs[4].Bytes()),
+				bytes.NewReader(buffs[0].Bytes()),
+			})
+
+			require.Error(t, err)
+		}
+	}
+}
+
+func TestShamirCombineMismatch(t *testing.T) {
+	writeShares := func(pubkey []byte, secret []byte) []io.Reader {
+		shares, err := shamir.Split(secret, 2, 2)
+
+		require.NoError(t, err)
+
+		var readers []io.Reader
+
+		for _, share := range shares {
+			buff, err := json.Marshal(map[string]interface{}{
+				"pubkey":    hex.EncodeToString(pubkey),
+				"threshold": 2,
+				"x":         share.X,
+				"y":         hex.EncodeToString(share.Y),
+			})
+
+			require.NoError(t, err)
+
+			readers = append(readers, bytes.NewReader(buff))
+		}
+
+		return readers
+	}
+
+	for _, driver := range []string{"eth", "solana"} {
+		k, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		other, err := key.New(driver)
+
+		require.NoError(t, err)
+
+		priKey, address := k.PriKey(), k.Address()
+
+		// a secret restoring another key and a wrong length secret leave the key unchanged
+		for _, readers := range [][]io.Reader{
+			writeShares(k.PublicKey().Uncompressed(), other.PriKey()),
+			writeShares(other.PublicKey().Uncompressed(), other.PriKey()[:31]),
+		} {
+			err = key.Combine("shamir", k, nil, readers)
+
+			require.ErrorIs(t, err, shamir.ErrShares)
+
+			require.Equal(t, priKey, k.PriKey())
+			require.Equal(t, address, k.Address())
+		}
+	}
+}